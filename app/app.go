@@ -0,0 +1,203 @@
+package app
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"kasir-api/config"
+	"kasir-api/config/seeder"
+	"kasir-api/handler"
+	"kasir-api/repository"
+	"kasir-api/repository/memory"
+	"kasir-api/service"
+	"kasir-api/storage"
+)
+
+// Services groups the business logic layer
+type Services struct {
+	Category    service.CategoryServiceInterface
+	Product     service.ProductServiceInterface
+	Transaction service.TransactionServiceInterface
+	Report      service.ReportServiceInterface
+	Price       service.PriceServiceInterface
+	Variant     service.VariantServiceInterface
+	Modifier    service.ModifierServiceInterface
+	Unit        service.UnitServiceInterface
+	Image       service.ImageServiceInterface
+	Promotion   service.PromotionServiceInterface
+	Voucher     service.VoucherServiceInterface
+	Tax         service.TaxServiceInterface
+}
+
+// Handlers groups the HTTP layer
+type Handlers struct {
+	Category    *handler.CategoryHandler
+	Product     *handler.ProductHandler
+	Transaction *handler.TransactionHandler
+	Report      *handler.ReportHandler
+	Price       *handler.PriceHandler
+	Variant     *handler.VariantHandler
+	Modifier    *handler.ModifierHandler
+	Unit        *handler.UnitHandler
+	Image       *handler.ImageHandler
+	Promotion   *handler.PromotionHandler
+	Voucher     *handler.VoucherHandler
+	Tax         *handler.TaxHandler
+	Uploads     http.Handler // file upload dari storage lokal, nil jika dilayani di luar aplikasi
+}
+
+// App is the application container with every layer wired together.
+// It is built from an explicit config.Config and never reads environment variables.
+type App struct {
+	Config       config.Config
+	DB           *sql.DB
+	Repositories repository.Repositories
+	TxManager    repository.TxManagerInterface
+	Services     Services
+	Handlers     Handlers
+	Router       http.Handler
+}
+
+// New builds the app on the storage backend selected by cfg.Storage
+func New(cfg config.Config) (*App, error) {
+	if cfg.Storage == config.StorageMemory {
+		return NewInMemory(cfg)
+	}
+
+	db, err := config.Connect(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.SetupDatabase(db, cfg.Migrate, cfg.Seed); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return NewWithDB(cfg, db), nil
+}
+
+// NewWithDB builds the app on top of an existing database connection
+func NewWithDB(cfg config.Config, db *sql.DB) *App {
+	// Repository Layer (Data Access with PostgreSQL/Neon or SQLite)
+	repos := repository.NewRepositories(db)
+
+	return newApp(cfg, db, repos, repository.NewTxManager(db))
+}
+
+// NewInMemory builds the app on thread-safe in-memory repositories,
+// useful for tests and offline demos. Data is lost on restart.
+func NewInMemory(cfg config.Config) (*App, error) {
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+
+	if cfg.Seed {
+		if err := seeder.SeedRepositories(repos.Category, repos.Product, repos.Price); err != nil {
+			return nil, err
+		}
+	}
+
+	return newApp(cfg, nil, repos, memory.NewTxManager(store)), nil
+}
+
+// newApp wires services, handlers and routes on top of the repositories
+func newApp(cfg config.Config, db *sql.DB, repos repository.Repositories, txManager repository.TxManagerInterface) *App {
+	// File Storage (gambar produk di filesystem lokal)
+	files := storage.NewLocalStorage(cfg.Uploads.Dir, cfg.Uploads.BaseURL)
+
+	// Service Layer (Business Logic)
+	services := Services{
+		Category:    service.NewCategoryService(repos.Category, txManager),
+		Product:     service.NewProductService(txManager, files),
+		Transaction: service.NewTransactionService(txManager),
+		Report:      service.NewReportService(repos.Transaction),
+		Price:       service.NewPriceService(txManager),
+		Variant:     service.NewVariantService(txManager),
+		Modifier:    service.NewModifierService(txManager),
+		Unit:        service.NewUnitService(txManager),
+		Image:       service.NewImageService(txManager, files),
+		Promotion:   service.NewPromotionService(txManager),
+		Voucher:     service.NewVoucherService(txManager),
+		Tax:         service.NewTaxService(txManager),
+	}
+
+	// Handler Layer (HTTP Handler/Controller)
+	auth := handler.NewAuthorizer(cfg.Auth.ManagerToken)
+	handlers := Handlers{
+		Category:    handler.NewCategoryHandler(services.Category),
+		Product:     handler.NewProductHandler(services.Product, auth),
+		Transaction: handler.NewTransactionHandler(services.Transaction, auth),
+		Report:      handler.NewReportHandler(services.Report, auth),
+		Price:       handler.NewPriceHandler(services.Price),
+		Variant:     handler.NewVariantHandler(services.Variant, auth),
+		Modifier:    handler.NewModifierHandler(services.Modifier),
+		Unit:        handler.NewUnitHandler(services.Unit),
+		Image:       handler.NewImageHandler(services.Image),
+		Promotion:   handler.NewPromotionHandler(services.Promotion, auth),
+		Voucher:     handler.NewVoucherHandler(services.Voucher, auth),
+		Tax:         handler.NewTaxHandler(services.Tax, auth),
+	}
+	// Base URL berupa path berarti file dilayani aplikasi ini, URL penuh berarti CDN atau reverse proxy
+	if strings.HasPrefix(cfg.Uploads.BaseURL, "/") {
+		handlers.Uploads = http.StripPrefix(cfg.Uploads.BaseURL, files.Handler())
+	}
+
+	return &App{
+		Config:       cfg,
+		DB:           db,
+		Repositories: repos,
+		TxManager:    txManager,
+		Services:     services,
+		Handlers:     handlers,
+		Router:       NewRouter(handlers, cfg.Uploads.BaseURL, apiInfo(cfg.Server.Port, databaseLabel(cfg.Storage))),
+	}
+}
+
+// Run starts the HTTP server
+func (a *App) Run() error {
+	printBanner(a.Config.Server.Port, databaseLabel(a.Config.Storage))
+	return http.ListenAndServe(":"+a.Config.Server.Port, a.Router)
+}
+
+// Close releases the database connection
+func (a *App) Close() error {
+	if a.DB == nil {
+		return nil
+	}
+	return a.DB.Close()
+}
+
+// databaseLabel describes the storage backend for humans
+func databaseLabel(storage string) string {
+	switch storage {
+	case config.StorageMemory:
+		return "In-Memory (offline demo)"
+	case config.StorageSQLite:
+		return "SQLite (standalone)"
+	}
+	return "PostgreSQL (Neon)"
+}
+
+// printBanner prints server info on startup
+func printBanner(port, database string) {
+	println("╔════════════════════════════════════════════════════════════╗")
+	println("║                    🚀 Kasir API Server                     ║")
+	println("╠════════════════════════════════════════════════════════════╣")
+	println("║  📍 Endpoint Utama:  http://localhost:" + port + "/                ║")
+	println("║  📖 Swagger UI:      http://localhost:" + port + "/swagger/        ║")
+	println("║  💓 Health Check:    http://localhost:" + port + "/health          ║")
+	println("╠════════════════════════════════════════════════════════════╣")
+	println(fmt.Sprintf("║  🐘 Database: %-45s║", database))
+	println("╠════════════════════════════════════════════════════════════╣")
+	println("║  Layered Architecture:                                     ║")
+	println("║    • Entity     → entity/                                  ║")
+	println("║    • Repository → repository/ (PostgreSQL)                 ║")
+	println("║    • Service    → service/                                 ║")
+	println("║    • Handler    → handler/                                 ║")
+	println("║    • App        → app/ (wiring & routes)                   ║")
+	println("╠════════════════════════════════════════════════════════════╣")
+	println("║  ⭐ Challenge: JOIN Product dengan Category!                ║")
+	println("╚════════════════════════════════════════════════════════════╝")
+}
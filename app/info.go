@@ -0,0 +1,78 @@
+package app
+
+// APIInfo represents the API information for root endpoint
+type APIInfo struct {
+	Name         string       `json:"name"`
+	Version      string       `json:"version"`
+	Description  string       `json:"description"`
+	Database     string       `json:"database"`
+	Endpoints    Endpoints    `json:"endpoints"`
+	Architecture Architecture `json:"architecture"`
+}
+
+// Endpoints represents all available endpoints
+type Endpoints struct {
+	Root         string `json:"root"`
+	Health       string `json:"health"`
+	Swagger      string `json:"swagger"`
+	Categories   string `json:"categories"`
+	Products     string `json:"products"`
+	Modifiers    string `json:"modifier_groups"`
+	Units        string `json:"units"`
+	Promotions   string `json:"promotions"`
+	Vouchers     string `json:"vouchers"`
+	TaxRates     string `json:"tax_rates"`
+	Checkout     string `json:"checkout"`
+	CartPrice    string `json:"cart_price"`
+	MarginReport string `json:"margin_report"`
+	TaxReport    string `json:"tax_report"`
+}
+
+// Architecture represents the layered architecture
+type Architecture struct {
+	Layers []Layer `json:"layers"`
+}
+
+// Layer represents a single layer
+type Layer struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+// apiInfo builds the root endpoint response for the given port and database
+func apiInfo(port, database string) APIInfo {
+	baseURL := "http://localhost:" + port
+
+	return APIInfo{
+		Name:        "Kasir API",
+		Version:     "1.0.0",
+		Description: "API Kasir dengan Layered Architecture - Week 2 Challenge",
+		Database:    database,
+		Endpoints: Endpoints{
+			Root:         baseURL + "/",
+			Health:       baseURL + "/health",
+			Swagger:      baseURL + "/swagger/",
+			Categories:   baseURL + "/api/categories",
+			Products:     baseURL + "/api/produk",
+			Modifiers:    baseURL + "/api/modifier-groups",
+			Units:        baseURL + "/api/units",
+			Promotions:   baseURL + "/api/promotions",
+			Vouchers:     baseURL + "/api/vouchers",
+			TaxRates:     baseURL + "/api/tax-rates",
+			Checkout:     baseURL + "/api/checkout",
+			CartPrice:    baseURL + "/api/cart/price",
+			MarginReport: baseURL + "/api/report/margin",
+			TaxReport:    baseURL + "/api/report/tax",
+		},
+		Architecture: Architecture{
+			Layers: []Layer{
+				{Name: "App", Path: "app/", Description: "Application container - wiring & routes"},
+				{Name: "Handler", Path: "handler/", Description: "HTTP Layer - Request/Response handling"},
+				{Name: "Service", Path: "service/", Description: "Business Logic Layer"},
+				{Name: "Repository", Path: "repository/", Description: "Data Access Layer (PostgreSQL/Neon, in-memory)"},
+				{Name: "Entity", Path: "entity/", Description: "Models/Entities"},
+			},
+		},
+	}
+}
@@ -0,0 +1,304 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+// NewRouter registers all routes on a new ServeMux. Uploaded files are served
+// under uploadsPath when h.Uploads is set.
+func NewRouter(h Handlers, uploadsPath string, info APIInfo) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// Root endpoint - Simple JSON
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(info)
+	})
+
+	// Swagger UI
+	mux.HandleFunc("/swagger/", httpSwagger.WrapHandler)
+
+	// Category Routes (Layered Architecture)
+	mux.HandleFunc("/api/categories/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			switch {
+			case r.URL.Path == "/api/categories/tree":
+				h.Category.GetCategoryTree(w, r)
+			case strings.HasSuffix(r.URL.Path, "/products"):
+				h.Product.GetProductsByCategory(w, r)
+			default:
+				h.Category.GetCategoryByID(w, r)
+			}
+		case "PUT":
+			h.Category.UpdateCategory(w, r)
+		case "DELETE":
+			h.Category.DeleteCategory(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/categories", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Category.GetAllCategories(w, r)
+		case "POST":
+			h.Category.CreateCategory(w, r)
+		}
+	})
+
+	// Product Routes (Layered Architecture dengan CHALLENGE: JOIN)
+	mux.HandleFunc("/api/produk/", func(w http.ResponseWriter, r *http.Request) {
+		// Riwayat dan jadwal harga: /api/produk/{id}/prices[/{priceID}]
+		if strings.Contains(r.URL.Path, "/prices") {
+			switch r.Method {
+			case "GET":
+				h.Price.GetPrices(w, r)
+			case "POST":
+				h.Price.SchedulePrice(w, r)
+			case "DELETE":
+				h.Price.CancelPrice(w, r)
+			}
+			return
+		}
+
+		// Varian produk: /api/produk/{id}/variants[/{variantID}[/stock]]
+		if strings.Contains(r.URL.Path, "/variants") {
+			switch r.Method {
+			case "GET":
+				h.Variant.GetVariants(w, r)
+			case "POST":
+				if strings.HasSuffix(r.URL.Path, "/stock") {
+					h.Variant.AdjustStock(w, r)
+				} else {
+					h.Variant.CreateVariant(w, r)
+				}
+			case "PUT":
+				h.Variant.UpdateVariant(w, r)
+			case "DELETE":
+				h.Variant.DeleteVariant(w, r)
+			}
+			return
+		}
+
+		// Gambar produk: /api/produk/{id}/image
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/image") {
+			switch r.Method {
+			case "POST":
+				h.Image.UploadImage(w, r)
+			case "DELETE":
+				h.Image.DeleteImage(w, r)
+			}
+			return
+		}
+
+		// Stok produk tanpa varian: /api/produk/{id}/stock
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/stock") {
+			switch r.Method {
+			case "POST":
+				h.Product.AdjustStock(w, r)
+			}
+			return
+		}
+
+		// Grup modifier yang berlaku: /api/produk/{id}/modifiers
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/modifiers") {
+			switch r.Method {
+			case "GET":
+				h.Modifier.GetProductModifiers(w, r)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			// CHALLENGE: Get Detail Product dengan Category Name (JOIN)
+			h.Product.GetProductByID(w, r)
+		case "PUT":
+			h.Product.UpdateProduct(w, r)
+		case "DELETE":
+			h.Product.DeleteProduct(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/produk", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Product.GetAllProducts(w, r)
+		case "POST":
+			h.Product.CreateProduct(w, r)
+		}
+	})
+
+	// Modifier Routes: /api/modifier-groups/{id}[/modifiers[/{modifierID}]]
+	mux.HandleFunc("/api/modifier-groups/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/modifiers") {
+			switch r.Method {
+			case "POST":
+				h.Modifier.CreateModifier(w, r)
+			case "PUT":
+				h.Modifier.UpdateModifier(w, r)
+			case "DELETE":
+				h.Modifier.DeleteModifier(w, r)
+			}
+			return
+		}
+
+		switch r.Method {
+		case "GET":
+			h.Modifier.GetGroup(w, r)
+		case "PUT":
+			h.Modifier.UpdateGroup(w, r)
+		case "DELETE":
+			h.Modifier.DeleteGroup(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/modifier-groups", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Modifier.GetGroups(w, r)
+		case "POST":
+			h.Modifier.CreateGroup(w, r)
+		}
+	})
+
+	// Unit Routes: /api/units[/{code}]
+	mux.HandleFunc("/api/units/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			h.Unit.UpdateUnit(w, r)
+		case "DELETE":
+			h.Unit.DeleteUnit(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/units", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Unit.GetUnits(w, r)
+		case "POST":
+			h.Unit.CreateUnit(w, r)
+		}
+	})
+
+	// Promotion Routes: /api/promotions[/{id}], POST/PUT/DELETE role manager
+	mux.HandleFunc("/api/promotions/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Promotion.GetPromotion(w, r)
+		case "PUT":
+			h.Promotion.UpdatePromotion(w, r)
+		case "DELETE":
+			h.Promotion.DeletePromotion(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/promotions", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Promotion.GetPromotions(w, r)
+		case "POST":
+			h.Promotion.CreatePromotion(w, r)
+		}
+	})
+
+	// Voucher Routes: /api/vouchers[/{id}] role manager, POST /api/vouchers/validate untuk kasir
+	mux.HandleFunc("/api/vouchers/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Voucher.GetVoucher(w, r)
+		case "POST":
+			if strings.TrimSuffix(r.URL.Path, "/") == "/api/vouchers/validate" {
+				h.Voucher.ValidateVoucher(w, r)
+			}
+		case "PUT":
+			h.Voucher.UpdateVoucher(w, r)
+		case "DELETE":
+			h.Voucher.DeleteVoucher(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/vouchers", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Voucher.GetVouchers(w, r)
+		case "POST":
+			h.Voucher.CreateVoucher(w, r)
+		}
+	})
+
+	// Tax Routes: /api/tax-rates[/{id}], POST/PUT/DELETE role manager
+	mux.HandleFunc("/api/tax-rates/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Tax.GetTaxRate(w, r)
+		case "PUT":
+			h.Tax.UpdateTaxRate(w, r)
+		case "DELETE":
+			h.Tax.DeleteTaxRate(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/tax-rates", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Tax.GetTaxRates(w, r)
+		case "POST":
+			h.Tax.CreateTaxRate(w, r)
+		}
+	})
+
+	// Transaction Routes
+	mux.HandleFunc("/api/checkout", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			h.Transaction.Checkout(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/cart/price", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			h.Transaction.PriceCart(w, r)
+		}
+	})
+
+	// Report Routes (role manager)
+	mux.HandleFunc("/api/report/margin", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Report.GetMarginReport(w, r)
+		}
+	})
+
+	mux.HandleFunc("/api/report/tax", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			h.Report.GetTaxReport(w, r)
+		}
+	})
+
+	// Gambar produk yang di-upload (storage lokal)
+	if h.Uploads != nil {
+		mux.Handle(uploadsPath+"/", h.Uploads)
+	}
+
+	// Health check
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":  "OK",
+			"message": "API Running with " + info.Database,
+		})
+	})
+
+	return mux
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"kasir-api/config"
+	"kasir-api/config/migration"
+	"kasir-api/config/seeder"
+)
+
+// runCommand runs a CLI subcommand
+func runCommand(name string, args []string) error {
+	switch name {
+	case "seed":
+		return runSeedCommand(args)
+	}
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// runSeedCommand handles `seed [--fake] [--taxes] [--products N] ...`
+func runSeedCommand(args []string) error {
+	defaults := seeder.DefaultFakeOptions()
+
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fake := fs.Bool("fake", false, "generate a fake dataset instead of the default seed data")
+	clear := fs.Bool("clear", false, "clear all data before seeding")
+	taxes := fs.Bool("taxes", false, "also add example tax rates (service charge 5%, PPN 11%) if none exist")
+	categories := fs.Int("categories", defaults.Categories, "number of fake categories")
+	products := fs.Int("products", defaults.Products, "number of fake products")
+	transactions := fs.Int("transactions", defaults.Transactions, "number of fake historical transactions")
+	seed := fs.Int64("seed", defaults.Seed, "random seed, the same seed generates the same dataset")
+	days := fs.Int("days", defaults.Days, "spread transactions over the last N days")
+	until := fs.String("until", defaults.Until.Format("2006-01-02"), "last day of generated transactions (YYYY-MM-DD)")
+	batch := fs.Int("batch", defaults.BatchSize, "rows per INSERT statement")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	untilDate, err := time.Parse("2006-01-02", *until)
+	if err != nil {
+		return fmt.Errorf("invalid --until date: %w", err)
+	}
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		return err
+	}
+	// Sama seperti app.New: data memory hanya hidup di dalam proses server
+	if cfg.Storage == config.StorageMemory {
+		return fmt.Errorf("seed needs a database, STORAGE=memory is seeded by the server on start (DB_SEED)")
+	}
+	db, err := config.Connect(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := migration.Migrate(db); err != nil {
+		return err
+	}
+
+	if *clear {
+		if err := seeder.Clear(db); err != nil {
+			return err
+		}
+	}
+
+	if *taxes {
+		if err := seeder.SeedTaxRates(db); err != nil {
+			return err
+		}
+	}
+
+	if !*fake {
+		return seeder.NewSeeder(db).Run()
+	}
+
+	maxBindParams := seeder.PostgresMaxBindParams
+	if cfg.Storage == config.StorageSQLite {
+		maxBindParams = seeder.SQLiteMaxBindParams
+	}
+	return seeder.SeedFake(db, seeder.FakeOptions{
+		Categories:    *categories,
+		Products:      *products,
+		Transactions:  *transactions,
+		Seed:          *seed,
+		Days:          *days,
+		Until:         untilDate.AddDate(0, 0, 1),
+		BatchSize:     *batch,
+		MaxBindParams: maxBindParams,
+	})
+}
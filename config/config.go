@@ -0,0 +1,254 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"kasir-api/repository/sqlite"
+
+	"github.com/joho/godotenv"
+)
+
+// Storage backends
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+// Config is the typed application configuration
+type Config struct {
+	Server   ServerConfig
+	Storage  string // postgres (default), sqlite untuk satu kasir, atau memory untuk demo offline
+	Database DBConfig
+	Migrate  bool // jalankan migrasi saat start
+	Seed     bool // jalankan seeder default saat start
+	Auth     AuthConfig
+	Uploads  UploadConfig
+}
+
+// UploadConfig holds where uploaded files (product images) are stored and served from
+type UploadConfig struct {
+	Dir     string // direktori lokal untuk file upload
+	BaseURL string // prefix URL publik; path seperti /uploads dilayani aplikasi ini, URL penuh untuk CDN
+}
+
+// AuthConfig holds role settings. Requests without a valid token are treated as cashier.
+type AuthConfig struct {
+	ManagerToken string // Bearer token untuk role manager, kosong berarti tidak ada manager
+}
+
+// ServerConfig holds HTTP server settings
+type ServerConfig struct {
+	Port string
+}
+
+// Default returns the configuration used when nothing is set
+func Default() Config {
+	return Config{
+		Server:   ServerConfig{Port: "8080"},
+		Storage:  StoragePostgres,
+		Database: DefaultDBConfig(),
+		Migrate:  true,
+		Seed:     true,
+		Uploads:  UploadConfig{Dir: "uploads", BaseURL: "/uploads"},
+	}
+}
+
+// Load builds the configuration from defaults, the .env file, environment
+// variables and command line flags, in increasing order of precedence.
+// Variables already present in the environment are never overridden by .env.
+func Load(args []string) (Config, error) {
+	fs := flag.NewFlagSet("kasir-api", flag.ContinueOnError)
+	envFile := fs.String("env-file", ".env", "path to .env file")
+	port := fs.String("port", "", "HTTP port (overrides PORT / SERVER_PORT)")
+	storage := fs.String("storage", "", "storage backend: postgres, sqlite or memory (overrides STORAGE)")
+	databaseURL := fs.String("database-url", "", "database connection string (overrides DATABASE_URL)")
+	migrate := fs.String("migrate", "", "run migrations on startup (true/false)")
+	seed := fs.String("seed", "", "run default seeders on startup (true/false)")
+	uploadDir := fs.String("upload-dir", "", "directory for uploaded product images (overrides UPLOAD_DIR)")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if err := godotenv.Load(*envFile); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return Config{}, fmt.Errorf("failed to load %s: %w", *envFile, err)
+		}
+		println("⚠️  Warning: .env file not found, using environment variables")
+	}
+
+	cfg, err := FromEnv()
+	if err != nil {
+		return Config{}, err
+	}
+
+	// Flags
+	if *port != "" {
+		cfg.Server.Port = *port
+	}
+	if *storage != "" {
+		cfg.Storage = *storage
+	}
+	if *databaseURL != "" {
+		cfg.Database.URL = *databaseURL
+		if *storage == "" && cfg.Storage != StorageMemory {
+			cfg.Storage = storageForURL(cfg.Database.URL)
+		}
+	}
+	if *migrate != "" {
+		if cfg.Migrate, err = strconv.ParseBool(*migrate); err != nil {
+			return Config{}, fmt.Errorf("--migrate must be true or false: %w", err)
+		}
+	}
+	if *seed != "" {
+		if cfg.Seed, err = strconv.ParseBool(*seed); err != nil {
+			return Config{}, fmt.Errorf("--seed must be true or false: %w", err)
+		}
+	}
+
+	if *uploadDir != "" {
+		cfg.Uploads.Dir = *uploadDir
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// FromEnv reads the configuration from environment variables on top of defaults
+func FromEnv() (Config, error) {
+	cfg := Default()
+
+	// PORT di-set oleh platform (Fly.io), SERVER_PORT untuk development lokal
+	if port := os.Getenv("PORT"); port != "" {
+		cfg.Server.Port = port
+	} else if port := os.Getenv("SERVER_PORT"); port != "" {
+		cfg.Server.Port = port
+	}
+
+	db, err := DBConfigFromEnv()
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Database = db
+
+	// Backend dipilih dari skema DATABASE_URL kecuali STORAGE di-set
+	cfg.Storage = storageForURL(db.URL)
+	if storage := os.Getenv("STORAGE"); storage != "" {
+		cfg.Storage = storage
+	}
+
+	if cfg.Migrate, err = envBool("DB_MIGRATE", cfg.Migrate); err != nil {
+		return cfg, err
+	}
+	if cfg.Seed, err = envBool("DB_SEED", cfg.Seed); err != nil {
+		return cfg, err
+	}
+	cfg.Auth.ManagerToken = os.Getenv("MANAGER_TOKEN")
+	if dir := os.Getenv("UPLOAD_DIR"); dir != "" {
+		cfg.Uploads.Dir = dir
+	}
+	if baseURL := os.Getenv("UPLOAD_BASE_URL"); baseURL != "" {
+		cfg.Uploads.BaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+	return cfg, nil
+}
+
+// storageForURL picks the SQL backend from the DATABASE_URL scheme
+func storageForURL(databaseURL string) string {
+	if sqlite.IsURL(databaseURL) {
+		return StorageSQLite
+	}
+	return StoragePostgres
+}
+
+// Validate checks the configuration and reports every problem at once
+func (c Config) Validate() error {
+	var problems []string
+
+	if n, err := strconv.Atoi(c.Server.Port); err != nil || n < 1 || n > 65535 {
+		problems = append(problems, fmt.Sprintf("port %q must be a number between 1 and 65535", c.Server.Port))
+	}
+
+	switch c.Storage {
+	case StoragePostgres:
+		if c.Database.URL == "" {
+			problems = append(problems, "DATABASE_URL is required")
+		} else if sqlite.IsURL(c.Database.URL) {
+			problems = append(problems, "DATABASE_URL points at SQLite but STORAGE is postgres")
+		} else if _, err := parseDSN(c.Database.URL); err != nil {
+			problems = append(problems, err.Error())
+		}
+	case StorageSQLite:
+		if !sqlite.IsURL(c.Database.URL) {
+			problems = append(problems, "STORAGE=sqlite requires DATABASE_URL like sqlite://data/kasir.db")
+		}
+	case StorageMemory:
+		// Tidak butuh database
+	default:
+		problems = append(problems, fmt.Sprintf("STORAGE %q must be %s, %s or %s", c.Storage, StoragePostgres, StorageSQLite, StorageMemory))
+	}
+	if c.Database.MaxOpenConns < 0 {
+		problems = append(problems, "DB_MAX_OPEN_CONNS must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		problems = append(problems, "DB_MAX_IDLE_CONNS must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.ConnectRetries < 0 {
+		problems = append(problems, "DB_CONNECT_RETRIES must not be negative")
+	}
+	if c.Database.RetryBackoff <= 0 {
+		problems = append(problems, "DB_CONNECT_BACKOFF must be positive")
+	}
+
+	if c.Uploads.Dir == "" {
+		problems = append(problems, "UPLOAD_DIR must not be empty")
+	}
+	if b := c.Uploads.BaseURL; !strings.HasPrefix(b, "/") && !strings.HasPrefix(b, "http://") && !strings.HasPrefix(b, "https://") {
+		problems = append(problems, fmt.Sprintf("UPLOAD_BASE_URL %q must be a path like /uploads or an http(s) URL", b))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// String returns the configuration with secrets redacted, safe for logging
+func (c Config) String() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "⚙️  Configuration:")
+	fmt.Fprintf(&b, "  PORT                  = %s\n", c.Server.Port)
+	fmt.Fprintf(&b, "  STORAGE               = %s\n", c.Storage)
+	fmt.Fprintf(&b, "  DATABASE_URL          = %s\n", redactDSN(c.Database.URL))
+	fmt.Fprintf(&b, "  DB_MAX_OPEN_CONNS     = %d\n", c.Database.MaxOpenConns)
+	fmt.Fprintf(&b, "  DB_MAX_IDLE_CONNS     = %d\n", c.Database.MaxIdleConns)
+	fmt.Fprintf(&b, "  DB_CONN_MAX_LIFETIME  = %s\n", c.Database.ConnMaxLifetime)
+	fmt.Fprintf(&b, "  DB_CONN_MAX_IDLE_TIME = %s\n", c.Database.ConnMaxIdleTime)
+	fmt.Fprintf(&b, "  DB_CONNECT_RETRIES    = %d\n", c.Database.ConnectRetries)
+	fmt.Fprintf(&b, "  DB_CONNECT_BACKOFF    = %s\n", c.Database.RetryBackoff)
+	fmt.Fprintf(&b, "  DB_AUTO_CREATE        = %t\n", c.Database.AutoCreate)
+	fmt.Fprintf(&b, "  DB_MIGRATE            = %t\n", c.Migrate)
+	fmt.Fprintf(&b, "  DB_SEED               = %t\n", c.Seed)
+	fmt.Fprintf(&b, "  UPLOAD_DIR            = %s\n", c.Uploads.Dir)
+	fmt.Fprintf(&b, "  UPLOAD_BASE_URL       = %s\n", c.Uploads.BaseURL)
+	fmt.Fprintf(&b, "  MANAGER_TOKEN         = %s", redactSecret(c.Auth.ManagerToken))
+	return b.String()
+}
+
+// redactSecret hides a secret value but shows whether it is set
+func redactSecret(secret string) string {
+	if secret == "" {
+		return "(not set)"
+	}
+	return "****"
+}
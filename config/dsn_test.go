@@ -0,0 +1,41 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  string
+		want map[string]string
+	}{
+		{"spaces", "host=localhost dbname=kasir_api", map[string]string{"host": "localhost", "dbname": "kasir_api"}},
+		{"tabs and newlines", "host=db\tdbname=kasir\r\nuser=kasir", map[string]string{"host": "db", "dbname": "kasir", "user": "kasir"}},
+		{"space around equals", "host = db\tsslmode =\tdisable", map[string]string{"host": "db", "sslmode": "disable"}},
+		{"quoted value", `password='p a\'ss' dbname=kasir`, map[string]string{"password": "p a'ss", "dbname": "kasir"}},
+		{"url", "postgres://kasir:se%2Fcret@db:5432/kasir_api?sslmode=require", map[string]string{
+			"user": "kasir", "password": "se/cret", "host": "db", "port": "5432", "dbname": "kasir_api", "sslmode": "require",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDSN(tt.dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseDSN(%q) = %v, want %v", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDSNInvalid(t *testing.T) {
+	for _, dsn := range []string{"host", "host\tdbname=kasir", "password='open"} {
+		if _, err := parseDSN(dsn); err == nil {
+			t.Errorf("parseDSN(%q) should fail", dsn)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+-- Migration: Create transactions and transaction_details tables
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id SERIAL PRIMARY KEY,
+    total_amount INTEGER NOT NULL CHECK (total_amount >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS transaction_details (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    product_id INTEGER REFERENCES products(id) ON DELETE SET NULL,
+    nama_produk VARCHAR(100) NOT NULL,
+    harga INTEGER NOT NULL CHECK (harga >= 0),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    subtotal INTEGER NOT NULL CHECK (subtotal >= 0)
+);
+
+-- Create indexes for reporting queries
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_details_transaction_id ON transaction_details(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_transaction_details_product_id ON transaction_details(product_id);
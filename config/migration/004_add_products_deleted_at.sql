@@ -0,0 +1,8 @@
+-- Migration: Soft delete for products
+-- Created at: 2026-10-19
+-- Produk yang dihapus lewat kategori (mode=cascade) tetap disimpan agar
+-- riwayat transaksi tidak kehilangan referensi produk
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products(deleted_at);
@@ -0,0 +1,11 @@
+-- Migration: Hierarchical categories (sub-categories)
+-- Created at: 2026-10-19
+-- parent_id NULL berarti kategori root. Siklus (A > B > A) dicegah di service,
+-- database hanya menolak kategori yang menjadi parent dirinya sendiri
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_parent_not_self;
+ALTER TABLE categories ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
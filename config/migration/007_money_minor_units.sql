@@ -0,0 +1,17 @@
+-- Migration: Money in minor units with currency
+-- Created at: 2026-10-19
+-- Semua kolom uang disimpan dalam minor unit (1/100 rupiah) sebagai BIGINT agar
+-- harga seperti Rp 1.250,50 bisa disimpan tanpa float. Nilai lama dikali 100.
+
+ALTER TABLE products
+    ALTER COLUMN harga TYPE BIGINT USING harga::BIGINT * 100,
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE transactions
+    ALTER COLUMN total_amount TYPE BIGINT USING total_amount::BIGINT * 100,
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- Mata uang detail mengikuti transaksinya
+ALTER TABLE transaction_details
+    ALTER COLUMN harga TYPE BIGINT USING harga::BIGINT * 100,
+    ALTER COLUMN subtotal TYPE BIGINT USING subtotal::BIGINT * 100;
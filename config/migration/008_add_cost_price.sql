@@ -0,0 +1,11 @@
+-- Migration: Cost price (harga beli) and cost snapshot per sale line
+-- Created at: 2026-10-19
+-- harga_beli memakai minor unit dan mata uang yang sama dengan harga jual.
+-- NULL berarti harga beli belum diketahui. Detail transaksi menyimpan salinan
+-- harga beli saat penjualan agar margin historis tidak berubah.
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS harga_beli BIGINT CHECK (harga_beli >= 0);
+
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS harga_beli BIGINT CHECK (harga_beli >= 0);
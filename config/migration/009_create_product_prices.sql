@@ -0,0 +1,26 @@
+-- Migration: Product price history and scheduled price changes
+-- Created at: 2026-10-19
+-- Setiap baris berlaku pada [effective_from, effective_to), effective_to NULL untuk harga
+-- terakhir. Waktu disimpan dalam UTC. products.harga tetap ada sebagai harga dasar untuk
+-- produk tanpa riwayat harga.
+
+CREATE TABLE IF NOT EXISTS product_prices (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    harga BIGINT NOT NULL CHECK (harga >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    effective_from TIMESTAMP NOT NULL,
+    effective_to TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_prices_product_from ON product_prices(product_id, effective_from);
+
+-- Harga saat ini menjadi riwayat pertama, berlaku sejak produk dibuat (created_at disimpan
+-- dalam zona waktu server, dikonversi ke UTC)
+INSERT INTO product_prices (product_id, harga, currency, effective_from)
+SELECT id, harga, currency,
+       COALESCE(created_at AT TIME ZONE current_setting('TimeZone') AT TIME ZONE 'UTC', CURRENT_TIMESTAMP AT TIME ZONE 'UTC')
+FROM products
+WHERE NOT EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = products.id);
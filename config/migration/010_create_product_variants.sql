@@ -0,0 +1,23 @@
+-- Migration: Product variants (size, flavor) with per-variant SKU, price and stock
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    nama VARCHAR(100) NOT NULL,
+    options JSONB NOT NULL DEFAULT '{}',
+    harga BIGINT NOT NULL CHECK (harga >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- SKU unik di seluruh toko, nama varian unik per produk (case-insensitive)
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_sku ON product_variants (LOWER(sku));
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_product_nama ON product_variants (product_id, LOWER(nama));
+
+-- Varian yang terjual, NULL untuk produk tanpa varian atau varian yang sudah dihapus
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
@@ -0,0 +1,23 @@
+-- Migration: Bundle products (Paket Hemat) and product stock
+-- Created at: 2026-10-19
+-- Produk bundle dijual dengan harga sendiri dan tersusun dari produk lain.
+-- stock NULL berarti stok produk tidak dilacak.
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS type VARCHAR(10) NOT NULL DEFAULT 'single' CHECK (type IN ('single', 'bundle'));
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS stock INTEGER CHECK (stock >= 0);
+
+-- Komponen bundle. Produk atau varian yang masih menjadi komponen tidak bisa dihapus.
+CREATE TABLE IF NOT EXISTS bundle_components (
+    id SERIAL PRIMARY KEY,
+    bundle_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    CHECK (product_id <> bundle_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bundle_components ON bundle_components (bundle_id, product_id, COALESCE(variant_id, 0));
+CREATE INDEX IF NOT EXISTS idx_bundle_components_product_id ON bundle_components (product_id);
@@ -0,0 +1,46 @@
+-- Migration: Units of measure, unit conversions and decimal quantities
+-- Created at: 2026-10-19
+-- Jumlah barang disimpan dalam seperseribu unit sebagai BIGINT agar 0,5 kg gula
+-- bisa dijual tanpa float. Nilai lama dikali 1000.
+
+CREATE TABLE IF NOT EXISTS units (
+    code VARCHAR(10) PRIMARY KEY CHECK (code = LOWER(code)),
+    nama VARCHAR(50) NOT NULL,
+    allow_decimal BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+INSERT INTO units (code, nama, allow_decimal) VALUES
+    ('pcs', 'Pieces', FALSE),
+    ('dus', 'Dus', FALSE),
+    ('pak', 'Pak', FALSE),
+    ('kg', 'Kilogram', TRUE),
+    ('g', 'Gram', TRUE),
+    ('l', 'Liter', TRUE),
+    ('ml', 'Mililiter', TRUE)
+ON CONFLICT (code) DO NOTHING;
+
+-- Harga dan stok produk dalam satuan dasar, satuan beli untuk restock
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS unit VARCHAR(10) NOT NULL DEFAULT 'pcs' REFERENCES units(code) ON DELETE RESTRICT,
+    ADD COLUMN IF NOT EXISTS purchase_unit VARCHAR(10) REFERENCES units(code) ON DELETE RESTRICT,
+    ALTER COLUMN stock TYPE BIGINT USING stock::BIGINT * 1000;
+
+-- Satuan lain per produk: 1 unit = factor/1000 satuan dasar, misal 1 dus = 24 pcs (factor 24000)
+CREATE TABLE IF NOT EXISTS product_units (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    unit VARCHAR(10) NOT NULL REFERENCES units(code) ON DELETE RESTRICT,
+    factor BIGINT NOT NULL CHECK (factor > 0),
+    PRIMARY KEY (product_id, unit)
+);
+
+-- Baris penjualan menyimpan satuan jualnya dan jumlah dalam satuan dasar untuk laporan
+ALTER TABLE transaction_details
+    ALTER COLUMN quantity TYPE BIGINT USING quantity::BIGINT * 1000,
+    ADD COLUMN IF NOT EXISTS unit VARCHAR(10) NOT NULL DEFAULT 'pcs',
+    ADD COLUMN IF NOT EXISTS base_quantity BIGINT;
+
+UPDATE transaction_details SET base_quantity = quantity WHERE base_quantity IS NULL;
+
+ALTER TABLE transaction_details
+    ALTER COLUMN base_quantity SET NOT NULL,
+    ADD CONSTRAINT transaction_details_base_quantity_check CHECK (base_quantity > 0);
@@ -0,0 +1,9 @@
+-- Migration: Product images for touchscreen POS tiles
+-- Created at: 2026-10-19
+-- Hanya lokasi file di storage (key) yang disimpan, URL dibentuk dari storage
+-- yang dipakai sehingga pindah ke storage lain tidak perlu mengubah data.
+-- NULL berarti produk belum punya gambar.
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS image_key VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS thumbnail_key VARCHAR(255);
@@ -0,0 +1,58 @@
+-- Migration: Promotions applied automatically when a cart is priced
+-- Created at: 2026-10-19
+-- percent dalam basis poin (10% = 1000). days adalah bitmask hari berlaku,
+-- bit 0 = Minggu sampai bit 6 = Sabtu, 0 = setiap hari. Waktu disimpan dalam UTC.
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percentage', 'fixed', 'bogo', 'threshold')),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('product', 'category', 'cart')),
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    percent INTEGER CHECK (percent > 0 AND percent <= 10000),
+    amount BIGINT CHECK (amount > 0),
+    min_subtotal BIGINT CHECK (min_subtotal >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    buy_quantity INTEGER CHECK (buy_quantity > 0),
+    get_quantity INTEGER CHECK (get_quantity > 0),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    days INTEGER NOT NULL DEFAULT 0 CHECK (days >= 0 AND days < 128),
+    priority INTEGER NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((scope = 'product') = (product_id IS NOT NULL)),
+    CHECK ((scope = 'category') = (category_id IS NOT NULL)),
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_product_id ON promotions (product_id);
+CREATE INDEX IF NOT EXISTS idx_promotions_category_id ON promotions (category_id);
+
+-- Subtotal sebelum diskon; transaksi lama tidak punya diskon
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS subtotal BIGINT,
+    ADD COLUMN IF NOT EXISTS discount_amount BIGINT NOT NULL DEFAULT 0 CHECK (discount_amount >= 0);
+
+UPDATE transactions SET subtotal = total_amount WHERE subtotal IS NULL;
+
+ALTER TABLE transactions
+    ALTER COLUMN subtotal SET NOT NULL,
+    ADD CONSTRAINT transactions_subtotal_check CHECK (subtotal >= 0);
+
+-- Bagian setiap baris dari semua diskon, pendapatan bersih = subtotal - discount
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS discount BIGINT NOT NULL DEFAULT 0 CHECK (discount >= 0 AND discount <= subtotal);
+
+-- Salinan promosi yang dipakai per transaksi, promotion_id NULL jika promosi dihapus
+CREATE TABLE IF NOT EXISTS transaction_discounts (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    promotion_id INTEGER REFERENCES promotions(id) ON DELETE SET NULL,
+    nama VARCHAR(100) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_discounts_transaction_id ON transaction_discounts (transaction_id);
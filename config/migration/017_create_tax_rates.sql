@@ -0,0 +1,51 @@
+-- Migration: Tax rates (PPN, service charge) with per-category exemptions
+-- Created at: 2026-10-19
+-- Pajak dihitung dari harga setelah diskon, urut berdasarkan position. Pajak inclusive
+-- sudah termasuk dalam harga jual, pajak exclusive ditambahkan ke total transaksi.
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id SERIAL PRIMARY KEY,
+    nama VARCHAR(100) NOT NULL UNIQUE,
+    rate INTEGER NOT NULL CHECK (rate > 0 AND rate <= 10000),
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    compound BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (NOT (inclusive AND compound))
+);
+
+-- Kategori yang bebas pajak, berlaku juga untuk sub-kategorinya
+CREATE TABLE IF NOT EXISTS tax_rate_exemptions (
+    tax_rate_id INTEGER NOT NULL REFERENCES tax_rates(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (tax_rate_id, category_id)
+);
+
+-- Tarif bawaan restoran: service charge 5%, lalu PPN 11% atas harga + service charge
+INSERT INTO tax_rates (nama, rate, inclusive, compound, position) VALUES
+    ('Service Charge 5%', 500, FALSE, FALSE, 1),
+    ('PPN 11%', 1100, FALSE, TRUE, 2)
+ON CONFLICT (nama) DO NOTHING;
+
+-- Pajak exclusive yang ditambahkan, total_amount = subtotal - discount_amount + tax_amount
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS tax_amount BIGINT NOT NULL DEFAULT 0 CHECK (tax_amount >= 0);
+
+-- Bagian baris dari pajak inclusive, pendapatan tanpa pajak = subtotal - discount - tax_included
+ALTER TABLE transaction_details
+    ADD COLUMN IF NOT EXISTS tax_included BIGINT NOT NULL DEFAULT 0 CHECK (tax_included >= 0);
+
+-- Salinan pajak per transaksi untuk laporan, tax_rate_id NULL jika tarif dihapus
+CREATE TABLE IF NOT EXISTS transaction_taxes (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL,
+    nama VARCHAR(100) NOT NULL,
+    rate INTEGER NOT NULL CHECK (rate > 0),
+    inclusive BOOLEAN NOT NULL,
+    taxable_amount BIGINT NOT NULL CHECK (taxable_amount >= 0),
+    amount BIGINT NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_taxes_transaction_id ON transaction_taxes (transaction_id);
@@ -0,0 +1,7 @@
+-- Migration: Cost price (harga beli) per product variant
+-- Created at: 2026-10-19
+-- Varian punya harga jual sendiri, jadi harga pokoknya juga bisa berbeda dari produk.
+-- NULL berarti harga beli varian belum diketahui; penjualannya tidak ikut dihitung margin.
+
+ALTER TABLE product_variants
+    ADD COLUMN IF NOT EXISTS harga_beli BIGINT CHECK (harga_beli >= 0);
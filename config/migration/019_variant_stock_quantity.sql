@@ -0,0 +1,7 @@
+-- Migration: Variant stock in thousandths of a unit
+-- Created at: 2026-10-19
+-- Stok varian mengikuti stok produk (013): seperseribu satuan dasar sebagai BIGINT agar
+-- varian dengan satuan desimal (misal kg) bisa dijual 0,5. Nilai lama dikali 1000.
+
+ALTER TABLE product_variants
+    ALTER COLUMN stock TYPE BIGINT USING stock::BIGINT * 1000;
@@ -4,15 +4,32 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
+	"io/fs"
 	"log"
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"kasir-api/repository/sqlite"
 )
 
 //go:embed *.sql
 var migrationFiles embed.FS
 
+// SQLite has its own migration set (AUTOINCREMENT instead of SERIAL)
+//
+//go:embed sqlite/*.sql
+var sqliteMigrationFiles embed.FS
+
+// filesFor returns the migration set matching the database driver
+func filesFor(db *sql.DB) fs.FS {
+	if sqlite.IsSQLite(db) {
+		sub, _ := fs.Sub(sqliteMigrationFiles, "sqlite")
+		return sub
+	}
+	return migrationFiles
+}
+
 // Migration represents a single migration
 type Migration struct {
 	Version string
@@ -36,7 +53,8 @@ func Migrate(db *sql.DB) error {
 	}
 
 	// Get list of migration files
-	files, err := migrationFiles.ReadDir(".")
+	migrations := filesFor(db)
+	files, err := fs.ReadDir(migrations, ".")
 	if err != nil {
 		return fmt.Errorf("failed to read migration files: %w", err)
 	}
@@ -67,7 +85,7 @@ func Migrate(db *sql.DB) error {
 		}
 
 		// Read migration file
-		content, err := migrationFiles.ReadFile(file.Name())
+		content, err := fs.ReadFile(migrations, file.Name())
 		if err != nil {
 			return fmt.Errorf("failed to read migration file %s: %w", file.Name(), err)
 		}
@@ -119,7 +137,7 @@ func Rollback(db *sql.DB) error {
 
 	// Read migration file for rollback SQL (if exists)
 	rollbackFile := version + "_rollback.sql"
-	content, err := migrationFiles.ReadFile(rollbackFile)
+	content, err := fs.ReadFile(filesFor(db), rollbackFile)
 	if err != nil {
 		// If no rollback file, just delete the migration record
 		_, err = db.Exec("DELETE FROM schema_migrations WHERE version = $1", version)
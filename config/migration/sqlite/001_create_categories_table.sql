@@ -0,0 +1,13 @@
+-- Migration: Create categories table (SQLite)
+-- Created at: 2026-02-01
+
+CREATE TABLE IF NOT EXISTS categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL CHECK (length(name) <= 100),
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for faster lookup
+CREATE INDEX IF NOT EXISTS idx_categories_name ON categories(name);
@@ -0,0 +1,6 @@
+-- Migration: Soft delete for products (SQLite)
+-- Created at: 2026-10-19
+
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products(deleted_at);
@@ -0,0 +1,6 @@
+-- Migration: Hierarchical categories (SQLite)
+-- Created at: 2026-10-19
+
+ALTER TABLE categories ADD COLUMN parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
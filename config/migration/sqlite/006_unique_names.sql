@@ -0,0 +1,36 @@
+-- Migration: Case-insensitive unique names (SQLite)
+-- Created at: 2026-10-19
+-- LOWER() SQLite hanya mengenal huruf ASCII
+--
+-- PERHATIAN: migrasi ini MENGUBAH data. Duplikat yang sudah ada (selain yang ID-nya
+-- terkecil) diganti namanya menjadi "nama (id)" tanpa laporan. Cari yang diganti dengan:
+--   SELECT id, name FROM categories WHERE name LIKE '% (' || id || ')';
+--   SELECT id, nama FROM products WHERE nama LIKE '% (' || id || ')';
+
+UPDATE categories
+SET name = substr(name, 1, 88) || ' (' || id || ')'
+WHERE EXISTS (
+    SELECT 1 FROM categories older
+    WHERE COALESCE(older.parent_id, 0) = COALESCE(categories.parent_id, 0)
+      AND LOWER(older.name) = LOWER(categories.name)
+      AND older.id < categories.id
+);
+
+UPDATE products
+SET nama = substr(nama, 1, 88) || ' (' || id || ')'
+WHERE deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM products older
+    WHERE COALESCE(older.category_id, 0) = COALESCE(products.category_id, 0)
+      AND LOWER(older.nama) = LOWER(products.nama)
+      AND older.deleted_at IS NULL
+      AND older.id < products.id
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_categories_parent_name
+    ON categories (COALESCE(parent_id, 0), LOWER(name));
+
+-- Produk yang sudah di-soft delete tidak menghalangi nama baru
+CREATE UNIQUE INDEX IF NOT EXISTS uq_products_category_nama
+    ON products (COALESCE(category_id, 0), LOWER(nama))
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+-- Migration: Money in minor units with currency (SQLite)
+-- Created at: 2026-10-19
+-- INTEGER SQLite sudah 64-bit, cukup konversi nilai lama ke minor unit
+
+UPDATE products SET harga = harga * 100;
+ALTER TABLE products ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+UPDATE transactions SET total_amount = total_amount * 100;
+ALTER TABLE transactions ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+UPDATE transaction_details SET harga = harga * 100, subtotal = subtotal * 100;
@@ -0,0 +1,6 @@
+-- Migration: Cost price (harga beli) and cost snapshot per sale line (SQLite)
+-- Created at: 2026-10-19
+
+ALTER TABLE products ADD COLUMN harga_beli INTEGER CHECK (harga_beli >= 0);
+
+ALTER TABLE transaction_details ADD COLUMN harga_beli INTEGER CHECK (harga_beli >= 0);
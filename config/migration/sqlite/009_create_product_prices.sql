@@ -0,0 +1,21 @@
+-- Migration: Product price history and scheduled price changes (SQLite)
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS product_prices (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    harga INTEGER NOT NULL CHECK (harga >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    effective_from TIMESTAMP NOT NULL,
+    effective_to TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_prices_product_from ON product_prices(product_id, effective_from);
+
+-- CURRENT_TIMESTAMP SQLite sudah UTC
+INSERT INTO product_prices (product_id, harga, currency, effective_from)
+SELECT id, harga, currency, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM products
+WHERE NOT EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = products.id);
@@ -0,0 +1,20 @@
+-- Migration: Product variants (size, flavor) with per-variant SKU, price and stock (SQLite)
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    nama VARCHAR(100) NOT NULL,
+    options TEXT NOT NULL DEFAULT '{}',
+    harga INTEGER NOT NULL CHECK (harga >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_sku ON product_variants (LOWER(sku));
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_product_nama ON product_variants (product_id, LOWER(nama));
+
+ALTER TABLE transaction_details ADD COLUMN variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
@@ -0,0 +1,40 @@
+-- Migration: Modifier groups and priced add-ons for sale lines (SQLite)
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nama VARCHAR(100) NOT NULL,
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER NOT NULL DEFAULT 0 CHECK (max_select = 0 OR max_select >= min_select),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((product_id IS NULL) <> (category_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_modifier_groups_product_id ON modifier_groups (product_id);
+CREATE INDEX IF NOT EXISTS idx_modifier_groups_category_id ON modifier_groups (category_id);
+
+CREATE TABLE IF NOT EXISTS modifiers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_id INTEGER NOT NULL REFERENCES modifier_groups(id) ON DELETE CASCADE,
+    nama VARCHAR(100) NOT NULL,
+    harga INTEGER NOT NULL DEFAULT 0 CHECK (harga >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_modifiers_group_nama ON modifiers (group_id, LOWER(nama));
+
+CREATE TABLE IF NOT EXISTS transaction_detail_modifiers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_detail_id INTEGER NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    modifier_id INTEGER REFERENCES modifiers(id) ON DELETE SET NULL,
+    group_nama VARCHAR(100) NOT NULL,
+    nama VARCHAR(100) NOT NULL,
+    harga INTEGER NOT NULL CHECK (harga >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_detail_modifiers_detail_id ON transaction_detail_modifiers (transaction_detail_id);
@@ -0,0 +1,18 @@
+-- Migration: Bundle products (Paket Hemat) and product stock (SQLite)
+-- Created at: 2026-10-19
+
+ALTER TABLE products ADD COLUMN type VARCHAR(10) NOT NULL DEFAULT 'single' CHECK (type IN ('single', 'bundle'));
+
+ALTER TABLE products ADD COLUMN stock INTEGER CHECK (stock >= 0);
+
+CREATE TABLE IF NOT EXISTS bundle_components (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bundle_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+    variant_id INTEGER REFERENCES product_variants(id) ON DELETE RESTRICT,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    CHECK (product_id <> bundle_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_bundle_components ON bundle_components (bundle_id, product_id, COALESCE(variant_id, 0));
+CREATE INDEX IF NOT EXISTS idx_bundle_components_product_id ON bundle_components (product_id);
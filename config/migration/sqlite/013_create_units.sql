@@ -0,0 +1,35 @@
+-- Migration: Units of measure, unit conversions and decimal quantities (SQLite)
+-- Created at: 2026-10-19
+-- INTEGER SQLite sudah 64-bit, cukup konversi jumlah lama ke seperseribu unit
+
+CREATE TABLE IF NOT EXISTS units (
+    code VARCHAR(10) PRIMARY KEY CHECK (code = LOWER(code)),
+    nama VARCHAR(50) NOT NULL,
+    allow_decimal BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+INSERT OR IGNORE INTO units (code, nama, allow_decimal) VALUES
+    ('pcs', 'Pieces', FALSE),
+    ('dus', 'Dus', FALSE),
+    ('pak', 'Pak', FALSE),
+    ('kg', 'Kilogram', TRUE),
+    ('g', 'Gram', TRUE),
+    ('l', 'Liter', TRUE),
+    ('ml', 'Mililiter', TRUE);
+
+-- SQLite tidak mengizinkan kolom REFERENCES baru dengan default selain NULL,
+-- satuan dasar diisi setelahnya dan selalu ditulis oleh repository
+ALTER TABLE products ADD COLUMN unit VARCHAR(10) REFERENCES units(code) ON DELETE RESTRICT;
+ALTER TABLE products ADD COLUMN purchase_unit VARCHAR(10) REFERENCES units(code) ON DELETE RESTRICT;
+UPDATE products SET unit = 'pcs', stock = stock * 1000;
+
+CREATE TABLE IF NOT EXISTS product_units (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    unit VARCHAR(10) NOT NULL REFERENCES units(code) ON DELETE RESTRICT,
+    factor INTEGER NOT NULL CHECK (factor > 0),
+    PRIMARY KEY (product_id, unit)
+);
+
+ALTER TABLE transaction_details ADD COLUMN unit VARCHAR(10) NOT NULL DEFAULT 'pcs';
+ALTER TABLE transaction_details ADD COLUMN base_quantity INTEGER NOT NULL DEFAULT 0 CHECK (base_quantity >= 0);
+UPDATE transaction_details SET quantity = quantity * 1000, base_quantity = quantity * 1000;
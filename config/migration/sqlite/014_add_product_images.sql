@@ -0,0 +1,6 @@
+-- Migration: Product images for touchscreen POS tiles (SQLite)
+-- Created at: 2026-10-19
+
+ALTER TABLE products ADD COLUMN image_key VARCHAR(255);
+
+ALTER TABLE products ADD COLUMN thumbnail_key VARCHAR(255);
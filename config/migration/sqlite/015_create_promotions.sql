@@ -0,0 +1,46 @@
+-- Migration: Promotions applied automatically when a cart is priced (SQLite)
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nama VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percentage', 'fixed', 'bogo', 'threshold')),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('product', 'category', 'cart')),
+    product_id INTEGER REFERENCES products(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    percent INTEGER CHECK (percent > 0 AND percent <= 10000),
+    amount INTEGER CHECK (amount > 0),
+    min_subtotal INTEGER CHECK (min_subtotal >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    buy_quantity INTEGER CHECK (buy_quantity > 0),
+    get_quantity INTEGER CHECK (get_quantity > 0),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    days INTEGER NOT NULL DEFAULT 0 CHECK (days >= 0 AND days < 128),
+    priority INTEGER NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((scope = 'product') = (product_id IS NOT NULL)),
+    CHECK ((scope = 'category') = (category_id IS NOT NULL)),
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_product_id ON promotions (product_id);
+CREATE INDEX IF NOT EXISTS idx_promotions_category_id ON promotions (category_id);
+
+ALTER TABLE transactions ADD COLUMN subtotal INTEGER NOT NULL DEFAULT 0 CHECK (subtotal >= 0);
+ALTER TABLE transactions ADD COLUMN discount_amount INTEGER NOT NULL DEFAULT 0 CHECK (discount_amount >= 0);
+UPDATE transactions SET subtotal = total_amount;
+
+ALTER TABLE transaction_details ADD COLUMN discount INTEGER NOT NULL DEFAULT 0 CHECK (discount >= 0 AND discount <= subtotal);
+
+CREATE TABLE IF NOT EXISTS transaction_discounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    promotion_id INTEGER REFERENCES promotions(id) ON DELETE SET NULL,
+    nama VARCHAR(100) NOT NULL,
+    amount INTEGER NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_discounts_transaction_id ON transaction_discounts (transaction_id);
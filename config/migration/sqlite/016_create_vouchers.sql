@@ -0,0 +1,38 @@
+-- Migration: Voucher codes with usage limits and redemption history (SQLite)
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS vouchers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code VARCHAR(32) NOT NULL UNIQUE CHECK (code = UPPER(code)),
+    nama VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percentage', 'fixed')),
+    percent INTEGER CHECK (percent > 0 AND percent <= 10000),
+    amount INTEGER CHECK (amount > 0),
+    max_discount INTEGER CHECK (max_discount > 0),
+    min_subtotal INTEGER CHECK (min_subtotal >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'IDR',
+    usage_limit INTEGER CHECK (usage_limit > 0),
+    per_customer_limit INTEGER CHECK (per_customer_limit > 0),
+    used_count INTEGER NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    starts_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((type = 'percentage') = (percent IS NOT NULL)),
+    CHECK ((type = 'fixed') = (amount IS NOT NULL)),
+    CHECK (usage_limit IS NULL OR used_count <= usage_limit),
+    CHECK (expires_at IS NULL OR starts_at IS NULL OR expires_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    voucher_id INTEGER NOT NULL REFERENCES vouchers(id) ON DELETE CASCADE,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    customer VARCHAR(100),
+    amount INTEGER NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_customer ON voucher_redemptions (voucher_id, customer);
+
+ALTER TABLE transaction_discounts ADD COLUMN voucher_id INTEGER REFERENCES vouchers(id) ON DELETE SET NULL;
@@ -0,0 +1,41 @@
+-- Migration: Tax rates (PPN, service charge) with per-category exemptions (SQLite)
+-- Created at: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nama VARCHAR(100) NOT NULL UNIQUE,
+    rate INTEGER NOT NULL CHECK (rate > 0 AND rate <= 10000),
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    compound BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (NOT (inclusive AND compound))
+);
+
+CREATE TABLE IF NOT EXISTS tax_rate_exemptions (
+    tax_rate_id INTEGER NOT NULL REFERENCES tax_rates(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (tax_rate_id, category_id)
+);
+
+INSERT OR IGNORE INTO tax_rates (nama, rate, inclusive, compound, position) VALUES
+    ('Service Charge 5%', 500, FALSE, FALSE, 1),
+    ('PPN 11%', 1100, FALSE, TRUE, 2);
+
+ALTER TABLE transactions ADD COLUMN tax_amount INTEGER NOT NULL DEFAULT 0 CHECK (tax_amount >= 0);
+
+ALTER TABLE transaction_details ADD COLUMN tax_included INTEGER NOT NULL DEFAULT 0 CHECK (tax_included >= 0);
+
+CREATE TABLE IF NOT EXISTS transaction_taxes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE SET NULL,
+    nama VARCHAR(100) NOT NULL,
+    rate INTEGER NOT NULL CHECK (rate > 0),
+    inclusive BOOLEAN NOT NULL,
+    taxable_amount INTEGER NOT NULL CHECK (taxable_amount >= 0),
+    amount INTEGER NOT NULL CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_taxes_transaction_id ON transaction_taxes (transaction_id);
@@ -0,0 +1,4 @@
+-- Migration: Cost price (harga beli) per product variant (SQLite)
+-- Created at: 2026-10-19
+
+ALTER TABLE product_variants ADD COLUMN harga_beli INTEGER CHECK (harga_beli >= 0);
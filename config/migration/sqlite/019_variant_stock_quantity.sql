@@ -0,0 +1,5 @@
+-- Migration: Variant stock in thousandths of a unit (SQLite)
+-- Created at: 2026-10-19
+-- INTEGER SQLite sudah 64-bit, cukup konversi stok lama ke seperseribu unit
+
+UPDATE product_variants SET stock = stock * 1000;
@@ -0,0 +1,7 @@
+-- Migration: Remove the tax rates seeded by 017 (SQLite)
+-- Created at: 2026-10-19
+
+DELETE FROM tax_rates
+WHERE ((nama = 'Service Charge 5%' AND rate = 500 AND NOT inclusive AND NOT compound AND position = 1)
+    OR (nama = 'PPN 11%' AND rate = 1100 AND NOT inclusive AND compound AND position = 2))
+  AND NOT EXISTS (SELECT 1 FROM tax_rate_exemptions e WHERE e.tax_rate_id = tax_rates.id);
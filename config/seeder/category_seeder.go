@@ -63,10 +63,11 @@ func SeedCategories(db *sql.DB) error {
 	return nil
 }
 
-// GetCategoryIDByName gets category ID by name
+// GetCategoryIDByName gets a root category ID by name, case-insensitive
+// (unique per parent, see migration 006)
 func GetCategoryIDByName(db *sql.DB, name string) (int, error) {
 	var id int
-	err := db.QueryRow("SELECT id FROM categories WHERE name = $1", name).Scan(&id)
+	err := db.QueryRow("SELECT id FROM categories WHERE LOWER(name) = LOWER($1) AND parent_id IS NULL", name).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
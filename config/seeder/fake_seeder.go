@@ -0,0 +1,518 @@
+package seeder
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"kasir-api/entity"
+)
+
+// FakeOptions configures the fake data generator
+type FakeOptions struct {
+	Categories   int
+	Products     int
+	Transactions int
+	Seed         int64
+	Days         int       // rentang hari transaksi historis
+	Until        time.Time // transaksi dibuat sebelum waktu ini
+	BatchSize    int
+	// MaxBindParams - batas parameter ($N) per statement dari driver pemanggil,
+	// 0 = batas protokol PostgreSQL
+	MaxBindParams int
+}
+
+// DefaultFakeOptions returns sensible defaults for a demo dataset
+func DefaultFakeOptions() FakeOptions {
+	return FakeOptions{
+		Categories:   10,
+		Products:     1000,
+		Transactions: 5000,
+		Seed:         42,
+		Days:         90,
+		Until:        time.Now().Truncate(24 * time.Hour),
+		BatchSize:    500,
+	}
+}
+
+// fakeCategory is a category template with product name parts and price range
+type fakeCategory struct {
+	Name        string
+	Description string
+	Items       []string
+	Variants    []string
+	MinHarga    int
+	MaxHarga    int
+}
+
+// fakeCategories contains realistic Indonesian retail categories
+var fakeCategories = []fakeCategory{
+	{
+		Name:        "Minuman",
+		Description: "Minuman kemasan dan minuman racikan",
+		Items:       []string{"Es Teh Manis", "Teh Botol", "Kopi Susu", "Kopi Hitam", "Air Mineral", "Jus Jeruk", "Jus Alpukat", "Susu Kedelai", "Es Jeruk", "Teh Tarik", "Wedang Jahe", "Es Cendol"},
+		Variants:    []string{"250ml", "330ml", "500ml", "600ml", "1L", "1,5L", "Regular", "Jumbo"},
+		MinHarga:    3000,
+		MaxHarga:    25000,
+	},
+	{
+		Name:        "Makanan",
+		Description: "Makanan siap saji",
+		Items:       []string{"Nasi Goreng", "Mie Ayam", "Bakso Urat", "Soto Ayam", "Nasi Uduk", "Gado-Gado", "Rendang", "Ayam Geprek", "Nasi Rames", "Sate Ayam", "Pecel Lele", "Mie Goreng Jawa"},
+		Variants:    []string{"Spesial", "Biasa", "Pedas", "Jumbo", "Porsi Kecil", "Komplit"},
+		MinHarga:    8000,
+		MaxHarga:    45000,
+	},
+	{
+		Name:        "Snack",
+		Description: "Makanan ringan dan cemilan",
+		Items:       []string{"Keripik Kentang", "Keripik Singkong", "Kacang Garuda", "Chocolatos", "Wafer Tango", "Biskuit Roma", "Kerupuk Udang", "Rempeyek", "Makaroni Pedas", "Basreng"},
+		Variants:    []string{"Mini", "40g", "75g", "150g", "Family Pack", "Balado", "Original"},
+		MinHarga:    1000,
+		MaxHarga:    20000,
+	},
+	{
+		Name:        "Sembako",
+		Description: "Bahan pokok kebutuhan sehari-hari",
+		Items:       []string{"Beras Pandan Wangi", "Gula Pasir", "Minyak Goreng", "Tepung Terigu", "Telur Ayam", "Garam Dapur", "Beras Merah", "Kecap Manis", "Mie Instan"},
+		Variants:    []string{"250g", "500g", "1kg", "2kg", "5kg", "1 Liter", "2 Liter", "Renceng"},
+		MinHarga:    2500,
+		MaxHarga:    85000,
+	},
+	{
+		Name:        "Bumbu Dapur",
+		Description: "Bumbu dan rempah masakan",
+		Items:       []string{"Bawang Merah", "Bawang Putih", "Cabai Rawit", "Kunyit Bubuk", "Merica Bubuk", "Ketumbar", "Penyedap Rasa", "Saus Sambal", "Terasi"},
+		Variants:    []string{"Sachet", "50g", "100g", "250g", "Botol", "Ons"},
+		MinHarga:    1000,
+		MaxHarga:    30000,
+	},
+	{
+		Name:        "Perlengkapan Mandi",
+		Description: "Sabun, sampo dan perawatan diri",
+		Items:       []string{"Sabun Mandi", "Sampo", "Pasta Gigi", "Sikat Gigi", "Sabun Cuci Muka", "Deodoran", "Minyak Kayu Putih", "Bedak Bayi"},
+		Variants:    []string{"Sachet", "70ml", "170ml", "340ml", "Refill", "Botol"},
+		MinHarga:    1500,
+		MaxHarga:    45000,
+	},
+	{
+		Name:        "Kebersihan Rumah",
+		Description: "Perlengkapan kebersihan rumah tangga",
+		Items:       []string{"Sabun Cuci Piring", "Deterjen Bubuk", "Pewangi Pakaian", "Pembersih Lantai", "Tisu Gulung", "Kantong Sampah", "Spons Cuci"},
+		Variants:    []string{"Sachet", "400ml", "800ml", "1kg", "Refill", "Isi 10"},
+		MinHarga:    2000,
+		MaxHarga:    50000,
+	},
+	{
+		Name:        "Roti & Kue",
+		Description: "Roti, kue basah dan kue kering",
+		Items:       []string{"Roti Tawar", "Roti Sobek", "Donat Gula", "Bolu Pandan", "Lapis Legit", "Klepon", "Martabak Manis", "Pisang Goreng", "Onde-Onde"},
+		Variants:    []string{"Satuan", "Isi 4", "Isi 10", "Coklat", "Keju", "Original"},
+		MinHarga:    2000,
+		MaxHarga:    60000,
+	},
+	{
+		Name:        "Frozen Food",
+		Description: "Makanan beku siap masak",
+		Items:       []string{"Nugget Ayam", "Sosis Sapi", "Kentang Goreng", "Dimsum Ayam", "Bakso Sapi", "Otak-Otak", "Cireng Isi"},
+		Variants:    []string{"250g", "500g", "1kg", "Isi 10", "Isi 25"},
+		MinHarga:    10000,
+		MaxHarga:    75000,
+	},
+	{
+		Name:        "Alat Tulis",
+		Description: "Alat tulis dan perlengkapan kantor",
+		Items:       []string{"Buku Tulis", "Pulpen", "Pensil 2B", "Penghapus", "Spidol", "Lakban", "Map Plastik", "Kertas HVS"},
+		Variants:    []string{"Satuan", "Isi 3", "Isi 12", "Hitam", "Biru", "A4", "F4"},
+		MinHarga:    1000,
+		MaxHarga:    55000,
+	},
+	{
+		Name:        "Elektronik",
+		Description: "Barang elektronik dan gadget",
+		Items:       []string{"Kabel Data", "Charger HP", "Earphone", "Baterai AA", "Lampu LED", "Stopkontak", "Power Bank"},
+		Variants:    []string{"1 Meter", "2 Meter", "5 Watt", "10 Watt", "Type-C", "Micro USB", "10000mAh"},
+		MinHarga:    5000,
+		MaxHarga:    250000,
+	},
+	{
+		Name:        "Rokok",
+		Description: "Rokok dan produk tembakau",
+		Items:       []string{"Kretek Filter", "Kretek Tangan", "Rokok Putih", "Cerutu Mini"},
+		Variants:    []string{"Isi 12", "Isi 16", "Slop", "Mild", "Bold"},
+		MinHarga:    15000,
+		MaxHarga:    300000,
+	},
+}
+
+// fakeBrands are appended to product names to keep them distinct
+var fakeBrands = []string{"Sari", "Bu Tini", "Mak Nyus", "Sumber Rejeki", "Cap Jago", "Nusantara", "Berkah", "Sinar Jaya", "Pak Kumis", "Sederhana", "Sehat", "Mantap"}
+
+// fakeProduct is a generated product waiting to be inserted
+type fakeProduct struct {
+	Nama          string
+	Harga         int
+	HargaBeli     int
+	CategoryIndex int
+}
+
+// FakeSeeder generates deterministic fake datasets for load tests and demos
+type FakeSeeder struct {
+	db   *sql.DB
+	opts FakeOptions
+	rnd  *rand.Rand
+}
+
+// NewFakeSeeder creates a new fake seeder instance
+func NewFakeSeeder(db *sql.DB, opts FakeOptions) *FakeSeeder {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.Days <= 0 {
+		opts.Days = 1
+	}
+	if opts.Until.IsZero() {
+		opts.Until = time.Now().Truncate(24 * time.Hour)
+	}
+	if opts.MaxBindParams <= 0 {
+		opts.MaxBindParams = PostgresMaxBindParams
+	}
+	return &FakeSeeder{
+		db:   db,
+		opts: opts,
+		rnd:  rand.New(rand.NewSource(opts.Seed)),
+	}
+}
+
+// Run generates categories, products and historical transactions
+func (s *FakeSeeder) Run() error {
+	fmt.Printf("\n🎲 Generating fake data (seed %d)...\n", s.opts.Seed)
+
+	if s.opts.Categories <= 0 && (s.opts.Products > 0 || s.opts.Transactions > 0) {
+		return fmt.Errorf("at least one category is required to generate products")
+	}
+	if s.opts.Products <= 0 && s.opts.Transactions > 0 {
+		return fmt.Errorf("at least one product is required to generate transactions")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	categoryIDs, err := s.insertCategories(tx)
+	if err != nil {
+		return err
+	}
+
+	products := s.generateProducts()
+	productIDs, err := s.insertProducts(tx, products, categoryIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := s.insertPrices(tx, products, productIDs); err != nil {
+		return err
+	}
+
+	if err := s.insertTransactions(tx, products, productIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fake data: %w", err)
+	}
+
+	fmt.Println("✅ Fake data generated successfully")
+	return nil
+}
+
+// insertCategories inserts N categories and returns their IDs in order
+func (s *FakeSeeder) insertCategories(tx *sql.Tx) ([]int, error) {
+	rows := make([][]interface{}, 0, s.opts.Categories)
+	for i := 0; i < s.opts.Categories; i++ {
+		tmpl := fakeCategories[i%len(fakeCategories)]
+		name := tmpl.Name
+		if round := i / len(fakeCategories); round > 0 {
+			name = fmt.Sprintf("%s %d", tmpl.Name, round+1)
+		}
+		rows = append(rows, []interface{}{name, tmpl.Description})
+	}
+
+	ids, err := s.batchInsert(tx, "categories", []string{"name", "description"}, rows, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert fake categories: %w", err)
+	}
+	fmt.Printf("  ✓ %d categories\n", len(ids))
+	return ids, nil
+}
+
+// generateProducts builds product names and prices from the category templates
+func (s *FakeSeeder) generateProducts() []fakeProduct {
+	products := make([]fakeProduct, 0, s.opts.Products)
+	seen := make(map[string]int, s.opts.Products)
+
+	for i := 0; i < s.opts.Products; i++ {
+		categoryIndex := s.rnd.Intn(s.opts.Categories)
+		tmpl := fakeCategories[categoryIndex%len(fakeCategories)]
+
+		nama := fmt.Sprintf("%s %s %s",
+			tmpl.Items[s.rnd.Intn(len(tmpl.Items))],
+			fakeBrands[s.rnd.Intn(len(fakeBrands))],
+			tmpl.Variants[s.rnd.Intn(len(tmpl.Variants))],
+		)
+		// Nama yang sama diberi nomor urut agar tetap unik
+		seen[nama]++
+		if seen[nama] > 1 {
+			nama = fmt.Sprintf("%s #%d", nama, seen[nama])
+		}
+		if len(nama) > 100 {
+			nama = nama[:100]
+		}
+
+		harga := s.fakePrice(tmpl.MinHarga, tmpl.MaxHarga)
+		products = append(products, fakeProduct{
+			Nama:          nama,
+			Harga:         harga,
+			HargaBeli:     s.fakeCost(harga),
+			CategoryIndex: categoryIndex,
+		})
+	}
+
+	return products
+}
+
+// fakePrice returns a log-uniform price rounded like a real price tag
+func (s *FakeSeeder) fakePrice(min, max int) int {
+	logMin, logMax := math.Log(float64(min)), math.Log(float64(max))
+	harga := math.Exp(logMin + s.rnd.Float64()*(logMax-logMin))
+
+	step := 500.0
+	if harga < 5000 {
+		step = 100
+	} else if harga >= 50000 {
+		step = 1000
+	}
+	return int(math.Max(step, math.Round(harga/step)*step))
+}
+
+// fakeCost returns a cost price of 55-80% of harga, rounded to Rp 100
+func (s *FakeSeeder) fakeCost(harga int) int {
+	ratio := 0.55 + s.rnd.Float64()*0.25
+	return int(math.Max(100, math.Round(float64(harga)*ratio/100)*100))
+}
+
+// insertProducts inserts generated products and returns their IDs in order
+func (s *FakeSeeder) insertProducts(tx *sql.Tx, products []fakeProduct, categoryIDs []int) ([]int, error) {
+	rows := make([][]interface{}, 0, len(products))
+	for _, p := range products {
+		rows = append(rows, []interface{}{p.Nama, minorUnits(p.Harga), minorUnits(p.HargaBeli), categoryIDs[p.CategoryIndex]})
+	}
+
+	ids, err := s.batchInsert(tx, "products", []string{"nama", "harga", "harga_beli", "category_id"}, rows, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert fake products: %w", err)
+	}
+	fmt.Printf("  ✓ %d products\n", len(ids))
+	return ids, nil
+}
+
+// insertPrices records the product prices as effective since the start of the sales history
+func (s *FakeSeeder) insertPrices(tx *sql.Tx, products []fakeProduct, productIDs []int) error {
+	start := s.opts.Until.AddDate(0, 0, -s.opts.Days).UTC()
+	rows := make([][]interface{}, 0, len(products))
+	for i, p := range products {
+		rows = append(rows, []interface{}{productIDs[i], minorUnits(p.Harga), start})
+	}
+
+	if _, err := s.batchInsert(tx, "product_prices", []string{"product_id", "harga", "effective_from"}, rows, false); err != nil {
+		return fmt.Errorf("failed to insert fake product prices: %w", err)
+	}
+	return nil
+}
+
+// insertTransactions generates historical sales with popular products sold more often
+func (s *FakeSeeder) insertTransactions(tx *sql.Tx, products []fakeProduct, productIDs []int) error {
+	if s.opts.Transactions <= 0 {
+		return nil
+	}
+
+	// Distribusi Zipf: sebagian kecil produk menjadi best seller
+	popularity := rand.NewZipf(s.rnd, 1.2, 1, uint64(len(products)-1))
+	start := s.opts.Until.AddDate(0, 0, -s.opts.Days)
+
+	for offset := 0; offset < s.opts.Transactions; offset += s.opts.BatchSize {
+		size := s.opts.BatchSize
+		if offset+size > s.opts.Transactions {
+			size = s.opts.Transactions - offset
+		}
+
+		headers := make([][]interface{}, 0, size)
+		lines := make([][]fakeLine, 0, size)
+		for i := 0; i < size; i++ {
+			details, total := s.generateLines(products, popularity)
+			headers = append(headers, []interface{}{minorUnits(total), minorUnits(total), s.fakeTime(start)})
+			lines = append(lines, details)
+		}
+
+		transactionIDs, err := s.batchInsert(tx, "transactions", []string{"subtotal", "total_amount", "created_at"}, headers, true)
+		if err != nil {
+			return fmt.Errorf("failed to insert fake transactions: %w", err)
+		}
+
+		var detailRows [][]interface{}
+		for i, details := range lines {
+			for _, d := range details {
+				p := products[d.ProductIndex]
+				quantity := int64(entity.Qty(int64(d.Quantity)))
+				detailRows = append(detailRows, []interface{}{
+					transactionIDs[i], productIDs[d.ProductIndex], p.Nama, minorUnits(p.Harga), minorUnits(p.HargaBeli),
+					quantity, quantity, minorUnits(p.Harga * d.Quantity),
+				})
+			}
+		}
+
+		_, err = s.batchInsert(tx, "transaction_details",
+			[]string{"transaction_id", "product_id", "nama_produk", "harga", "harga_beli", "quantity", "base_quantity", "subtotal"},
+			detailRows, false,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert fake transaction details: %w", err)
+		}
+	}
+
+	fmt.Printf("  ✓ %d transactions\n", s.opts.Transactions)
+	return nil
+}
+
+// fakeLine is a generated transaction line
+type fakeLine struct {
+	ProductIndex int
+	Quantity     int
+}
+
+// generateLines picks 1-6 distinct products for a single transaction
+func (s *FakeSeeder) generateLines(products []fakeProduct, popularity *rand.Zipf) ([]fakeLine, int) {
+	count := 1 + s.rnd.Intn(6)
+	picked := make(map[int]bool, count)
+	lines := make([]fakeLine, 0, count)
+	total := 0
+
+	for len(lines) < count && len(picked) < len(products) {
+		index := int(popularity.Uint64())
+		if picked[index] {
+			continue
+		}
+		picked[index] = true
+
+		quantity := 1
+		if s.rnd.Float64() < 0.3 {
+			quantity += s.rnd.Intn(4)
+		}
+		lines = append(lines, fakeLine{ProductIndex: index, Quantity: quantity})
+		total += products[index].Harga * quantity
+	}
+
+	return lines, total
+}
+
+// minorUnits converts whole rupiah to the minor units stored in money columns
+func minorUnits(rupiah int) int64 {
+	return entity.IDR(int64(rupiah)).Amount
+}
+
+// fakeTime returns a timestamp within store opening hours (07:00 - 22:00)
+func (s *FakeSeeder) fakeTime(start time.Time) time.Time {
+	day := start.AddDate(0, 0, s.rnd.Intn(s.opts.Days))
+	// Jam ramai di sekitar makan siang dan sore hari
+	hour := int(math.Round(14.5 + s.rnd.NormFloat64()*3.5))
+	if hour < 7 {
+		hour = 7
+	} else if hour > 21 {
+		hour = 21
+	}
+	return day.Add(time.Duration(hour)*time.Hour +
+		time.Duration(s.rnd.Intn(60))*time.Minute +
+		time.Duration(s.rnd.Intn(60))*time.Second)
+}
+
+// Batas parameter ($N) per statement: protokol PostgreSQL dan SQLITE_MAX_VARIABLE_NUMBER
+const (
+	PostgresMaxBindParams = 65535
+	SQLiteMaxBindParams   = 32766
+)
+
+// batchInsert inserts rows using multi-row VALUES and optionally returns the new IDs
+func (s *FakeSeeder) batchInsert(tx *sql.Tx, table string, columns []string, rows [][]interface{}, returning bool) ([]int, error) {
+	var ids []int
+
+	// Jumlah placeholder per statement dibatasi driver
+	size := s.opts.BatchSize
+	if limit := s.opts.MaxBindParams / len(columns); size > limit {
+		size = limit
+	}
+
+	for offset := 0; offset < len(rows); offset += size {
+		end := offset + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		chunk := rows[offset:end]
+
+		var query strings.Builder
+		fmt.Fprintf(&query, "INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+
+		args := make([]interface{}, 0, len(chunk)*len(columns))
+		for i, row := range chunk {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString("(")
+			for j, value := range row {
+				if j > 0 {
+					query.WriteString(", ")
+				}
+				args = append(args, value)
+				fmt.Fprintf(&query, "$%d", len(args))
+			}
+			query.WriteString(")")
+		}
+
+		if !returning {
+			if _, err := tx.Exec(query.String(), args...); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// PostgreSQL mengembalikan RETURNING sesuai urutan VALUES
+		query.WriteString(" RETURNING id")
+		result, err := tx.Query(query.String(), args...)
+		if err != nil {
+			return nil, err
+		}
+		for result.Next() {
+			var id int
+			if err := result.Scan(&id); err != nil {
+				result.Close()
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		result.Close()
+		if err := result.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
+
+// SeedFake generates a fake dataset with the given options
+func SeedFake(db *sql.DB, opts FakeOptions) error {
+	return NewFakeSeeder(db, opts).Run()
+}
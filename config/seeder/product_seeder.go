@@ -3,12 +3,15 @@ package seeder
 import (
 	"database/sql"
 	"fmt"
+	"time"
+
+	"kasir-api/entity"
 )
 
 // ProductSeed represents a product seed data
 type ProductSeed struct {
 	Nama       string
-	Harga      int
+	Harga      int // rupiah utuh, disimpan sebagai minor unit
 	CategoryID int
 }
 
@@ -65,13 +68,13 @@ func SeedProducts(db *sql.DB) error {
 	// Insert products
 	for _, prod := range DefaultProducts {
 		_, err := db.Exec(
-			"INSERT INTO products (nama, harga, category_id) VALUES ($1, $2, $3)",
-			prod.Nama, prod.Harga, prod.CategoryID,
+			"INSERT INTO products (nama, harga, currency, category_id) VALUES ($1, $2, $3, $4)",
+			prod.Nama, entity.IDR(int64(prod.Harga)).Amount, "IDR", prod.CategoryID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert product %s: %w", prod.Nama, err)
 		}
-		fmt.Printf("  ✓ Product: %s (Rp %d)\n", prod.Nama, prod.Harga)
+		fmt.Printf("  ✓ Product: %s (%s)\n", prod.Nama, entity.IDR(int64(prod.Harga)))
 	}
 
 	fmt.Printf("  ✅ Seeded %d products\n", len(DefaultProducts))
@@ -119,15 +122,32 @@ func SeedProductsWithCategoryNames(db *sql.DB) error {
 		}
 
 		_, err = db.Exec(
-			"INSERT INTO products (nama, harga, category_id) VALUES ($1, $2, $3)",
-			prod.Nama, prod.Harga, categoryID,
+			"INSERT INTO products (nama, harga, currency, category_id) VALUES ($1, $2, $3, $4)",
+			prod.Nama, entity.IDR(int64(prod.Harga)).Amount, "IDR", categoryID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert product %s: %w", prod.Nama, err)
 		}
-		fmt.Printf("  ✓ Product: %s (Rp %d) - %s\n", prod.Nama, prod.Harga, prod.CategoryName)
+		fmt.Printf("  ✓ Product: %s (%s) - %s\n", prod.Nama, entity.IDR(int64(prod.Harga)), prod.CategoryName)
 	}
 
 	fmt.Println("  ✅ Products seeded")
 	return nil
 }
+
+// SeedProductPrices records the current harga as the first price of every product without price history
+func SeedProductPrices(db *sql.DB) error {
+	result, err := db.Exec(`
+		INSERT INTO product_prices (product_id, harga, currency, effective_from)
+		SELECT id, harga, currency, $1 FROM products
+		WHERE NOT EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = products.id)`,
+		time.Now().UTC().Truncate(time.Microsecond),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to seed product prices: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		fmt.Printf("  ✓ Recorded prices for %d products\n", n)
+	}
+	return nil
+}
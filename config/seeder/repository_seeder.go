@@ -0,0 +1,53 @@
+package seeder
+
+import (
+	"fmt"
+	"time"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// SeedRepositories seeds default data through the repository interfaces,
+// used by storage backends without SQL such as STORAGE=memory
+func SeedRepositories(categories repository.CategoryRepositoryInterface, products repository.ProductRepositoryInterface, prices repository.PriceRepositoryInterface) error {
+	fmt.Println("\n🌱 Seeding repositories...")
+
+	existing, err := categories.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to check categories: %w", err)
+	}
+	if len(existing) > 0 {
+		fmt.Printf("  ⏭️  Categories already seeded (%d records exist)\n", len(existing))
+		return nil
+	}
+
+	// Map seed CategoryID (urutan DefaultCategories) ke ID yang dibuat repository
+	categoryIDs := make(map[int]int, len(DefaultCategories))
+	for i, cat := range DefaultCategories {
+		created, err := categories.Create(entity.Category{Name: cat.Name, Description: cat.Description})
+		if err != nil {
+			return fmt.Errorf("failed to insert category %s: %w", cat.Name, err)
+		}
+		categoryIDs[i+1] = created.ID
+	}
+
+	for _, prod := range DefaultProducts {
+		categoryID := categoryIDs[prod.CategoryID]
+		created, err := products.Create(entity.Product{
+			Nama:       prod.Nama,
+			Harga:      entity.IDR(int64(prod.Harga)),
+			CategoryID: &categoryID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert product %s: %w", prod.Nama, err)
+		}
+		_, err = prices.Create(entity.ProductPrice{ProductID: created.ID, Harga: created.Harga, EffectiveFrom: time.Now()})
+		if err != nil {
+			return fmt.Errorf("failed to record price of %s: %w", prod.Nama, err)
+		}
+	}
+
+	fmt.Printf("  ✅ Seeded %d categories and %d products\n", len(DefaultCategories), len(DefaultProducts))
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+
+	"kasir-api/repository/sqlite"
 )
 
 // Seeder handles database seeding
@@ -29,6 +31,10 @@ func (s *Seeder) Run() error {
 		return fmt.Errorf("product seeder failed: %w", err)
 	}
 
+	if err := SeedProductPrices(s.db); err != nil {
+		return fmt.Errorf("product price seeder failed: %w", err)
+	}
+
 	fmt.Println("✅ All seeders completed successfully")
 	return nil
 }
@@ -45,8 +51,33 @@ func RunSeeder(db *sql.DB) {
 func Clear(db *sql.DB) error {
 	fmt.Println("🗑️  Clearing all data...")
 
-	// Delete in correct order (products first due to FK)
-	_, err := db.Exec("DELETE FROM products")
+	// Delete in correct order (transactions, then products due to FK)
+	_, err := db.Exec("DELETE FROM transactions")
+	if err != nil {
+		return fmt.Errorf("failed to clear transactions: %w", err)
+	}
+	fmt.Println("  ✓ Cleared transactions")
+
+	_, err = db.Exec("DELETE FROM promotions")
+	if err != nil {
+		return fmt.Errorf("failed to clear promotions: %w", err)
+	}
+	fmt.Println("  ✓ Cleared promotions")
+
+	_, err = db.Exec("DELETE FROM vouchers")
+	if err != nil {
+		return fmt.Errorf("failed to clear vouchers: %w", err)
+	}
+	fmt.Println("  ✓ Cleared vouchers")
+
+	// Komponen bundle menahan (RESTRICT) penghapusan produk komponennya
+	_, err = db.Exec("DELETE FROM bundle_components")
+	if err != nil {
+		return fmt.Errorf("failed to clear bundle components: %w", err)
+	}
+	fmt.Println("  ✓ Cleared bundle components")
+
+	_, err = db.Exec("DELETE FROM products")
 	if err != nil {
 		return fmt.Errorf("failed to clear products: %w", err)
 	}
@@ -59,20 +90,26 @@ func Clear(db *sql.DB) error {
 	fmt.Println("  ✓ Cleared categories")
 
 	// Reset sequences
-	_, err = db.Exec("ALTER SEQUENCE products_id_seq RESTART WITH 1")
-	if err != nil {
-		return fmt.Errorf("failed to reset products sequence: %w", err)
-	}
-
-	_, err = db.Exec("ALTER SEQUENCE categories_id_seq RESTART WITH 1")
-	if err != nil {
-		return fmt.Errorf("failed to reset categories sequence: %w", err)
+	for _, table := range []string{"transactions", "transaction_details", "transaction_discounts", "transaction_taxes", "promotions", "voucher_redemptions", "vouchers", "transaction_detail_modifiers", "modifiers", "modifier_groups", "bundle_components", "product_variants", "product_prices", "products", "categories"} {
+		if err := resetSequence(db, table); err != nil {
+			return fmt.Errorf("failed to reset %s sequence: %w", table, err)
+		}
 	}
 
 	fmt.Println("✅ All data cleared")
 	return nil
 }
 
+// resetSequence restarts the ID sequence of a table at 1
+func resetSequence(db *sql.DB, table string) error {
+	if sqlite.IsSQLite(db) {
+		_, err := db.Exec("DELETE FROM sqlite_sequence WHERE name = $1", table)
+		return err
+	}
+	_, err := db.Exec(fmt.Sprintf("ALTER SEQUENCE %s_id_seq RESTART WITH 1", table))
+	return err
+}
+
 // Refresh clears and re-seeds data
 func Refresh(db *sql.DB) error {
 	if err := Clear(db); err != nil {
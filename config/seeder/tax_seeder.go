@@ -0,0 +1,52 @@
+package seeder
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// TaxRateSeed represents a tax rate seed data, rate in basis points (1100 = 11%)
+type TaxRateSeed struct {
+	Nama     string
+	Rate     int
+	Compound bool
+	Position int
+}
+
+// DefaultTaxRates contains example restaurant taxes: service charge 5%, then PPN 11%
+// on price + service charge. Not part of the default seed, migrations leave tax_rates
+// empty so existing totals do not change.
+var DefaultTaxRates = []TaxRateSeed{
+	{Nama: "Service Charge 5%", Rate: 500, Position: 1},
+	{Nama: "PPN 11%", Rate: 1100, Compound: true, Position: 2},
+}
+
+// SeedTaxRates seeds the example tax rates into an empty tax_rates table
+func SeedTaxRates(db *sql.DB) error {
+	fmt.Println("🌱 Seeding tax rates...")
+
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM tax_rates").Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check tax rates count: %w", err)
+	}
+
+	if count > 0 {
+		fmt.Printf("  ⏭️  Tax rates already configured (%d records exist)\n", count)
+		return nil
+	}
+
+	for _, t := range DefaultTaxRates {
+		_, err := db.Exec(
+			"INSERT INTO tax_rates (nama, rate, compound, position) VALUES ($1, $2, $3, $4)",
+			t.Nama, t.Rate, t.Compound, t.Position,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert tax rate %s: %w", t.Nama, err)
+		}
+		fmt.Printf("  ✓ Tax rate: %s\n", t.Nama)
+	}
+
+	fmt.Printf("  ✅ Seeded %d tax rates\n", len(DefaultTaxRates))
+	return nil
+}
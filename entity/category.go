@@ -4,4 +4,15 @@ type Category struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	ParentID    *int   `json:"parent_id"`
+	// Path - breadcrumb dari kategori root sampai kategori ini, misal Minuman > Kopi > Kopi Susu
+	Path []CategoryCrumb `json:"path,omitempty"`
+	// Children - sub-kategori, hanya diisi oleh GET /api/categories/tree
+	Children []Category `json:"children,omitempty"`
+}
+
+// CategoryCrumb - satu langkah breadcrumb kategori
+type CategoryCrumb struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
@@ -0,0 +1,50 @@
+package entity
+
+import "encoding/json"
+
+// ModifierGroup - kelompok pilihan tambahan untuk produk atau semua produk dalam kategori,
+// misal "Ekstra" (opsional, maks 3) atau "Tingkat Gula" (wajib, pilih 1)
+type ModifierGroup struct {
+	ID         int        `json:"id"`
+	Nama       string     `json:"nama"`
+	ProductID  *int       `json:"product_id"`  // diisi salah satu: product_id atau category_id
+	CategoryID *int       `json:"category_id"` // berlaku juga untuk sub-kategori
+	Required   bool       `json:"required"`    // sama dengan min_select > 0
+	MinSelect  int        `json:"min_select"`
+	MaxSelect  int        `json:"max_select"` // 0 = tanpa batas
+	Modifiers  []Modifier `json:"modifiers"`
+}
+
+// Modifier - satu pilihan dalam ModifierGroup, misal "Extra Shot" seharga Rp 5.000
+type Modifier struct {
+	ID      int    `json:"id"`
+	GroupID int    `json:"group_id"`
+	Nama    string `json:"nama"`
+	Harga   Money  `json:"harga"` // harga tambahan per unit, 0 untuk pilihan gratis seperti "Less Sugar"
+}
+
+// TransactionDetailModifier - salinan modifier yang dipilih pada baris penjualan
+type TransactionDetailModifier struct {
+	ModifierID *int   `json:"modifier_id"` // null jika modifier sudah dihapus
+	GroupNama  string `json:"group_nama"`
+	Nama       string `json:"nama"`
+	Harga      Money  `json:"harga"` // harga per unit produk
+}
+
+// MarshalJSON - Modifier beserta harga_money
+func (m Modifier) MarshalJSON() ([]byte, error) {
+	type alias Modifier
+	return json.Marshal(struct {
+		alias
+		HargaMoney MoneyObject `json:"harga_money"`
+	}{alias(m), m.Harga.Object()})
+}
+
+// MarshalJSON - TransactionDetailModifier beserta harga_money
+func (m TransactionDetailModifier) MarshalJSON() ([]byte, error) {
+	type alias TransactionDetailModifier
+	return json.Marshal(struct {
+		alias
+		HargaMoney MoneyObject `json:"harga_money"`
+	}{alias(m), m.Harga.Object()})
+}
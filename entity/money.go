@@ -0,0 +1,381 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency - mata uang jika tidak disebutkan
+const DefaultCurrency = "IDR"
+
+// Errors for Money arithmetic and parsing
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrMoneyOverflow    = errors.New("money: amount out of range")
+	ErrInvalidMoney     = errors.New("money: invalid amount")
+	ErrInvalidCurrency  = errors.New("money: currency must be a 3-letter ISO 4217 code")
+)
+
+// currencyInfo - jumlah digit minor unit dan simbol tampilan
+type currencyInfo struct {
+	digits int
+	symbol string
+}
+
+// currencies - mata uang yang dikenal, mata uang lain memakai 2 digit dan kodenya sebagai simbol
+var currencies = map[string]currencyInfo{
+	"IDR": {2, "Rp"},
+	"USD": {2, "US$"},
+	"SGD": {2, "S$"},
+	"MYR": {2, "RM"},
+	"EUR": {2, "€"},
+	"JPY": {0, "¥"},
+}
+
+// Money - nilai uang dalam minor unit (sen, 1/100 rupiah) beserta kode mata uang.
+// Semua operasi memakai integer sehingga tidak ada pembulatan float.
+//
+// JSON: angka dalam satuan utama (15000, 1250.5) seperti sebelum ada Money, agar klien
+// lama tetap jalan. Mata uang dan format dikirim terpisah sebagai MoneyObject di field
+// <nama>_money. Input menerima angka, string ("1250.50") atau objek {"amount","currency"}.
+type Money struct {
+	Amount   int64  // minor unit, Rp 15.000 = 1500000
+	Currency string // ISO 4217, kosong berarti DefaultCurrency
+}
+
+// NewMoney - Money dari minor unit dan kode mata uang
+func NewMoney(minor int64, currency string) Money {
+	return Money{Amount: minor, Currency: strings.ToUpper(currency)}
+}
+
+// IDR - Money dari jumlah rupiah utuh, IDR(15000) = Rp 15.000
+func IDR(rupiah int64) Money {
+	return Money{Amount: rupiah * 100, Currency: "IDR"}
+}
+
+// ParseMoney - parse jumlah desimal dalam satuan utama ("1250.50", "15000") tanpa float
+func ParseMoney(amount, currency string) (Money, error) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	currency = strings.ToUpper(currency)
+	if !validCurrency(currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+	digits := currencyOf(currency).digits
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || (hasFrac && (frac == "" || !isDigits(frac))) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, amount)
+	}
+	// Nol di belakang boleh ("15000.00" untuk JPY), digit bermakna melebihi presisi ditolak
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > digits {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places for %s", ErrInvalidMoney, amount, digits, currency)
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyOverflow, amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// Cur - kode mata uang, DefaultCurrency jika kosong
+func (m Money) Cur() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// IsZero - true jika jumlahnya nol
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative - true jika jumlahnya di bawah nol
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add - m + o, mata uang harus sama
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) || (o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+	currency := m.Cur()
+	if m.blank() {
+		currency = o.Cur()
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: currency}, nil
+}
+
+// Sub - m - o, mata uang harus sama
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Amount == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return m.Add(Money{Amount: -o.Amount, Currency: o.Currency})
+}
+
+// Mul - m × n, misal harga × quantity
+func (m Money) Mul(n int64) (Money, error) {
+	if m.Amount == 0 || n == 0 {
+		return Money{Amount: 0, Currency: m.Cur()}, nil
+	}
+	result := m.Amount * n
+	if result/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Amount: result, Currency: m.Cur()}, nil
+}
+
+// MulRatio - m × num / den, dibulatkan ke minor unit terdekat (setengah menjauhi nol)
+func (m Money) MulRatio(num, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, fmt.Errorf("%w: division by zero", ErrInvalidMoney)
+	}
+
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	divisor := big.NewInt(den)
+	quotient, remainder := new(big.Int).QuoRem(product, divisor, new(big.Int))
+
+	// |sisa| × 2 >= |pembagi| berarti dibulatkan menjauhi nol
+	if new(big.Int).Abs(new(big.Int).Lsh(remainder, 1)).Cmp(new(big.Int).Abs(divisor)) >= 0 {
+		if product.Sign()*divisor.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Amount: quotient.Int64(), Currency: m.Cur()}, nil
+}
+
+// Percent - m × bps / 10000 dalam basis poin, misal PPN 11% = Percent(1100)
+func (m Money) Percent(bps int64) (Money, error) {
+	return m.MulRatio(bps, 10000)
+}
+
+// Cmp - -1, 0 atau 1 jika m kurang dari, sama dengan atau lebih dari o
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Sum - jumlah semua nilai, hasil nol ber-mata uang currency jika values kosong
+func Sum(currency string, values ...Money) (Money, error) {
+	total := Money{Currency: strings.ToUpper(currency)}
+	for _, v := range values {
+		var err error
+		if total, err = total.Add(v); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Decimal - jumlah dalam satuan utama tanpa pemisah ribuan, misal "1250.50"
+func (m Money) Decimal() string {
+	whole, frac, negative := m.split()
+	s := strconv.FormatUint(whole, 10)
+	if frac != "" {
+		s += "." + frac
+	}
+	if negative {
+		s = "-" + s
+	}
+	return s
+}
+
+// Format - format Indonesia: "Rp 15.000", "Rp 1.250,50", "-Rp 5.000".
+// Desimal hanya ditampilkan jika tidak nol.
+func (m Money) Format() string {
+	whole, frac, negative := m.split()
+
+	digits := strconv.FormatUint(whole, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	if strings.Trim(frac, "0") != "" {
+		b.WriteString("," + frac)
+	}
+
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+	return sign + currencyOf(m.Cur()).symbolFor(m.Cur()) + " " + b.String()
+}
+
+// String - sama dengan Format
+func (m Money) String() string {
+	return m.Format()
+}
+
+// MarshalJSON - angka JSON tanpa nol di belakang, 15000 atau 1250.5
+func (m Money) MarshalJSON() ([]byte, error) {
+	s := m.Decimal()
+	if strings.Contains(s, ".") {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	return []byte(s), nil
+}
+
+// MoneyObject - Money lengkap di JSON: {"amount":"1250.50","currency":"IDR","formatted":"Rp 1.250,50"}
+type MoneyObject struct {
+	Amount    string `json:"amount"`
+	Currency  string `json:"currency"`
+	Formatted string `json:"formatted"`
+}
+
+// Object - m sebagai MoneyObject
+func (m Money) Object() MoneyObject {
+	return MoneyObject{Amount: m.Decimal(), Currency: m.Cur(), Formatted: m.Format()}
+}
+
+// moneyObject - Object dari m, nil jika m nil
+func moneyObject(m *Money) *MoneyObject {
+	if m == nil {
+		return nil
+	}
+	o := m.Object()
+	return &o
+}
+
+// UnmarshalJSON - terima angka (15000), string ("1250.50") atau objek {"amount","currency"}
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	amount, currency := string(data), ""
+	switch data[0] {
+	case '"':
+		if err := json.Unmarshal(data, &amount); err != nil {
+			return err
+		}
+	case '{':
+		var obj struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		amount, currency = strings.Trim(string(bytes.TrimSpace(obj.Amount)), `"`), obj.Currency
+	}
+
+	parsed, err := ParseMoney(amount, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// split - bagian utuh, pecahan (sepanjang digit minor unit) dan tanda
+func (m Money) split() (whole uint64, frac string, negative bool) {
+	digits := currencyOf(m.Cur()).digits
+	abs := uint64(m.Amount)
+	if m.Amount < 0 {
+		abs = uint64(-(m.Amount + 1)) + 1 // aman untuk MinInt64
+		negative = true
+	}
+
+	scale := uint64(1)
+	for i := 0; i < digits; i++ {
+		scale *= 10
+	}
+	whole = abs / scale
+	if digits > 0 {
+		frac = fmt.Sprintf("%0*d", digits, abs%scale)
+	}
+	return whole, frac, negative
+}
+
+// sameCurrency - nilai nol tanpa mata uang (zero value) cocok dengan mata uang apa pun
+func (m Money) sameCurrency(o Money) error {
+	if m.Cur() == o.Cur() || m.blank() || o.blank() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Cur(), o.Cur())
+}
+
+// blank - zero value Money{}, dipakai sebagai titik awal penjumlahan
+func (m Money) blank() bool {
+	return m.Amount == 0 && m.Currency == ""
+}
+
+// currencyOf - info mata uang, default 2 digit
+func currencyOf(code string) currencyInfo {
+	if info, ok := currencies[code]; ok {
+		return info
+	}
+	return currencyInfo{digits: 2}
+}
+
+// symbolFor - simbol mata uang, kode ISO jika tidak dikenal
+func (c currencyInfo) symbolFor(code string) string {
+	if c.symbol == "" {
+		return code
+	}
+	return c.symbol
+}
+
+// validCurrency - tiga huruf A-Z
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits - string berisi digit 0-9 saja
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
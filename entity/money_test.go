@@ -0,0 +1,298 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		want             Money
+	}{
+		{"15000", "", NewMoney(1500000, "IDR")},
+		{"1250.5", "idr", NewMoney(125050, "IDR")},
+		{"1250.50", "IDR", NewMoney(125050, "IDR")},
+		{" -5000 ", "IDR", NewMoney(-500000, "IDR")},
+		{"+0.01", "USD", NewMoney(1, "USD")},
+		{"15000.00", "JPY", NewMoney(15000, "JPY")},
+		{"92233720368547758.07", "IDR", NewMoney(math.MaxInt64, "IDR")},
+		{"-92233720368547758.07", "IDR", NewMoney(-math.MaxInt64, "IDR")},
+		{"1", "XYZ", NewMoney(100, "XYZ")},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.amount, tt.currency)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q, %q) = %+v, %v; want %+v", tt.amount, tt.currency, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyInvalid(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		want             error
+	}{
+		{"", "IDR", ErrInvalidMoney},
+		{"abc", "IDR", ErrInvalidMoney},
+		{"12.", "IDR", ErrInvalidMoney},
+		{".5", "IDR", ErrInvalidMoney},
+		{"1,5", "IDR", ErrInvalidMoney},
+		{"1e3", "IDR", ErrInvalidMoney},
+		{"1.005", "IDR", ErrInvalidMoney},
+		{"15000.5", "JPY", ErrInvalidMoney},
+		{"92233720368547758.08", "IDR", ErrMoneyOverflow},
+		{"1", "RUPIAH", ErrInvalidCurrency},
+		{"1", "id1", ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		if _, err := ParseMoney(tt.amount, tt.currency); !errors.Is(err, tt.want) {
+			t.Errorf("ParseMoney(%q, %q) error = %v, want %v", tt.amount, tt.currency, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	max := NewMoney(math.MaxInt64, "IDR")
+	min := NewMoney(math.MinInt64, "IDR")
+
+	if got, err := IDR(5000).Add(IDR(2500)); err != nil || got != IDR(7500) {
+		t.Errorf("Add = %v, %v", got, err)
+	}
+	if got, err := IDR(5000).Sub(IDR(7500)); err != nil || got != IDR(-2500) {
+		t.Errorf("Sub = %v, %v", got, err)
+	}
+	// Zero value mengambil mata uang lawannya
+	if got, err := (Money{}).Add(NewMoney(100, "USD")); err != nil || got.Cur() != "USD" {
+		t.Errorf("Money{}.Add = %+v, %v", got, err)
+	}
+	if _, err := IDR(1).Add(NewMoney(1, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add across currencies: %v", err)
+	}
+	if _, err := max.Add(NewMoney(1, "IDR")); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Add overflow: %v", err)
+	}
+	if _, err := min.Add(NewMoney(-1, "IDR")); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Add underflow: %v", err)
+	}
+	if _, err := IDR(0).Sub(min); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Sub MinInt64: %v", err)
+	}
+	if got, err := IDR(-5000).Mul(3); err != nil || got != IDR(-15000) {
+		t.Errorf("Mul = %v, %v", got, err)
+	}
+	if _, err := max.Mul(2); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Mul overflow: %v", err)
+	}
+	if _, err := min.Mul(-1); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Mul MinInt64 × -1: %v", err)
+	}
+	if got, err := Sum("idr"); err != nil || got != NewMoney(0, "IDR") {
+		t.Errorf("empty Sum = %+v, %v", got, err)
+	}
+}
+
+func TestMulRatioRoundsHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		amount, num, den, want int64
+	}{
+		{5, 1, 2, 3},         // 2,5 -> 3
+		{-5, 1, 2, -3},       // -2,5 -> -3
+		{5, -1, 2, -3},       // tanda di pembilang
+		{5, 1, -2, -3},       // tanda di penyebut
+		{7, 1, 3, 2},         // 2,33 -> 2
+		{-7, 1, 3, -2},       // -2,33 -> -2
+		{8, 1, 3, 3},         // 2,67 -> 3
+		{100, 1, 3, 33},      // 33,33 -> 33
+		{200, 1, 3, 67},      // 66,67 -> 67
+		{1000, 0, 7, 0},      // nol
+		{15, 1100, 10000, 2}, // PPN 11% dari 15 sen = 1,65 -> 2
+		// m × num melebihi int64 di tengah perhitungan, hasil akhir tetap muat
+		{math.MaxInt64, 3, 3, math.MaxInt64},
+	}
+	for _, tt := range tests {
+		got, err := NewMoney(tt.amount, "IDR").MulRatio(tt.num, tt.den)
+		if err != nil || got.Amount != tt.want {
+			t.Errorf("%d × %d / %d = %d, %v; want %d", tt.amount, tt.num, tt.den, got.Amount, err, tt.want)
+		}
+	}
+
+	if _, err := IDR(1).MulRatio(1, 0); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("division by zero: %v", err)
+	}
+	if _, err := NewMoney(math.MaxInt64, "IDR").MulRatio(2, 1); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("MulRatio overflow: %v", err)
+	}
+	if got, err := IDR(10000).Percent(1100); err != nil || got != IDR(1100) {
+		t.Errorf("Percent = %v, %v", got, err)
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		money   Money
+		format  string
+		decimal string
+		json    string
+	}{
+		{IDR(0), "Rp 0", "0.00", "0"},
+		{IDR(15000), "Rp 15.000", "15000.00", "15000"},
+		{NewMoney(125050, "IDR"), "Rp 1.250,50", "1250.50", "1250.5"},
+		{NewMoney(125001, "IDR"), "Rp 1.250,01", "1250.01", "1250.01"},
+		{IDR(-5000), "-Rp 5.000", "-5000.00", "-5000"},
+		{NewMoney(-50, "IDR"), "-Rp 0,50", "-0.50", "-0.5"},
+		{IDR(1000000), "Rp 1.000.000", "1000000.00", "1000000"},
+		{IDR(100), "Rp 100", "100.00", "100"},
+		{Money{Amount: 500}, "Rp 5", "5.00", "5"},
+		{NewMoney(1500, "JPY"), "¥ 1.500", "1500", "1500"},
+		{NewMoney(999, "USD"), "US$ 9,99", "9.99", "9.99"},
+		{NewMoney(100, "XYZ"), "XYZ 1", "1.00", "1"},
+		{NewMoney(math.MinInt64, "IDR"), "-Rp 92.233.720.368.547.758,08", "-92233720368547758.08", "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.Format(); got != tt.format {
+			t.Errorf("Format(%+v) = %q, want %q", tt.money, got, tt.format)
+		}
+		if got := tt.money.Decimal(); got != tt.decimal {
+			t.Errorf("Decimal(%+v) = %q, want %q", tt.money, got, tt.decimal)
+		}
+		if got, err := json.Marshal(tt.money); err != nil || string(got) != tt.json {
+			t.Errorf("MarshalJSON(%+v) = %s, %v; want %s", tt.money, got, err, tt.json)
+		}
+	}
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	for _, m := range []Money{IDR(15000), NewMoney(125050, "IDR"), IDR(-5000), NewMoney(1, "IDR"), IDR(0)} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Money
+		if err := json.Unmarshal(data, &got); err != nil || got != m {
+			t.Errorf("round trip %+v via %s = %+v, %v", m, data, got, err)
+		}
+	}
+
+	// Bentuk input yang diterima
+	tests := []struct {
+		json string
+		want Money
+	}{
+		{`15000`, IDR(15000)},
+		{`"1250.50"`, NewMoney(125050, "IDR")},
+		{`{"amount": 12.5, "currency": "usd"}`, NewMoney(1250, "USD")},
+		{`{"amount": "1250.50", "currency": "IDR", "formatted": "Rp 1.250,50"}`, NewMoney(125050, "IDR")},
+	}
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %+v, %v; want %+v", tt.json, got, err, tt.want)
+		}
+	}
+
+	// null membiarkan nilai sebelumnya, pointer tetap nil
+	var p struct {
+		Cost *Money `json:"cost"`
+	}
+	if err := json.Unmarshal([]byte(`{"cost": null}`), &p); err != nil || p.Cost != nil {
+		t.Errorf("null cost = %+v, %v", p.Cost, err)
+	}
+
+	for _, bad := range []string{`"abc"`, `1e3`, `1.005`, `{"amount": "1", "currency": "RUPIAH"}`, `true`} {
+		var got Money
+		if err := json.Unmarshal([]byte(bad), &got); err == nil {
+			t.Errorf("Unmarshal(%s) should fail, got %+v", bad, got)
+		}
+	}
+}
+
+func TestMoneySiblingObjects(t *testing.T) {
+	cost := NewMoney(300050, "IDR")
+	data, err := json.Marshal(Product{ID: 1, Nama: "Kopi", Harga: IDR(8000), HargaBeli: &cost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	// Field lama tetap angka, objek lengkap di <field>_money
+	if string(got["harga"]) != "8000" || string(got["harga_beli"]) != "3000.5" {
+		t.Errorf("numeric fields changed: harga=%s harga_beli=%s", got["harga"], got["harga_beli"])
+	}
+	var harga, hargaBeli MoneyObject
+	if err := json.Unmarshal(got["harga_money"], &harga); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got["harga_beli_money"], &hargaBeli); err != nil {
+		t.Fatal(err)
+	}
+	if harga != (MoneyObject{Amount: "8000.00", Currency: "IDR", Formatted: "Rp 8.000"}) {
+		t.Errorf("harga_money = %+v", harga)
+	}
+	if hargaBeli != (MoneyObject{Amount: "3000.50", Currency: "IDR", Formatted: "Rp 3.000,50"}) {
+		t.Errorf("harga_beli_money = %+v", hargaBeli)
+	}
+
+	// Harga beli kosong: tanpa harga_beli dan tanpa harga_beli_money
+	data, err = json.Marshal(Product{ID: 1, Nama: "Kopi", Harga: IDR(8000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["harga_beli"]; ok {
+		t.Errorf("harga_beli should be omitted: %s", data)
+	}
+	if _, ok := got["harga_beli_money"]; ok {
+		t.Errorf("harga_beli_money should be omitted: %s", data)
+	}
+
+	// Sibling hanya untuk output, body dengan *_money tetap bisa dibaca kembali
+	var back Product
+	if err := json.Unmarshal(data, &back); err != nil || back.Harga != IDR(8000) {
+		t.Errorf("product did not round trip: %+v, %v", back, err)
+	}
+
+	data, err = json.Marshal(Transaction{Subtotal: IDR(20000), DiscountAmount: IDR(2000), TaxAmount: NewMoney(198000, "IDR"), TotalAmount: NewMoney(1998000, "IDR")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	var total MoneyObject
+	if err := json.Unmarshal(got["total_amount_money"], &total); err != nil {
+		t.Fatal(err)
+	}
+	if string(got["total_amount"]) != "19980" || total.Formatted != "Rp 19.980" {
+		t.Errorf("total_amount = %s, total_amount_money = %+v", got["total_amount"], total)
+	}
+}
+
+func TestProductClearHargaBeli(t *testing.T) {
+	tests := []struct {
+		body  string
+		clear bool
+	}{
+		{`{"nama": "Kopi", "harga": 8000}`, false},
+		{`{"nama": "Kopi", "harga": 8000, "harga_beli": null}`, true},
+		{`{"nama": "Kopi", "harga": 8000, "harga_beli": 3000}`, false},
+	}
+	for _, tt := range tests {
+		var p Product
+		if err := json.Unmarshal([]byte(tt.body), &p); err != nil || p.ClearHargaBeli != tt.clear || p.Harga != IDR(8000) {
+			t.Errorf("Product %s: clear = %v, harga = %v, %v", tt.body, p.ClearHargaBeli, p.Harga, err)
+		}
+		var v ProductVariant
+		if err := json.Unmarshal([]byte(tt.body), &v); err != nil || v.ClearHargaBeli != tt.clear {
+			t.Errorf("ProductVariant %s: clear = %v, %v", tt.body, v.ClearHargaBeli, err)
+		}
+	}
+}
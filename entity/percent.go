@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPercent - persen bukan angka atau punya lebih dari 2 angka desimal
+var ErrInvalidPercent = errors.New("percent: must be a number with at most 2 decimals")
+
+// Percent - persentase dalam basis poin (1% = 100) agar diskon 12,5% tidak memakai float.
+// JSON berupa angka biasa: 10, 12.5.
+type Percent int64
+
+// ParsePercent - Percent dari teks desimal seperti "10" atau "12,5"
+func ParsePercent(s string) (Percent, error) {
+	n, ok := parseDecimal(s, 2)
+	if !ok {
+		return 0, ErrInvalidPercent
+	}
+	return Percent(n), nil
+}
+
+// Of - p persen dari m, dibulatkan ke minor unit terdekat
+func (p Percent) Of(m Money) (Money, error) {
+	return m.Percent(int64(p))
+}
+
+// String - angka desimal tanpa nol di belakang, 1250 = "12.5"
+func (p Percent) String() string {
+	return formatDecimal(int64(p), 2)
+}
+
+// MarshalJSON - angka JSON, 10 atau 12.5
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON - menerima angka atau string angka
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	text, ok := decimalJSON(data)
+	if !ok {
+		return ErrInvalidPercent
+	}
+	if text == "" {
+		return nil
+	}
+	parsed, err := ParsePercent(text)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// parseDecimal - angka desimal dengan paling banyak places angka di belakang koma sebagai
+// bilangan bulat berskala 10^places, "1,5" dengan places 3 = 1500
+func parseDecimal(s string, places int) (int64, bool) {
+	s = strings.Replace(strings.TrimSpace(s), ",", ".", 1)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" || !isDigits(whole) || len(frac) > places || (frac != "" && !isDigits(frac)) {
+		return 0, false
+	}
+	frac += strings.Repeat("0", places-len(frac))
+
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		n = -n
+	}
+	return n, true
+}
+
+// formatDecimal - kebalikan parseDecimal tanpa nol di belakang, 1500 dengan places 3 = "1.5"
+func formatDecimal(n int64, places int) string {
+	scale := int64(1)
+	for i := 0; i < places; i++ {
+		scale *= 10
+	}
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	s := sign + strconv.FormatInt(n/scale, 10)
+	if frac := n % scale; frac != 0 {
+		s += "." + strings.TrimRight(strconv.FormatInt(frac+scale, 10)[1:], "0")
+	}
+	return s
+}
+
+// decimalJSON - teks angka dari JSON number atau string, "" untuk null.
+// Notasi eksponen ditolak agar tidak ada pembulatan diam-diam.
+func decimalJSON(data []byte) (string, bool) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", true
+	}
+
+	text := string(data)
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return "", false
+		}
+		return text, text != ""
+	}
+	return text, !strings.ContainsAny(text, "eE")
+}
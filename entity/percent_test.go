@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Percent
+		text string
+	}{
+		{"10", 1000, "10"},
+		{"12,5", 1250, "12.5"},
+		{"0.01", 1, "0.01"},
+		{"100", 10000, "100"},
+	}
+	for _, tt := range tests {
+		got, err := ParsePercent(tt.in)
+		if err != nil || got != tt.want || got.String() != tt.text {
+			t.Errorf("ParsePercent(%q) = %d (%s), %v; want %d (%s)", tt.in, got, got, err, tt.want, tt.text)
+		}
+	}
+	for _, bad := range []string{"", "12.345", "x", "1e2"} {
+		if _, err := ParsePercent(bad); err == nil {
+			t.Errorf("ParsePercent(%q) should fail", bad)
+		}
+	}
+
+	// PPN 11% dari Rp 1.250,50 = 137,555 -> Rp 137,56
+	if got, err := Percent(1100).Of(NewMoney(125050, "IDR")); err != nil || got.Amount != 13756 {
+		t.Errorf("Of = %d, %v", got.Amount, err)
+	}
+
+	var p Percent
+	if err := json.Unmarshal([]byte(`"12.5"`), &p); err != nil || p != 1250 {
+		t.Errorf(`Unmarshal("12.5") = %d, %v`, p, err)
+	}
+	if data, _ := json.Marshal(Percent(1250)); string(data) != "12.5" {
+		t.Errorf("Marshal(1250) = %s", data)
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProductPrice - harga jual produk dalam rentang waktu [EffectiveFrom, EffectiveTo).
+// Rentang satu produk tidak tumpang tindih; EffectiveTo nil berarti berlaku sampai ada harga baru.
+type ProductPrice struct {
+	ID            int        `json:"id"`
+	ProductID     int        `json:"product_id"`
+	Harga         Money      `json:"harga"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// SchedulePriceRequest - body POST /api/produk/{id}/prices, effective_from kosong berarti sekarang
+type SchedulePriceRequest struct {
+	Harga         Money     `json:"harga"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+// MarshalJSON - ProductPrice beserta harga_money
+func (p ProductPrice) MarshalJSON() ([]byte, error) {
+	type alias ProductPrice
+	return json.Marshal(struct {
+		alias
+		HargaMoney MoneyObject `json:"harga_money"`
+	}{alias(p), p.Harga.Object()})
+}
+
+// CoversTime - true jika harga berlaku pada waktu t
+func (p ProductPrice) CoversTime(t time.Time) bool {
+	return !t.Before(p.EffectiveFrom) && (p.EffectiveTo == nil || t.Before(*p.EffectiveTo))
+}
@@ -1,9 +1,92 @@
 package entity
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Jenis produk
+const (
+	ProductTypeSingle = "single" // produk biasa
+	ProductTypeBundle = "bundle" // paket berisi produk lain dengan harga sendiri
+)
+
 type Product struct {
-	ID         int     `json:"id"`
-	Nama       string  `json:"nama"`
-	Harga      int     `json:"harga"`
-	CategoryID int     `json:"category_id"`
-	Category   *Category `json:"category,omitempty"`
+	ID           int               `json:"id"`
+	Nama         string            `json:"nama"`
+	Type         string            `json:"type"` // single atau bundle
+	Harga        Money             `json:"harga"`
+	HargaBeli    *Money            `json:"harga_beli,omitempty"`    // harga pokok, hanya untuk manager; null = belum diketahui
+	Unit         string            `json:"unit"`                    // satuan dasar: harga dan stok per satuan ini
+	PurchaseUnit string            `json:"purchase_unit,omitempty"` // satuan beli, default untuk penyesuaian stok
+	Units        []UnitConversion  `json:"units,omitempty"`         // satuan lain yang bisa dipakai, misal 1 dus = 24 pcs
+	Stock        *Quantity         `json:"stock,omitempty"`         // dalam satuan dasar, null = stok tidak dilacak
+	CategoryID   *int              `json:"category_id"`             // null = tanpa kategori
+	Image        *ProductImage     `json:"image,omitempty"`         // null = belum ada gambar
+	Category     *Category         `json:"category,omitempty"`
+	Variants     []ProductVariant  `json:"variants,omitempty"`
+	Components   []BundleComponent `json:"components,omitempty"` // isi paket, hanya untuk bundle
+
+	ClearHargaBeli bool `json:"-"` // request mengirim "harga_beli": null, harga beli tersimpan dihapus
+}
+
+// MarshalJSON - Product beserta harga_money dan harga_beli_money
+func (p Product) MarshalJSON() ([]byte, error) {
+	type alias Product
+	return json.Marshal(struct {
+		alias
+		HargaMoney     MoneyObject  `json:"harga_money"`
+		HargaBeliMoney *MoneyObject `json:"harga_beli_money,omitempty"`
+	}{alias(p), p.Harga.Object(), moneyObject(p.HargaBeli)})
+}
+
+// UnmarshalJSON - Product dari request; "harga_beli": null dibedakan dari harga_beli yang
+// tidak dikirim agar update bisa menghapus harga beli
+func (p *Product) UnmarshalJSON(data []byte) error {
+	type alias Product
+	if err := json.Unmarshal(data, (*alias)(p)); err != nil {
+		return err
+	}
+	p.ClearHargaBeli = p.HargaBeli == nil && sentNull(data, "harga_beli")
+	return nil
+}
+
+// sentNull - true jika objek JSON data berisi field bernilai null
+func sentNull(data []byte, field string) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return false
+	}
+	value, ok := fields[field]
+	return ok && bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// ProductImage - gambar produk untuk tile kasir beserta thumbnail-nya. Key adalah lokasi file
+// di storage; URL diisi dari storage yang dipakai saat produk dibaca.
+type ProductImage struct {
+	Key          string `json:"-"`
+	ThumbnailKey string `json:"-"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// BundleComponent - satu produk (atau varian) dalam bundle beserta jumlahnya per paket,
+// misal Paket Hemat berisi 1 Nasi Goreng + 1 Es Teh
+type BundleComponent struct {
+	ProductID int    `json:"product_id"`
+	VariantID *int   `json:"variant_id,omitempty"` // wajib jika produk komponen punya varian
+	Quantity  int    `json:"quantity"`
+	Nama      string `json:"nama,omitempty"` // nama produk (dan varian), hanya dibaca
+}
+
+// IsBundle reports whether the product is a bundle of other products
+func (p Product) IsBundle() bool {
+	return p.Type == ProductTypeBundle
+}
+
+// ProductFilter narrows product listings
+type ProductFilter struct {
+	CategoryID           int  // 0 = semua kategori
+	IncludeSubcategories bool // CategoryID beserta semua sub-kategorinya
+	Uncategorized        bool // hanya produk tanpa kategori (category_id NULL)
 }
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis promosi
+const (
+	PromotionPercentage = "percentage" // diskon persen dari harga barang dalam scope
+	PromotionFixed      = "fixed"      // potongan per unit barang dalam scope, sekali per keranjang untuk scope cart
+	PromotionBOGO       = "bogo"       // beli buy_quantity gratis get_quantity, yang gratis selalu yang termurah
+	PromotionThreshold  = "threshold"  // potongan amount atau percent sekali jika belanja dalam scope >= min_subtotal
+)
+
+// Cakupan promosi
+const (
+	PromotionScopeProduct  = "product"
+	PromotionScopeCategory = "category" // berlaku juga untuk sub-kategori
+	PromotionScopeCart     = "cart"     // seluruh keranjang
+)
+
+// Promotion - aturan diskon yang dipakai otomatis saat keranjang dihitung, misal
+// "Beli 2 Gratis 1", "Snack 10% setiap akhir pekan" atau "Potongan Rp 5.000 di atas Rp 50.000"
+type Promotion struct {
+	ID          int        `json:"id"`
+	Nama        string     `json:"nama"` // dicetak di struk
+	Type        string     `json:"type"`
+	Scope       string     `json:"scope"`
+	ProductID   *int       `json:"product_id,omitempty"`   // wajib untuk scope product
+	CategoryID  *int       `json:"category_id,omitempty"`  // wajib untuk scope category
+	Percent     Percent    `json:"percent,omitempty"`      // percentage, atau threshold dengan diskon persen
+	Amount      *Money     `json:"amount,omitempty"`       // fixed, atau threshold dengan potongan nominal
+	MinSubtotal *Money     `json:"min_subtotal,omitempty"` // threshold: belanja minimal dalam scope
+	BuyQuantity int        `json:"buy_quantity,omitempty"` // bogo: jumlah yang dibayar
+	GetQuantity int        `json:"get_quantity,omitempty"` // bogo: jumlah yang gratis
+	StartsAt    *time.Time `json:"starts_at"`              // null = berlaku sejak dibuat
+	EndsAt      *time.Time `json:"ends_at"`                // eksklusif, null = tanpa batas akhir
+	// Days - hari berlaku 0 (Minggu) sampai 6 (Sabtu) dalam zona waktu server, kosong = setiap hari
+	Days     []int `json:"days,omitempty"`
+	Priority int   `json:"priority"` // lebih besar dihitung lebih dulu
+	// Stackable - boleh menambah diskon pada barang yang sudah didiskon promosi lain.
+	// Promosi yang tidak stackable hanya untuk barang tanpa diskon dan mengunci barang itu.
+	Stackable bool `json:"stackable"`
+}
+
+// MarshalJSON - Promotion beserta amount_money dan min_subtotal_money
+func (p Promotion) MarshalJSON() ([]byte, error) {
+	type alias Promotion
+	return json.Marshal(struct {
+		alias
+		AmountMoney      *MoneyObject `json:"amount_money,omitempty"`
+		MinSubtotalMoney *MoneyObject `json:"min_subtotal_money,omitempty"`
+	}{alias(p), moneyObject(p.Amount), moneyObject(p.MinSubtotal)})
+}
+
+// ActiveOn - promosi berlaku pada waktu at: dalam rentang starts_at/ends_at dan pada
+// salah satu Days menurut zona waktu loc
+func (p Promotion) ActiveOn(at time.Time, loc *time.Location) bool {
+	if p.StartsAt != nil && at.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return false
+	}
+	if len(p.Days) == 0 {
+		return true
+	}
+	weekday := int(at.In(loc).Weekday())
+	for _, day := range p.Days {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// TransactionDiscount - promosi atau voucher yang dipakai pada transaksi beserta total potongannya
+type TransactionDiscount struct {
+	PromotionID *int   `json:"promotion_id"`         // null untuk voucher atau jika promosi sudah dihapus
+	VoucherID   *int   `json:"voucher_id,omitempty"` // voucher yang dipakai, null jika sudah dihapus
+	Nama        string `json:"nama"`
+	Amount      Money  `json:"amount"`
+}
+
+// MarshalJSON - TransactionDiscount beserta amount_money
+func (d TransactionDiscount) MarshalJSON() ([]byte, error) {
+	type alias TransactionDiscount
+	return json.Marshal(struct {
+		alias
+		AmountMoney MoneyObject `json:"amount_money"`
+	}{alias(d), d.Amount.Object()})
+}
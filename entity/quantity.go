@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrInvalidQuantity - jumlah bukan angka atau punya lebih dari 3 angka desimal
+var ErrInvalidQuantity = errors.New("quantity: must be a number with at most 3 decimals")
+
+// quantityScale - Quantity disimpan dalam seperseribu unit
+const quantityScale = 1000
+
+// Quantity - jumlah barang dalam seperseribu unit (1,5 kg = 1500) agar penjualan
+// per berat tidak memakai float. JSON berupa angka biasa: 2, 0.5, 1.25.
+type Quantity int64
+
+// Qty - Quantity dari jumlah unit utuh, Qty(24) = 24 pcs
+func Qty(units int64) Quantity {
+	return Quantity(units * quantityScale)
+}
+
+// ParseQuantity - Quantity dari teks desimal seperti "1.5" atau "0,25"
+func ParseQuantity(s string) (Quantity, error) {
+	n, ok := parseDecimal(s, 3)
+	if !ok {
+		return 0, ErrInvalidQuantity
+	}
+	return Quantity(n), nil
+}
+
+// IsWhole - tidak ada bagian desimal
+func (q Quantity) IsWhole() bool {
+	return q%quantityScale == 0
+}
+
+// Units - jumlah unit utuh, bagian desimal dibuang
+func (q Quantity) Units() int64 {
+	return int64(q) / quantityScale
+}
+
+// Convert - q × factor, misal 2 dus × 24 pcs = 48 pcs. Dibulatkan ke seperseribu
+// terdekat; false jika hasilnya di luar jangkauan.
+func (q Quantity) Convert(factor Quantity) (Quantity, bool) {
+	product := new(big.Int).Mul(big.NewInt(int64(q)), big.NewInt(int64(factor)))
+	half := big.NewInt(quantityScale / 2)
+	if product.Sign() < 0 {
+		half.Neg(half)
+	}
+	result := product.Add(product, half).Quo(product, big.NewInt(quantityScale))
+	if !result.IsInt64() {
+		return 0, false
+	}
+	return Quantity(result.Int64()), true
+}
+
+// Price - harga satu unit × q, dibulatkan ke minor unit terdekat
+func (q Quantity) Price(unit Money) (Money, error) {
+	return unit.MulRatio(int64(q), quantityScale)
+}
+
+// String - angka desimal tanpa nol di belakang, 1500 = "1.5"
+func (q Quantity) String() string {
+	return formatDecimal(int64(q), 3)
+}
+
+// MarshalJSON - angka JSON, 2 atau 0.5
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON - menerima angka atau string angka
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	text, ok := decimalJSON(data)
+	if !ok {
+		return ErrInvalidQuantity
+	}
+	if text == "" {
+		return nil
+	}
+	parsed, err := ParseQuantity(text)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
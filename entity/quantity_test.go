@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Quantity
+	}{
+		{"2", Qty(2)},
+		{"1.5", 1500},
+		{"0,25", 250},
+		{"0.001", 1},
+		{"-1", Qty(-1)},
+	}
+	for _, tt := range tests {
+		if got, err := ParseQuantity(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseQuantity(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "1.0005", "abc", "1e3", ".5", "9223372036854776"} {
+		if _, err := ParseQuantity(bad); err == nil {
+			t.Errorf("ParseQuantity(%q) should fail", bad)
+		}
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	for _, q := range []Quantity{Qty(2), 1500, 250, 1, Qty(-3), 0} {
+		data, err := json.Marshal(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Quantity
+		if err := json.Unmarshal(data, &got); err != nil || got != q {
+			t.Errorf("round trip %d via %s = %d, %v", q, data, got, err)
+		}
+	}
+	if data, _ := json.Marshal(Quantity(1500)); string(data) != "1.5" {
+		t.Errorf("Marshal(1500) = %s", data)
+	}
+
+	var q Quantity
+	if err := json.Unmarshal([]byte(`"0,5"`), &q); err != nil || q != 500 {
+		t.Errorf(`Unmarshal("0,5") = %d, %v`, q, err)
+	}
+	if err := json.Unmarshal([]byte(`1e3`), &q); err == nil {
+		t.Error("exponent notation should be rejected")
+	}
+}
+
+func TestQuantityConvertAndPrice(t *testing.T) {
+	// 2 dus × 24 pcs
+	if got, ok := Qty(2).Convert(Qty(24)); !ok || got != Qty(48) {
+		t.Errorf("Convert = %d, %v", got, ok)
+	}
+	// 0,333 × 0,5 = 0,1665 -> 0,167 (setengah menjauhi nol)
+	if got, ok := Quantity(333).Convert(500); !ok || got != 167 {
+		t.Errorf("Convert rounding = %d, %v", got, ok)
+	}
+	if got, ok := Quantity(-333).Convert(500); !ok || got != -167 {
+		t.Errorf("Convert negative rounding = %d, %v", got, ok)
+	}
+	if _, ok := Quantity(math.MaxInt64).Convert(Qty(2)); ok {
+		t.Error("Convert overflow should fail")
+	}
+
+	// 1,5 kg × Rp 12.000 = Rp 18.000; 0,333 × Rp 0,10 = 3,33 sen -> 3 sen
+	if got, err := Quantity(1500).Price(IDR(12000)); err != nil || got != IDR(18000) {
+		t.Errorf("Price = %v, %v", got, err)
+	}
+	if got, err := Quantity(333).Price(NewMoney(10, "IDR")); err != nil || got.Amount != 3 {
+		t.Errorf("Price rounding = %d, %v", got.Amount, err)
+	}
+	if !Qty(3).IsWhole() || Quantity(1500).IsWhole() || Quantity(2500).Units() != 2 {
+		t.Error("IsWhole/Units")
+	}
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Margin report groupings
+const (
+	MarginByProduct  = "product"
+	MarginByCategory = "category"
+)
+
+// MarginFilter - pengelompokan dan rentang waktu laporan margin
+type MarginFilter struct {
+	GroupBy string    // MarginByProduct (default) atau MarginByCategory
+	From    time.Time // zero = tanpa batas bawah
+	To      time.Time // eksklusif, zero = tanpa batas atas
+}
+
+// MarginRow - margin kotor satu produk atau kategori dalam satu mata uang
+type MarginRow struct {
+	ID       *int     `json:"id"` // null untuk produk yang sudah dihapus atau produk tanpa kategori
+	Name     string   `json:"name"`
+	Quantity Quantity `json:"quantity"` // dalam satuan dasar produk
+	Revenue  Money    `json:"revenue"`
+	Cost     Money    `json:"cost"`
+	// UncostedRevenue - pendapatan dari baris tanpa harga beli, tidak ikut dihitung dalam margin
+	UncostedRevenue Money   `json:"uncosted_revenue"`
+	GrossMargin     Money   `json:"gross_margin"`
+	MarginPercent   float64 `json:"margin_percent"` // margin / pendapatan yang harga belinya diketahui
+}
+
+// MarshalJSON - MarginRow beserta objek Money setiap jumlahnya
+func (r MarginRow) MarshalJSON() ([]byte, error) {
+	type alias MarginRow
+	return json.Marshal(struct {
+		alias
+		RevenueMoney         MoneyObject `json:"revenue_money"`
+		CostMoney            MoneyObject `json:"cost_money"`
+		UncostedRevenueMoney MoneyObject `json:"uncosted_revenue_money"`
+		GrossMarginMoney     MoneyObject `json:"gross_margin_money"`
+	}{alias(r), r.Revenue.Object(), r.Cost.Object(), r.UncostedRevenue.Object(), r.GrossMargin.Object()})
+}
+
+// TaxFilter - rentang waktu laporan pajak
+type TaxFilter struct {
+	From time.Time // zero = tanpa batas bawah
+	To   time.Time // eksklusif, zero = tanpa batas atas
+}
+
+// TaxRow - total satu tarif pajak dalam satu mata uang
+type TaxRow struct {
+	Nama          string  `json:"nama"`
+	Rate          Percent `json:"rate"`
+	Inclusive     bool    `json:"inclusive"`
+	Transactions  int     `json:"transactions"`   // jumlah transaksi yang dikenai pajak ini
+	TaxableAmount Money   `json:"taxable_amount"` // dasar pengenaan pajak
+	Amount        Money   `json:"amount"`
+}
+
+// MarshalJSON - TaxRow beserta taxable_amount_money dan amount_money
+func (r TaxRow) MarshalJSON() ([]byte, error) {
+	type alias TaxRow
+	return json.Marshal(struct {
+		alias
+		TaxableAmountMoney MoneyObject `json:"taxable_amount_money"`
+		AmountMoney        MoneyObject `json:"amount_money"`
+	}{alias(r), r.TaxableAmount.Object(), r.Amount.Object()})
+}
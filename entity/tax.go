@@ -0,0 +1,41 @@
+package entity
+
+import "encoding/json"
+
+// TaxRate - pajak atau biaya layanan yang dihitung otomatis setelah diskon, misal
+// PPN 11% atau service charge 5%
+type TaxRate struct {
+	ID   int     `json:"id"`
+	Nama string  `json:"nama"` // dicetak di struk
+	Rate Percent `json:"rate"`
+	// Inclusive - harga jual sudah termasuk pajak ini: pajak dihitung dari dalam harga dan
+	// total tidak bertambah. Exclusive ditambahkan di atas harga.
+	Inclusive bool `json:"inclusive"`
+	// Compound - dihitung dari harga ditambah pajak exclusive sebelumnya, misal PPN atas
+	// service charge. Hanya untuk pajak exclusive.
+	Compound bool `json:"compound"`
+	Position int  `json:"position"` // urutan hitung, kecil lebih dulu
+	// ExemptCategoryIDs - kategori (beserta sub-kategorinya) yang bebas pajak ini
+	ExemptCategoryIDs []int `json:"exempt_category_ids"`
+}
+
+// TransactionTax - pajak yang dikenakan pada transaksi, untuk struk dan laporan pajak
+type TransactionTax struct {
+	TaxRateID *int    `json:"tax_rate_id"` // null jika tarif sudah dihapus
+	Nama      string  `json:"nama"`
+	Rate      Percent `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	// TaxableAmount - dasar pengenaan pajak, untuk pajak inclusive sudah tanpa pajaknya
+	TaxableAmount Money `json:"taxable_amount"`
+	Amount        Money `json:"amount"`
+}
+
+// MarshalJSON - TransactionTax beserta taxable_amount_money dan amount_money
+func (t TransactionTax) MarshalJSON() ([]byte, error) {
+	type alias TransactionTax
+	return json.Marshal(struct {
+		alias
+		TaxableAmountMoney MoneyObject `json:"taxable_amount_money"`
+		AmountMoney        MoneyObject `json:"amount_money"`
+	}{alias(t), t.TaxableAmount.Object(), t.Amount.Object()})
+}
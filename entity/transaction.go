@@ -0,0 +1,141 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Transaction struct {
+	ID             int                   `json:"id"`
+	Subtotal       Money                 `json:"subtotal"`        // jumlah subtotal baris sebelum diskon
+	DiscountAmount Money                 `json:"discount_amount"` // jumlah semua diskon
+	TaxAmount      Money                 `json:"tax_amount"`      // jumlah pajak exclusive yang ditambahkan
+	TotalAmount    Money                 `json:"total_amount"`    // subtotal - discount_amount + tax_amount
+	CreatedAt      time.Time             `json:"created_at"`
+	Details        []TransactionDetail   `json:"details,omitempty"`
+	Discounts      []TransactionDiscount `json:"discounts,omitempty"` // promosi yang dipakai
+	Taxes          []TransactionTax      `json:"taxes,omitempty"`     // semua pajak, termasuk yang inclusive
+}
+
+// MarshalJSON - Transaction beserta objek Money setiap total
+func (t Transaction) MarshalJSON() ([]byte, error) {
+	type alias Transaction
+	return json.Marshal(struct {
+		alias
+		SubtotalMoney       MoneyObject `json:"subtotal_money"`
+		DiscountAmountMoney MoneyObject `json:"discount_amount_money"`
+		TaxAmountMoney      MoneyObject `json:"tax_amount_money"`
+		TotalAmountMoney    MoneyObject `json:"total_amount_money"`
+	}{alias(t), t.Subtotal.Object(), t.DiscountAmount.Object(), t.TaxAmount.Object(), t.TotalAmount.Object()})
+}
+
+type TransactionDetail struct {
+	ID            int      `json:"id"`
+	TransactionID int      `json:"transaction_id"`
+	ProductID     *int     `json:"product_id"`           // null jika produk sudah dihapus
+	VariantID     *int     `json:"variant_id,omitempty"` // varian yang terjual, null untuk produk tanpa varian
+	NamaProduk    string   `json:"nama_produk"`
+	Harga         Money    `json:"harga"`
+	HargaBeli     *Money   `json:"harga_beli,omitempty"` // salinan harga beli per unit saat penjualan, hanya untuk manager
+	Quantity      Quantity `json:"quantity"`
+	Unit          string   `json:"unit"` // satuan jual baris ini, harga per satuan ini
+	// BaseQuantity - quantity dalam satuan dasar produk, yang dipakai untuk stok dan laporan
+	BaseQuantity Quantity `json:"base_quantity"`
+	Subtotal     Money    `json:"subtotal"` // (harga + harga modifier) x quantity
+	Discount     Money    `json:"discount"` // bagian baris ini dari semua diskon, pendapatan bersih = subtotal - discount
+	// TaxIncluded - bagian baris ini dari pajak inclusive yang sudah termasuk dalam harga,
+	// pendapatan tanpa pajak = subtotal - discount - tax_included
+	TaxIncluded Money `json:"tax_included"`
+	// Modifiers - add-on yang dipilih, dicetak di struk di bawah nama produk
+	Modifiers []TransactionDetailModifier `json:"modifiers,omitempty"`
+}
+
+// MarshalJSON - TransactionDetail beserta objek Money setiap jumlah
+func (d TransactionDetail) MarshalJSON() ([]byte, error) {
+	type alias TransactionDetail
+	return json.Marshal(struct {
+		alias
+		HargaMoney       MoneyObject  `json:"harga_money"`
+		HargaBeliMoney   *MoneyObject `json:"harga_beli_money,omitempty"`
+		SubtotalMoney    MoneyObject  `json:"subtotal_money"`
+		DiscountMoney    MoneyObject  `json:"discount_money"`
+		TaxIncludedMoney MoneyObject  `json:"tax_included_money"`
+	}{alias(d), d.Harga.Object(), moneyObject(d.HargaBeli), d.Subtotal.Object(), d.Discount.Object(), d.TaxIncluded.Object()})
+}
+
+// CheckoutItem - satu baris keranjang yang akan dibayar
+type CheckoutItem struct {
+	ProductID int      `json:"product_id"`
+	VariantID *int     `json:"variant_id,omitempty"` // wajib untuk produk yang punya varian
+	Quantity  Quantity `json:"quantity"`             // desimal (0.5 kg) hanya jika satuannya mengizinkan
+	Unit      string   `json:"unit,omitempty"`       // satuan jual, kosong = satuan dasar produk
+	Modifiers []int    `json:"modifiers,omitempty"`  // ID modifier yang dipilih, misal extra shot + less sugar
+}
+
+// CheckoutRequest - body POST /api/checkout
+type CheckoutRequest struct {
+	Items       []CheckoutItem `json:"items"`
+	VoucherCode string         `json:"voucher_code,omitempty"`
+	Customer    string         `json:"customer,omitempty"` // nomor HP atau ID member, untuk batas voucher per pelanggan
+}
+
+// CartPrice - hasil POST /api/cart/price: keranjang yang dihitung seperti checkout tetapi
+// tidak disimpan, jadi tanpa ID, waktu dan harga beli
+type CartPrice struct {
+	Subtotal       Money                 `json:"subtotal"`
+	DiscountAmount Money                 `json:"discount_amount"`
+	TaxAmount      Money                 `json:"tax_amount"`
+	TotalAmount    Money                 `json:"total_amount"`
+	Details        []CartLine            `json:"details"`
+	Discounts      []TransactionDiscount `json:"discounts,omitempty"`
+	Taxes          []TransactionTax      `json:"taxes,omitempty"`
+	// StockShortages - stok yang saat ini tidak cukup; checkout keranjang ini akan ditolak
+	StockShortages []StockShortage `json:"stock_shortages,omitempty"`
+}
+
+// MarshalJSON - CartPrice beserta objek Money setiap total
+func (c CartPrice) MarshalJSON() ([]byte, error) {
+	type alias CartPrice
+	return json.Marshal(struct {
+		alias
+		SubtotalMoney       MoneyObject `json:"subtotal_money"`
+		DiscountAmountMoney MoneyObject `json:"discount_amount_money"`
+		TaxAmountMoney      MoneyObject `json:"tax_amount_money"`
+		TotalAmountMoney    MoneyObject `json:"total_amount_money"`
+	}{alias(c), c.Subtotal.Object(), c.DiscountAmount.Object(), c.TaxAmount.Object(), c.TotalAmount.Object()})
+}
+
+// CartLine - satu baris CartPrice, field sama dengan TransactionDetail
+type CartLine struct {
+	ProductID    int                         `json:"product_id"`
+	VariantID    *int                        `json:"variant_id,omitempty"`
+	NamaProduk   string                      `json:"nama_produk"`
+	Harga        Money                       `json:"harga"`
+	Quantity     Quantity                    `json:"quantity"`
+	Unit         string                      `json:"unit"`
+	BaseQuantity Quantity                    `json:"base_quantity"`
+	Subtotal     Money                       `json:"subtotal"`
+	Discount     Money                       `json:"discount"`
+	TaxIncluded  Money                       `json:"tax_included"`
+	Modifiers    []TransactionDetailModifier `json:"modifiers,omitempty"`
+}
+
+// MarshalJSON - CartLine beserta objek Money setiap jumlah
+func (l CartLine) MarshalJSON() ([]byte, error) {
+	type alias CartLine
+	return json.Marshal(struct {
+		alias
+		HargaMoney       MoneyObject `json:"harga_money"`
+		SubtotalMoney    MoneyObject `json:"subtotal_money"`
+		DiscountMoney    MoneyObject `json:"discount_money"`
+		TaxIncludedMoney MoneyObject `json:"tax_included_money"`
+	}{alias(l), l.Harga.Object(), l.Subtotal.Object(), l.Discount.Object(), l.TaxIncluded.Object()})
+}
+
+// StockShortage - stok produk atau varian yang kurang untuk keranjang, dalam satuan dasar
+type StockShortage struct {
+	ProductID int      `json:"product_id"`
+	VariantID *int     `json:"variant_id,omitempty"`
+	Requested Quantity `json:"requested"` // jumlah kebutuhan semua baris keranjang
+	Available Quantity `json:"available"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+// DefaultUnit - satuan dasar produk jika tidak disebutkan
+const DefaultUnit = "pcs"
+
+// Unit - satuan barang, misal pcs, kg atau dus. Hanya satuan dengan AllowDecimal
+// yang boleh dijual atau distok dalam jumlah desimal (0,5 kg gula).
+type Unit struct {
+	Code         string `json:"code"`
+	Nama         string `json:"nama"`
+	AllowDecimal bool   `json:"allow_decimal"`
+}
+
+// UnitConversion - satu satuan lain dari produk: 1 Unit = Factor × satuan dasar produk,
+// misal {"unit":"dus","factor":24} untuk produk dengan satuan dasar pcs
+type UnitConversion struct {
+	Unit   string   `json:"unit"`
+	Factor Quantity `json:"factor"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import "encoding/json"
+
+// ProductVariant - varian produk (ukuran, rasa) dengan SKU, harga dan stok sendiri,
+// misal "Es Teh Manis" varian "Jumbo" dengan options {"ukuran":"jumbo"}
+type ProductVariant struct {
+	ID        int               `json:"id"`
+	ProductID int               `json:"product_id"`
+	SKU       string            `json:"sku"`
+	Nama      string            `json:"nama"`
+	Options   map[string]string `json:"options"`
+	Harga     Money             `json:"harga"`
+	HargaBeli *Money            `json:"harga_beli,omitempty"` // harga pokok varian, hanya untuk manager; null = belum diketahui
+	Stock     *Quantity         `json:"stock"`                // dalam satuan dasar produk; null saat update = tidak berubah
+
+	ClearHargaBeli bool `json:"-"` // request mengirim "harga_beli": null, harga beli tersimpan dihapus
+}
+
+// MarshalJSON - ProductVariant beserta harga_money dan harga_beli_money
+func (v ProductVariant) MarshalJSON() ([]byte, error) {
+	type alias ProductVariant
+	return json.Marshal(struct {
+		alias
+		HargaMoney     MoneyObject  `json:"harga_money"`
+		HargaBeliMoney *MoneyObject `json:"harga_beli_money,omitempty"`
+	}{alias(v), v.Harga.Object(), moneyObject(v.HargaBeli)})
+}
+
+// UnmarshalJSON - ProductVariant dari request, "harga_beli": null menghapus harga beli saat update
+func (v *ProductVariant) UnmarshalJSON(data []byte) error {
+	type alias ProductVariant
+	if err := json.Unmarshal(data, (*alias)(v)); err != nil {
+		return err
+	}
+	v.ClearHargaBeli = v.HargaBeli == nil && sentNull(data, "harga_beli")
+	return nil
+}
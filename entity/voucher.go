@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis potongan voucher
+const (
+	VoucherPercentage = "percentage" // persen dari total setelah promosi, dibatasi max_discount
+	VoucherFixed      = "fixed"      // potongan nominal, paling banyak sebesar total
+)
+
+// Voucher - kode kupon seperti "MERDEKA17" yang dimasukkan kasir saat checkout. Potongan
+// dihitung dari total keranjang setelah promosi otomatis.
+type Voucher struct {
+	ID          int     `json:"id"`
+	Code        string  `json:"code"` // huruf besar, unik
+	Nama        string  `json:"nama"` // dicetak di struk
+	Type        string  `json:"type"`
+	Percent     Percent `json:"percent,omitempty"`      // wajib untuk percentage
+	Amount      *Money  `json:"amount,omitempty"`       // wajib untuk fixed
+	MaxDiscount *Money  `json:"max_discount,omitempty"` // batas potongan percentage, null = tanpa batas
+	MinSubtotal *Money  `json:"min_subtotal,omitempty"` // belanja minimal setelah promosi
+	// UsageLimit - total pemakaian untuk semua pelanggan, null = tanpa batas
+	UsageLimit *int `json:"usage_limit"`
+	// PerCustomerLimit - pemakaian per pelanggan, null = tanpa batas. Checkout dengan voucher
+	// yang punya batas ini wajib menyebutkan customer.
+	PerCustomerLimit *int       `json:"per_customer_limit"`
+	UsedCount        int        `json:"used_count"` // hanya dibaca, bertambah saat checkout
+	StartsAt         *time.Time `json:"starts_at"`  // null = berlaku sejak dibuat
+	ExpiresAt        *time.Time `json:"expires_at"` // eksklusif, null = tidak kedaluwarsa
+}
+
+// MarshalJSON - Voucher beserta objek Money untuk amount, max_discount dan min_subtotal
+func (v Voucher) MarshalJSON() ([]byte, error) {
+	type alias Voucher
+	return json.Marshal(struct {
+		alias
+		AmountMoney      *MoneyObject `json:"amount_money,omitempty"`
+		MaxDiscountMoney *MoneyObject `json:"max_discount_money,omitempty"`
+		MinSubtotalMoney *MoneyObject `json:"min_subtotal_money,omitempty"`
+	}{alias(v), moneyObject(v.Amount), moneyObject(v.MaxDiscount), moneyObject(v.MinSubtotal)})
+}
+
+// VoucherRedemption - satu pemakaian voucher pada transaksi
+type VoucherRedemption struct {
+	ID            int       `json:"id"`
+	VoucherID     int       `json:"voucher_id"`
+	TransactionID int       `json:"transaction_id"`
+	Customer      string    `json:"customer,omitempty"`
+	Amount        Money     `json:"amount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// MarshalJSON - VoucherRedemption beserta amount_money
+func (r VoucherRedemption) MarshalJSON() ([]byte, error) {
+	type alias VoucherRedemption
+	return json.Marshal(struct {
+		alias
+		AmountMoney MoneyObject `json:"amount_money"`
+	}{alias(r), r.Amount.Object()})
+}
+
+// VoucherValidateRequest - body POST /api/vouchers/validate
+type VoucherValidateRequest struct {
+	Code     string         `json:"code"`
+	Customer string         `json:"customer,omitempty"`
+	Items    []CheckoutItem `json:"items"`
+}
+
+// VoucherPreview - potongan voucher untuk keranjang tanpa menyimpan transaksi
+type VoucherPreview struct {
+	Code              string `json:"code"`
+	Nama              string `json:"nama"`
+	Subtotal          Money  `json:"subtotal"`           // sebelum diskon
+	PromotionDiscount Money  `json:"promotion_discount"` // diskon promosi otomatis
+	Discount          Money  `json:"discount"`           // potongan voucher
+	TaxAmount         Money  `json:"tax_amount"`         // pajak exclusive setelah semua diskon
+	TotalAmount       Money  `json:"total_amount"`       // yang dibayar, sama dengan checkout
+}
+
+// MarshalJSON - VoucherPreview beserta objek Money setiap jumlah
+func (p VoucherPreview) MarshalJSON() ([]byte, error) {
+	type alias VoucherPreview
+	return json.Marshal(struct {
+		alias
+		SubtotalMoney          MoneyObject `json:"subtotal_money"`
+		PromotionDiscountMoney MoneyObject `json:"promotion_discount_money"`
+		DiscountMoney          MoneyObject `json:"discount_money"`
+		TaxAmountMoney         MoneyObject `json:"tax_amount_money"`
+		TotalAmountMoney       MoneyObject `json:"total_amount_money"`
+	}{alias(p), p.Subtotal.Object(), p.PromotionDiscount.Object(), p.Discount.Object(), p.TaxAmount.Object(), p.TotalAmount.Object()})
+}
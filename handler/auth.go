@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"kasir-api/entity"
+)
+
+// Role - peran pemanggil API
+type Role string
+
+// Roles
+const (
+	RoleCashier Role = "cashier"
+	RoleManager Role = "manager"
+)
+
+// Authorizer - tentukan role dari header Authorization: Bearer <token>.
+// Tanpa token yang cocok pemanggil dianggap kasir.
+type Authorizer struct {
+	managerToken string
+}
+
+// NewAuthorizer - constructor untuk Authorizer, token kosong berarti tidak ada manager
+func NewAuthorizer(managerToken string) *Authorizer {
+	return &Authorizer{managerToken: managerToken}
+}
+
+// RoleOf - role pemanggil request
+func (a *Authorizer) RoleOf(r *http.Request) Role {
+	if a == nil || a.managerToken == "" {
+		return RoleCashier
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if ok && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(a.managerToken)) == 1 {
+		return RoleManager
+	}
+	return RoleCashier
+}
+
+// IsManager - true jika pemanggil boleh melihat data harga beli dan laporan
+func (a *Authorizer) IsManager(r *http.Request) bool {
+	return a.RoleOf(r) == RoleManager
+}
+
+// hideProductCost - hapus harga beli dari produk dan variannya untuk non-manager
+func hideProductCost(products []entity.Product) {
+	for i := range products {
+		products[i].HargaBeli = nil
+		hideVariantCost(products[i].Variants)
+	}
+}
+
+// hideVariantCost - hapus harga beli dari varian untuk non-manager
+func hideVariantCost(variants []entity.ProductVariant) {
+	for i := range variants {
+		variants[i].HargaBeli = nil
+	}
+}
+
+// hideTransactionCost - hapus salinan harga beli dari detail transaksi untuk non-manager
+func hideTransactionCost(transaction *entity.Transaction) {
+	for i := range transaction.Details {
+		transaction.Details[i].HargaBeli = nil
+	}
+}
@@ -2,7 +2,10 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"kasir-api/entity"
+	"kasir-api/repository"
 	"kasir-api/service"
 	"net/http"
 	"strconv"
@@ -31,6 +34,18 @@ func (h *CategoryHandler) GetAllCategories(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(categories)
 }
 
+// GetCategoryTree - handler untuk GET /api/categories/tree
+func (h *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.service.GetCategoryTree()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
 // GetCategoryByID - handler untuk GET /api/categories/{id}
 func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/api/categories/")
@@ -41,10 +56,14 @@ func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request
 	}
 
 	category, err := h.service.GetCategoryByID(id)
-	if err != nil {
+	if errors.Is(err, repository.ErrCategoryNotFound) {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(category)
@@ -60,6 +79,13 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 	}
 
 	newCategory, err := h.service.CreateCategory(category)
+	if writeConflict(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrParentNotFound) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -87,9 +113,20 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 	}
 
 	updatedCategory, err := h.service.UpdateCategory(id, category)
-	if err != nil {
+	if writeConflict(w, err) {
+		return
+	}
+	switch {
+	case err == nil:
+	case errors.Is(err, repository.ErrCategoryNotFound):
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
+	case errors.Is(err, service.ErrParentNotFound), errors.Is(err, service.ErrCategoryCycle):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -105,14 +142,67 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.service.DeleteCategory(id)
+	opts, err := parseDeleteOptions(r)
 	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.DeleteCategory(id, opts)
+	var inUse *service.CategoryInUseError
+	switch {
+	case err == nil:
+	case errors.As(err, &inUse):
+		// Mode restrict: tampilkan produk yang masih memakai kategori, tanpa harga beli
+		hideProductCost(inUse.Products)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":    inUse.Error(),
+			"products": inUse.Products,
+		})
+		return
+	case errors.Is(err, repository.ErrCategoryNotFound):
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
+	case errors.Is(err, service.ErrProductInBundle):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case writeConflict(w, err):
+		// Produk atau sub-kategori yang dipindah bentrok nama di tujuan
+		return
+	case errors.Is(err, service.ErrInvalidDeleteMode),
+		errors.Is(err, service.ErrReassignTargetRequired),
+		errors.Is(err, service.ErrReassignToSelf),
+		errors.Is(err, service.ErrReassignTargetNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Category deleted successfully",
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":               "Category deleted successfully",
+		"mode":                  result.Mode,
+		"products_affected":     result.ProductsAffected,
+		"subcategories_moved":   result.SubcategoriesMoved,
+		"subcategories_deleted": result.SubcategoriesDeleted,
 	})
 }
+
+// parseDeleteOptions - baca ?mode=detach|restrict|reassign|cascade dan ?to=<id>
+func parseDeleteOptions(r *http.Request) (service.DeleteCategoryOptions, error) {
+	query := r.URL.Query()
+	opts := service.DeleteCategoryOptions{Mode: service.DeleteMode(query.Get("mode"))}
+
+	if to := query.Get("to"); to != "" {
+		id, err := strconv.Atoi(to)
+		if err != nil || id <= 0 {
+			return opts, fmt.Errorf("invalid target category %q", to)
+		}
+		opts.To = id
+	}
+	return opts, nil
+}
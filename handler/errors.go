@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// writeConflict - tulis 409 beserta record yang bentrok jika err adalah ConflictError
+func writeConflict(w http.ResponseWriter, err error) bool {
+	var conflict *repository.ConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	existing := conflict.Existing
+	if product, ok := existing.(entity.Product); ok {
+		// Harga beli hanya untuk manager, jangan bocor lewat 409
+		product.HargaBeli = nil
+		existing = product
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":    conflict.Error(),
+		"existing": existing,
+	})
+	return true
+}
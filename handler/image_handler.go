@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// ImageHandler - struct untuk image handler
+type ImageHandler struct {
+	service service.ImageServiceInterface
+}
+
+// NewImageHandler - constructor untuk ImageHandler
+func NewImageHandler(service service.ImageServiceInterface) *ImageHandler {
+	return &ImageHandler{service: service}
+}
+
+// UploadImage - handler untuk POST /api/produk/{id}/image
+// Body multipart/form-data dengan file di field "image": JPEG, PNG atau GIF, maksimal 5 MB.
+// Gambar lama diganti; response berisi url gambar dan thumbnail_url untuk tile kasir.
+func (h *ImageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
+	productID, childID, action, err := parseProductSubpath(r.URL.Path, "image")
+	if err != nil || childID != 0 || action != "" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+
+	// Sisa 1 MB untuk header multipart dan field lain
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxImageSize+1<<20)
+	file, _, err := r.FormFile("image")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, service.ErrImageTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, `image file is required in multipart field "image"`, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, service.MaxImageSize+1))
+	if err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	image, err := h.service.UploadProductImage(productID, data)
+	switch {
+	case errors.Is(err, repository.ErrProductNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrImageTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, service.ErrUnsupportedImage):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	case errors.Is(err, service.ErrInvalidImage), errors.Is(err, service.ErrImageDimensions):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(image)
+}
+
+// DeleteImage - handler untuk DELETE /api/produk/{id}/image
+func (h *ImageHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	productID, childID, action, err := parseProductSubpath(r.URL.Path, "image")
+	if err != nil || childID != 0 || action != "" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeleteProductImage(productID)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Product image deleted successfully",
+	})
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// ModifierHandler - struct untuk modifier handler
+type ModifierHandler struct {
+	service service.ModifierServiceInterface
+}
+
+// NewModifierHandler - constructor untuk ModifierHandler
+func NewModifierHandler(service service.ModifierServiceInterface) *ModifierHandler {
+	return &ModifierHandler{service: service}
+}
+
+// parseModifierPath - group ID, modifier ID (0 jika tidak ada) dan apakah path menunjuk ke
+// pilihan dari /api/modifier-groups/{id}[/modifiers[/{modifierID}]]
+func parseModifierPath(path string) (groupID, modifierID int, modifiers bool, err error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/modifier-groups/"), "/"), "/")
+	if len(parts) > 3 || (len(parts) >= 2 && parts[1] != "modifiers") {
+		return 0, 0, false, errors.New("Invalid modifier path")
+	}
+	if groupID, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, false, errors.New("Invalid Modifier Group ID")
+	}
+	if len(parts) == 3 {
+		if modifierID, err = strconv.Atoi(parts[2]); err != nil {
+			return 0, 0, false, errors.New("Invalid Modifier ID")
+		}
+	}
+	return groupID, modifierID, len(parts) >= 2, nil
+}
+
+// GetGroups - handler untuk GET /api/modifier-groups
+func (h *ModifierHandler) GetGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.service.GetGroups()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// GetGroup - handler untuk GET /api/modifier-groups/{id}
+func (h *ModifierHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	id, _, modifiers, err := parseModifierPath(r.URL.Path)
+	if err != nil || modifiers {
+		http.Error(w, "Invalid Modifier Group ID", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.service.GetGroup(id)
+	if writeModifierError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// GetProductModifiers - handler untuk GET /api/produk/{id}/modifiers, grup milik produk
+// dan kategorinya (termasuk kategori induk)
+func (h *ModifierHandler) GetProductModifiers(w http.ResponseWriter, r *http.Request) {
+	productID, childID, action, err := parseProductSubpath(r.URL.Path, "modifiers")
+	if err != nil || childID != 0 || action != "" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+
+	groups, err := h.service.GetProductModifiers(productID)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// CreateGroup - handler untuk POST /api/modifier-groups
+// Body: {"nama":"Ekstra","product_id":2,"max_select":3,"modifiers":[{"nama":"Extra Shot","harga":5000}]}
+func (h *ModifierHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var group entity.ModifierGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.CreateGroup(group)
+	if writeModifierError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateGroup - handler untuk PUT /api/modifier-groups/{id}
+func (h *ModifierHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	id, _, modifiers, err := parseModifierPath(r.URL.Path)
+	if err != nil || modifiers {
+		http.Error(w, "Invalid Modifier Group ID", http.StatusBadRequest)
+		return
+	}
+
+	var group entity.ModifierGroup
+	if err := json.NewDecoder(r.Body).Decode(&group); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.UpdateGroup(id, group)
+	if writeModifierError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteGroup - handler untuk DELETE /api/modifier-groups/{id}
+func (h *ModifierHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	id, _, modifiers, err := parseModifierPath(r.URL.Path)
+	if err != nil || modifiers {
+		http.Error(w, "Invalid Modifier Group ID", http.StatusBadRequest)
+		return
+	}
+
+	if writeModifierError(w, h.service.DeleteGroup(id)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Modifier group deleted successfully",
+	})
+}
+
+// CreateModifier - handler untuk POST /api/modifier-groups/{id}/modifiers
+// Body: {"nama":"Less Sugar","harga":0}
+func (h *ModifierHandler) CreateModifier(w http.ResponseWriter, r *http.Request) {
+	groupID, modifierID, modifiers, err := parseModifierPath(r.URL.Path)
+	if err != nil || !modifiers || modifierID != 0 {
+		http.Error(w, "Invalid Modifier Group ID", http.StatusBadRequest)
+		return
+	}
+
+	var modifier entity.Modifier
+	if err := json.NewDecoder(r.Body).Decode(&modifier); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.CreateModifier(groupID, modifier)
+	if writeModifierError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateModifier - handler untuk PUT /api/modifier-groups/{id}/modifiers/{modifierID}
+func (h *ModifierHandler) UpdateModifier(w http.ResponseWriter, r *http.Request) {
+	groupID, modifierID, _, err := parseModifierPath(r.URL.Path)
+	if err != nil || modifierID == 0 {
+		http.Error(w, "Invalid Modifier ID", http.StatusBadRequest)
+		return
+	}
+
+	var modifier entity.Modifier
+	if err := json.NewDecoder(r.Body).Decode(&modifier); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.UpdateModifier(groupID, modifierID, modifier)
+	if writeModifierError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteModifier - handler untuk DELETE /api/modifier-groups/{id}/modifiers/{modifierID}
+func (h *ModifierHandler) DeleteModifier(w http.ResponseWriter, r *http.Request) {
+	groupID, modifierID, _, err := parseModifierPath(r.URL.Path)
+	if err != nil || modifierID == 0 {
+		http.Error(w, "Invalid Modifier ID", http.StatusBadRequest)
+		return
+	}
+
+	if writeModifierError(w, h.service.DeleteModifier(groupID, modifierID)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Modifier deleted successfully",
+	})
+}
+
+// writeModifierError - tulis status HTTP untuk error modifier, false jika err nil
+func writeModifierError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case writeConflict(w, err):
+	case errors.Is(err, repository.ErrModifierGroupNotFound), errors.Is(err, repository.ErrModifierNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrProductNotFound), errors.Is(err, repository.ErrCategoryNotFound),
+		errors.Is(err, service.ErrModifierGroupNameRequired), errors.Is(err, service.ErrModifierNameRequired),
+		errors.Is(err, service.ErrModifierTarget), errors.Is(err, service.ErrModifierRange),
+		errors.Is(err, service.ErrNegativeHarga):
+		// Produk atau kategori yang dirujuk body tidak ada, bukan resource di URL
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return true
+}
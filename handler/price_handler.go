@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// PriceHandler - struct untuk price handler
+type PriceHandler struct {
+	service service.PriceServiceInterface
+}
+
+// NewPriceHandler - constructor untuk PriceHandler
+func NewPriceHandler(service service.PriceServiceInterface) *PriceHandler {
+	return &PriceHandler{service: service}
+}
+
+// GetPrices - handler untuk GET /api/produk/{id}/prices
+// Riwayat harga dan harga terjadwal, urut berdasarkan effective_from
+func (h *PriceHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
+	productID, priceID, action, err := parseProductSubpath(r.URL.Path, "prices")
+	if err != nil || priceID != 0 || action != "" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+
+	prices, err := h.service.GetPrices(productID)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prices)
+}
+
+// SchedulePrice - handler untuk POST /api/produk/{id}/prices
+// Body: {"harga":18000,"effective_from":"2026-11-01T00:00:00+07:00"}, tanpa effective_from berlaku sekarang
+func (h *PriceHandler) SchedulePrice(w http.ResponseWriter, r *http.Request) {
+	productID, priceID, action, err := parseProductSubpath(r.URL.Path, "prices")
+	if err != nil || priceID != 0 || action != "" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+
+	var req entity.SchedulePriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	price, err := h.service.SchedulePrice(productID, req)
+	if writeConflict(w, err) {
+		return
+	}
+	if errors.Is(err, repository.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrNegativeHarga) || errors.Is(err, service.ErrPriceInPast) ||
+		errors.Is(err, service.ErrPriceCurrencyMismatch) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(price)
+}
+
+// CancelPrice - handler untuk DELETE /api/produk/{id}/prices/{priceID}
+// Hanya harga yang belum berlaku yang bisa dibatalkan
+func (h *PriceHandler) CancelPrice(w http.ResponseWriter, r *http.Request) {
+	productID, priceID, action, err := parseProductSubpath(r.URL.Path, "prices")
+	if err != nil || priceID == 0 || action != "" {
+		http.Error(w, "Invalid Price ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.CancelPrice(productID, priceID)
+	if errors.Is(err, repository.ErrPriceNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrPriceAlreadyEffective) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Scheduled price cancelled successfully",
+	})
+}
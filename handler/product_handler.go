@@ -2,7 +2,9 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"kasir-api/entity"
+	"kasir-api/repository"
 	"kasir-api/service"
 	"net/http"
 	"strconv"
@@ -12,25 +14,125 @@ import (
 // ProductHandler - struct untuk product handler
 type ProductHandler struct {
 	service service.ProductServiceInterface
+	auth    *Authorizer
 }
 
-// NewProductHandler - constructor untuk ProductHandler
-func NewProductHandler(service service.ProductServiceInterface) *ProductHandler {
-	return &ProductHandler{service: service}
+// NewProductHandler - constructor untuk ProductHandler.
+// Harga beli hanya ditampilkan dan bisa diubah oleh role manager.
+func NewProductHandler(service service.ProductServiceInterface, auth *Authorizer) *ProductHandler {
+	return &ProductHandler{service: service, auth: auth}
 }
 
 // GetAllProducts - handler untuk GET /api/produk
+// Tambahkan ?include=category untuk menyertakan kategori setiap produk (JOIN),
+// ?include=variants untuk varian setiap produk, atau keduanya: ?include=category,variants
+// Filter: ?category_id={id} atau ?category_id=null untuk produk tanpa kategori
 func (h *ProductHandler) GetAllProducts(w http.ResponseWriter, r *http.Request) {
-	products, err := h.service.GetAllProducts()
+	query := r.URL.Query()
+
+	include := parseInclude(query.Get("include"))
+
+	var filter entity.ProductFilter
+	switch categoryID := query.Get("category_id"); categoryID {
+	case "":
+	case "null", "uncategorized":
+		filter.Uncategorized = true
+	default:
+		id, err := strconv.Atoi(categoryID)
+		if err != nil {
+			http.Error(w, "Invalid Category ID", http.StatusBadRequest)
+			return
+		}
+		filter.CategoryID = id
+	}
+
+	products, err := h.service.GetAllProducts(filter, include)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if !h.auth.IsManager(r) {
+		hideProductCost(products)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(products)
 }
 
+// GetProductsByCategory - handler untuk GET /api/categories/{id}/products
+// Tambahkan ?recursive=true untuk menyertakan produk di semua sub-kategori
+func (h *ProductHandler) GetProductsByCategory(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/categories/"), "/products")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid Category ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	recursive := false
+	if value := query.Get("recursive"); value != "" {
+		if recursive, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "recursive must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	products, err := h.service.GetProductsByCategory(id, recursive, parseInclude(query.Get("include")))
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if products == nil {
+		products = []entity.Product{}
+	}
+	if !h.auth.IsManager(r) {
+		hideProductCost(products)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// parseProductSubpath - product ID, child ID (0 jika tidak ada) dan aksi ("" jika tidak ada)
+// dari /api/produk/{id}/{resource}[/{childID}[/{action}]]
+func parseProductSubpath(path, resource string) (productID, childID int, action string, err error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/produk/"), "/"), "/")
+	if len(parts) < 2 || len(parts) > 4 || parts[1] != resource {
+		return 0, 0, "", errors.New("Invalid " + resource + " path")
+	}
+	if productID, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, "", errors.New("Invalid Product ID")
+	}
+	if len(parts) >= 3 {
+		if childID, err = strconv.Atoi(parts[2]); err != nil {
+			return 0, 0, "", errors.New("Invalid " + resource + " ID")
+		}
+	}
+	if len(parts) == 4 {
+		action = parts[3]
+	}
+	return productID, childID, action, nil
+}
+
+// parseInclude - ?include=category,variants
+func parseInclude(include string) service.ProductInclude {
+	var result service.ProductInclude
+	for _, part := range strings.Split(include, ",") {
+		switch strings.TrimSpace(part) {
+		case "category":
+			result.Category = true
+		case "variants":
+			result.Variants = true
+		}
+	}
+	return result
+}
+
 // GetProductByID - handler untuk GET /api/produk/{id}
 // CHALLENGE: Return category.name dari product (JOIN)
 func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request) {
@@ -43,10 +145,15 @@ func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 
 	// Service melakukan JOIN dengan category
 	product, err := h.service.GetProductByID(id)
-	if err != nil {
+	if errors.Is(err, repository.ErrProductNotFound) {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.hideCostUnlessManager(r, &product)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(product)
@@ -61,13 +168,23 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.hideCostUnlessManager(r, &product)
+
 	newProduct, err := h.service.CreateProduct(product)
+	if writeConflict(w, err) {
+		return
+	}
+	if isProductInputError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	h.hideCostUnlessManager(r, &newProduct)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(newProduct)
 }
@@ -88,12 +205,22 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.hideCostUnlessManager(r, &product)
+
 	updatedProduct, err := h.service.UpdateProduct(id, product)
+	if writeConflict(w, err) {
+		return
+	}
+	if isProductInputError(err) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
+	h.hideCostUnlessManager(r, &updatedProduct)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(updatedProduct)
 }
@@ -108,6 +235,10 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.service.DeleteProduct(id)
+	if errors.Is(err, service.ErrProductInBundle) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -118,3 +249,74 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 		"message": "Product deleted successfully",
 	})
 }
+
+// AdjustStock - handler untuk POST /api/produk/{id}/stock
+// Body: {"delta":2,"unit":"dus"} untuk restock, delta negatif untuk koreksi stok.
+// unit boleh dikosongkan (satuan beli produk), stok disimpan dalam satuan dasar.
+func (h *ProductHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/produk/"), "/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil || len(parts) != 2 || parts[1] != "stock" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Delta entity.Quantity `json:"delta"`
+		Unit  string          `json:"unit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Delta == 0 {
+		http.Error(w, "delta must be a non-zero number", http.StatusBadRequest)
+		return
+	}
+
+	product, err := h.service.AdjustStock(id, req.Delta, req.Unit)
+	switch {
+	case errors.Is(err, repository.ErrProductNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrInsufficientStock):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, repository.ErrStockNotTracked), errors.Is(err, service.ErrBundleStock),
+		errors.Is(err, service.ErrUnitNotAvailable), errors.Is(err, service.ErrUnknownUnit),
+		errors.Is(err, service.ErrFractionalQuantity), errors.Is(err, service.ErrInvalidQuantity):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.hideCostUnlessManager(r, &product)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// isProductInputError - error karena isi request produk tidak valid (400)
+func isProductInputError(err error) bool {
+	for _, target := range []error{
+		repository.ErrCategoryNotFound, repository.ErrVariantNotFound,
+		service.ErrNegativeHarga, service.ErrCostCurrencyMismatch, service.ErrNegativeStock,
+		service.ErrInvalidProductType, service.ErrBundleComponentsRequired, service.ErrComponentsOnSingle,
+		service.ErrNestedBundle, service.ErrDuplicateComponent, service.ErrComponentNotFound, service.ErrBundleStock,
+		service.ErrBundleVariants, service.ErrVariantRequired, service.ErrInvalidQuantity,
+		service.ErrUnknownUnit, service.ErrInvalidConversion, service.ErrDuplicateUnit,
+		service.ErrInvalidPurchase, service.ErrFractionalQuantity,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// hideCostUnlessManager - harga beli hanya untuk manager: dihapus dari response, dan dari
+// request agar harga beli tersimpan tidak berubah oleh non-manager
+func (h *ProductHandler) hideCostUnlessManager(r *http.Request, product *entity.Product) {
+	if !h.auth.IsManager(r) {
+		product.HargaBeli = nil
+		product.ClearHargaBeli = false
+		hideVariantCost(product.Variants)
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// PromotionHandler - struct untuk promotion handler
+type PromotionHandler struct {
+	service service.PromotionServiceInterface
+	auth    *Authorizer
+}
+
+// NewPromotionHandler - constructor untuk PromotionHandler, hanya manager yang boleh
+// membuat, mengubah dan menghapus promosi
+func NewPromotionHandler(service service.PromotionServiceInterface, auth *Authorizer) *PromotionHandler {
+	return &PromotionHandler{service: service, auth: auth}
+}
+
+// promotionID - ID dari /api/promotions/{id}
+func promotionID(path string) (int, error) {
+	return strconv.Atoi(strings.Trim(strings.TrimPrefix(path, "/api/promotions/"), "/"))
+}
+
+// GetPromotions - handler untuk GET /api/promotions
+func (h *PromotionHandler) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+// GetPromotion - handler untuk GET /api/promotions/{id}
+func (h *PromotionHandler) GetPromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := promotionID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	promotion, err := h.service.GetByID(id)
+	if writePromotionError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// CreatePromotion - handler untuk POST /api/promotions
+// Body: {"nama":"Snack Weekend","type":"percentage","scope":"category","category_id":3,"percent":10,"days":[0,6]}
+// atau {"nama":"Beli 2 Gratis 1","type":"bogo","scope":"product","product_id":5,"buy_quantity":2,"get_quantity":1}
+// atau {"nama":"Potongan 5rb","type":"threshold","scope":"cart","amount":5000,"min_subtotal":50000}
+func (h *PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing promotions requires manager role", http.StatusForbidden)
+		return
+	}
+
+	var promotion entity.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.Create(promotion)
+	if writePromotionError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdatePromotion - handler untuk PUT /api/promotions/{id}
+func (h *PromotionHandler) UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing promotions requires manager role", http.StatusForbidden)
+		return
+	}
+
+	id, err := promotionID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	var promotion entity.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.Update(id, promotion)
+	if writePromotionError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeletePromotion - handler untuk DELETE /api/promotions/{id}
+func (h *PromotionHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing promotions requires manager role", http.StatusForbidden)
+		return
+	}
+
+	id, err := promotionID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Promotion ID", http.StatusBadRequest)
+		return
+	}
+
+	if writePromotionError(w, h.service.Delete(id)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Promotion deleted successfully",
+	})
+}
+
+// writePromotionError - tulis status HTTP untuk error promosi, false jika err nil
+func writePromotionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, repository.ErrPromotionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrProductNotFound), errors.Is(err, repository.ErrCategoryNotFound),
+		errors.Is(err, service.ErrPromotionNameRequired), errors.Is(err, service.ErrPromotionType),
+		errors.Is(err, service.ErrPromotionScope), errors.Is(err, service.ErrPromotionPercent),
+		errors.Is(err, service.ErrPromotionAmount), errors.Is(err, service.ErrPromotionBOGO),
+		errors.Is(err, service.ErrPromotionThreshold), errors.Is(err, service.ErrPromotionRange),
+		errors.Is(err, service.ErrPromotionDays), errors.Is(err, service.ErrMixedCurrency):
+		// Produk atau kategori yang dirujuk body tidak ada, bukan resource di URL
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return true
+}
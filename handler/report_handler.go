@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"kasir-api/entity"
+	"kasir-api/service"
+)
+
+// ReportHandler - struct untuk report handler, hanya untuk role manager
+type ReportHandler struct {
+	service service.ReportServiceInterface
+	auth    *Authorizer
+}
+
+// NewReportHandler - constructor untuk ReportHandler
+func NewReportHandler(service service.ReportServiceInterface, auth *Authorizer) *ReportHandler {
+	return &ReportHandler{service: service, auth: auth}
+}
+
+// GetMarginReport - handler untuk GET /api/report/margin
+// Query: ?by=product|category&from=YYYY-MM-DD&to=YYYY-MM-DD (to inklusif, tanggal UTC)
+func (h *ReportHandler) GetMarginReport(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Margin report requires manager role", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := entity.MarginFilter{GroupBy: query.Get("by")}
+	if from := query.Get("from"); from != "" {
+		day, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			http.Error(w, "from must be a date like 2006-01-02", http.StatusBadRequest)
+			return
+		}
+		filter.From = day
+	}
+	if to := query.Get("to"); to != "" {
+		day, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			http.Error(w, "to must be a date like 2006-01-02", http.StatusBadRequest)
+			return
+		}
+		filter.To = day.AddDate(0, 0, 1)
+	}
+
+	rows, err := h.service.MarginReport(filter)
+	if errors.Is(err, service.ErrInvalidGroupBy) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rows)
+}
+
+// GetTaxReport - handler untuk GET /api/report/tax
+// Query: ?from=YYYY-MM-DD&to=YYYY-MM-DD (to inklusif, tanggal UTC)
+func (h *ReportHandler) GetTaxReport(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Tax report requires manager role", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	var filter entity.TaxFilter
+	if from := query.Get("from"); from != "" {
+		day, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			http.Error(w, "from must be a date like 2006-01-02", http.StatusBadRequest)
+			return
+		}
+		filter.From = day
+	}
+	if to := query.Get("to"); to != "" {
+		day, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			http.Error(w, "to must be a date like 2006-01-02", http.StatusBadRequest)
+			return
+		}
+		filter.To = day.AddDate(0, 0, 1)
+	}
+
+	rows, err := h.service.TaxReport(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rows)
+}
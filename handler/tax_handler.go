@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// TaxHandler - struct untuk tax handler
+type TaxHandler struct {
+	service service.TaxServiceInterface
+	auth    *Authorizer
+}
+
+// NewTaxHandler - constructor untuk TaxHandler. Tarif pajak mengubah total setiap
+// transaksi, jadi tambah, ubah dan hapus tarif hanya untuk manager.
+func NewTaxHandler(service service.TaxServiceInterface, auth *Authorizer) *TaxHandler {
+	return &TaxHandler{service: service, auth: auth}
+}
+
+// taxRateID - ID dari /api/tax-rates/{id}
+func taxRateID(path string) (int, error) {
+	return strconv.Atoi(strings.Trim(strings.TrimPrefix(path, "/api/tax-rates/"), "/"))
+}
+
+// GetTaxRates - handler untuk GET /api/tax-rates
+func (h *TaxHandler) GetTaxRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rates)
+}
+
+// GetTaxRate - handler untuk GET /api/tax-rates/{id}
+func (h *TaxHandler) GetTaxRate(w http.ResponseWriter, r *http.Request) {
+	id, err := taxRateID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Tax Rate ID", http.StatusBadRequest)
+		return
+	}
+
+	rate, err := h.service.GetByID(id)
+	if writeTaxRateError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rate)
+}
+
+// CreateTaxRate - handler untuk POST /api/tax-rates
+// Body: {"nama":"PPN 11%","rate":11,"compound":true,"position":2,"exempt_category_ids":[4]}
+// atau {"nama":"PB1 10%","rate":10,"inclusive":true}
+func (h *TaxHandler) CreateTaxRate(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing tax rates requires manager role", http.StatusForbidden)
+		return
+	}
+
+	var rate entity.TaxRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.Create(rate)
+	if writeTaxRateError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateTaxRate - handler untuk PUT /api/tax-rates/{id}
+func (h *TaxHandler) UpdateTaxRate(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing tax rates requires manager role", http.StatusForbidden)
+		return
+	}
+
+	id, err := taxRateID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Tax Rate ID", http.StatusBadRequest)
+		return
+	}
+
+	var rate entity.TaxRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.Update(id, rate)
+	if writeTaxRateError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteTaxRate - handler untuk DELETE /api/tax-rates/{id}
+func (h *TaxHandler) DeleteTaxRate(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing tax rates requires manager role", http.StatusForbidden)
+		return
+	}
+
+	id, err := taxRateID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Tax Rate ID", http.StatusBadRequest)
+		return
+	}
+
+	if writeTaxRateError(w, h.service.Delete(id)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Tax rate deleted successfully",
+	})
+}
+
+// writeTaxRateError - tulis status HTTP untuk error tarif pajak, false jika err nil
+func writeTaxRateError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case writeConflict(w, err):
+	case errors.Is(err, repository.ErrTaxRateNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrCategoryNotFound), errors.Is(err, service.ErrTaxNameRequired),
+		errors.Is(err, service.ErrTaxRate), errors.Is(err, service.ErrTaxCompoundIncluded):
+		// Kategori yang dirujuk body tidak ada, bukan resource di URL
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// TransactionHandler - struct untuk transaction handler
+type TransactionHandler struct {
+	service service.TransactionServiceInterface
+	auth    *Authorizer
+}
+
+// NewTransactionHandler - constructor untuk TransactionHandler
+func NewTransactionHandler(service service.TransactionServiceInterface, auth *Authorizer) *TransactionHandler {
+	return &TransactionHandler{service: service, auth: auth}
+}
+
+// Checkout - handler untuk POST /api/checkout
+// Body: {"items":[{"product_id":1,"quantity":2},{"product_id":3,"variant_id":7,"quantity":1,"modifiers":[4,9]},
+// {"product_id":5,"quantity":0.5,"unit":"kg"}],"voucher_code":"MERDEKA17","customer":"0812345678"}
+func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	var req entity.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.Checkout(req)
+	if writeCheckoutError(w, err) {
+		return
+	}
+
+	if !h.auth.IsManager(r) {
+		hideTransactionCost(&transaction)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// PriceCart - handler untuk POST /api/cart/price, body sama dengan checkout. Harga, diskon,
+// pajak dan total dihitung dari harga produk saat ini tanpa menyimpan apa pun; stok yang
+// kurang dilaporkan di stock_shortages, bukan 409.
+func (h *TransactionHandler) PriceCart(w http.ResponseWriter, r *http.Request) {
+	var req entity.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	price, err := h.service.Price(req)
+	if writeCheckoutError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(price)
+}
+
+// writeCheckoutError - tulis status HTTP untuk error keranjang atau voucher,
+// false jika err nil
+func writeCheckoutError(w http.ResponseWriter, err error) bool {
+	if writeCartError(w, err) {
+		return true
+	}
+	if errors.Is(err, repository.ErrVoucherNotFound) {
+		// Kode voucher dari body, bukan resource di URL
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return true
+	}
+	return writeVoucherError(w, err)
+}
+
+// writeCartError - tulis status HTTP untuk error keranjang (baris tidak valid atau stok kurang),
+// false jika err nil atau bukan error keranjang
+func writeCartError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrEmptyCart), errors.Is(err, service.ErrInvalidQuantity),
+		errors.Is(err, service.ErrQuantityTooLarge),
+		errors.Is(err, service.ErrMixedCurrency), errors.Is(err, repository.ErrProductNotFound),
+		errors.Is(err, repository.ErrVariantNotFound), errors.Is(err, service.ErrVariantRequired),
+		errors.Is(err, service.ErrModifierNotAvailable), errors.Is(err, service.ErrDuplicateModifier),
+		errors.Is(err, service.ErrModifierSelection), errors.Is(err, service.ErrFractionalQuantity),
+		errors.Is(err, service.ErrUnitNotAvailable), errors.Is(err, service.ErrUnknownUnit),
+		errors.Is(err, entity.ErrMoneyOverflow):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrInsufficientStock):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// UnitHandler - struct untuk unit handler
+type UnitHandler struct {
+	service service.UnitServiceInterface
+}
+
+// NewUnitHandler - constructor untuk UnitHandler
+func NewUnitHandler(service service.UnitServiceInterface) *UnitHandler {
+	return &UnitHandler{service: service}
+}
+
+// unitCode - kode satuan dari /api/units/{code}
+func unitCode(path string) string {
+	return strings.Trim(strings.TrimPrefix(path, "/api/units/"), "/")
+}
+
+// GetUnits - handler untuk GET /api/units
+func (h *UnitHandler) GetUnits(w http.ResponseWriter, r *http.Request) {
+	units, err := h.service.GetAllUnits()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(units)
+}
+
+// CreateUnit - handler untuk POST /api/units
+// Body: {"code":"rim","nama":"Rim","allow_decimal":false}
+func (h *UnitHandler) CreateUnit(w http.ResponseWriter, r *http.Request) {
+	var unit entity.Unit
+	if err := json.NewDecoder(r.Body).Decode(&unit); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.CreateUnit(unit)
+	if writeUnitError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateUnit - handler untuk PUT /api/units/{code}
+func (h *UnitHandler) UpdateUnit(w http.ResponseWriter, r *http.Request) {
+	code := unitCode(r.URL.Path)
+	if code == "" || strings.Contains(code, "/") {
+		http.Error(w, "Invalid unit code", http.StatusBadRequest)
+		return
+	}
+
+	var unit entity.Unit
+	if err := json.NewDecoder(r.Body).Decode(&unit); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.UpdateUnit(code, unit)
+	if writeUnitError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteUnit - handler untuk DELETE /api/units/{code}
+func (h *UnitHandler) DeleteUnit(w http.ResponseWriter, r *http.Request) {
+	code := unitCode(r.URL.Path)
+	if code == "" || strings.Contains(code, "/") {
+		http.Error(w, "Invalid unit code", http.StatusBadRequest)
+		return
+	}
+
+	if writeUnitError(w, h.service.DeleteUnit(code)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Unit deleted successfully",
+	})
+}
+
+// writeUnitError - tulis status HTTP untuk error satuan, false jika err nil
+func writeUnitError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case writeConflict(w, err):
+	case errors.Is(err, repository.ErrUnitNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrUnitInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidUnitCode), errors.Is(err, service.ErrUnitNameRequired),
+		errors.Is(err, service.ErrUnitNameTooLong):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// VariantHandler - struct untuk variant handler
+type VariantHandler struct {
+	service service.VariantServiceInterface
+	auth    *Authorizer
+}
+
+// NewVariantHandler - constructor untuk VariantHandler
+func NewVariantHandler(service service.VariantServiceInterface, auth *Authorizer) *VariantHandler {
+	return &VariantHandler{service: service, auth: auth}
+}
+
+// GetVariants - handler untuk GET /api/produk/{id}/variants
+func (h *VariantHandler) GetVariants(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, action, err := parseProductSubpath(r.URL.Path, "variants")
+	if err != nil || variantID != 0 || action != "" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+
+	variants, err := h.service.GetVariants(productID)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !h.auth.IsManager(r) {
+		hideVariantCost(variants)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(variants)
+}
+
+// CreateVariant - handler untuk POST /api/produk/{id}/variants
+// Body: {"sku":"ETM-JMB","nama":"Jumbo","options":{"ukuran":"jumbo"},"harga":8000,"harga_beli":3500,"stock":50}
+func (h *VariantHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, action, err := parseProductSubpath(r.URL.Path, "variants")
+	if err != nil || action != "" {
+		http.Error(w, "Invalid Product ID", http.StatusBadRequest)
+		return
+	}
+	if variantID != 0 {
+		// POST /api/produk/{id}/variants/{variantID} tidak dikenal, hanya .../stock
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var variant entity.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&variant); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	h.hideCostUnlessManager(r, &variant)
+	created, err := h.service.CreateVariant(productID, variant)
+	if writeVariantError(w, err) {
+		return
+	}
+	h.hideCostUnlessManager(r, &created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateVariant - handler untuk PUT /api/produk/{id}/variants/{variantID}
+func (h *VariantHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, action, err := parseProductSubpath(r.URL.Path, "variants")
+	if err != nil || variantID == 0 || action != "" {
+		http.Error(w, "Invalid Variant ID", http.StatusBadRequest)
+		return
+	}
+
+	var variant entity.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&variant); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	h.hideCostUnlessManager(r, &variant)
+	updated, err := h.service.UpdateVariant(productID, variantID, variant)
+	if writeVariantError(w, err) {
+		return
+	}
+	h.hideCostUnlessManager(r, &updated)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteVariant - handler untuk DELETE /api/produk/{id}/variants/{variantID}
+func (h *VariantHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, action, err := parseProductSubpath(r.URL.Path, "variants")
+	if err != nil || variantID == 0 || action != "" {
+		http.Error(w, "Invalid Variant ID", http.StatusBadRequest)
+		return
+	}
+
+	if writeVariantError(w, h.service.DeleteVariant(productID, variantID)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Variant deleted successfully",
+	})
+}
+
+// AdjustStock - handler untuk POST /api/produk/{id}/variants/{variantID}/stock
+// Body: {"delta":24} atau {"delta":0.5,"unit":"kg"} untuk restock, delta negatif untuk koreksi stok.
+// unit boleh dikosongkan (satuan beli produk), stok disimpan dalam satuan dasar produk.
+func (h *VariantHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	productID, variantID, action, err := parseProductSubpath(r.URL.Path, "variants")
+	if err != nil || variantID == 0 || action != "stock" {
+		http.Error(w, "Invalid Variant ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Delta entity.Quantity `json:"delta"`
+		Unit  string          `json:"unit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Delta == 0 {
+		http.Error(w, "delta must be a non-zero number", http.StatusBadRequest)
+		return
+	}
+
+	variant, err := h.service.AdjustStock(productID, variantID, req.Delta, req.Unit)
+	if writeVariantError(w, err) {
+		return
+	}
+	h.hideCostUnlessManager(r, &variant)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(variant)
+}
+
+// hideCostUnlessManager - harga beli varian hanya untuk manager: dihapus dari response, dan
+// dari request agar harga beli tersimpan tidak berubah oleh non-manager
+func (h *VariantHandler) hideCostUnlessManager(r *http.Request, variant *entity.ProductVariant) {
+	if !h.auth.IsManager(r) {
+		variant.HargaBeli = nil
+		variant.ClearHargaBeli = false
+	}
+}
+
+// writeVariantError - tulis status HTTP untuk error varian, false jika err nil
+func writeVariantError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case writeConflict(w, err):
+	case errors.Is(err, repository.ErrProductNotFound), errors.Is(err, repository.ErrVariantNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrInsufficientStock), errors.Is(err, service.ErrProductInBundle):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrSKURequired), errors.Is(err, service.ErrVariantNameRequired),
+		errors.Is(err, service.ErrNegativeHarga), errors.Is(err, service.ErrNegativeStock),
+		errors.Is(err, service.ErrVariantCurrencyMismatch), errors.Is(err, service.ErrCostCurrencyMismatch),
+		errors.Is(err, service.ErrBundleVariants), errors.Is(err, service.ErrFractionalQuantity),
+		errors.Is(err, service.ErrUnitNotAvailable), errors.Is(err, service.ErrUnknownUnit),
+		errors.Is(err, service.ErrInvalidQuantity):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return true
+}
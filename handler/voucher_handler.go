@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/service"
+)
+
+// VoucherHandler - struct untuk voucher handler
+type VoucherHandler struct {
+	service service.VoucherServiceInterface
+	auth    *Authorizer
+}
+
+// NewVoucherHandler - constructor untuk VoucherHandler. Daftar dan pengelolaan voucher hanya
+// untuk manager karena memuat semua kode; kasir cukup memakai validate dan checkout.
+func NewVoucherHandler(service service.VoucherServiceInterface, auth *Authorizer) *VoucherHandler {
+	return &VoucherHandler{service: service, auth: auth}
+}
+
+// voucherID - ID dari /api/vouchers/{id}
+func voucherID(path string) (int, error) {
+	return strconv.Atoi(strings.Trim(strings.TrimPrefix(path, "/api/vouchers/"), "/"))
+}
+
+// GetVouchers - handler untuk GET /api/vouchers
+func (h *VoucherHandler) GetVouchers(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing vouchers requires manager role", http.StatusForbidden)
+		return
+	}
+
+	vouchers, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vouchers)
+}
+
+// GetVoucher - handler untuk GET /api/vouchers/{id}
+func (h *VoucherHandler) GetVoucher(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing vouchers requires manager role", http.StatusForbidden)
+		return
+	}
+
+	id, err := voucherID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Voucher ID", http.StatusBadRequest)
+		return
+	}
+
+	voucher, err := h.service.GetByID(id)
+	if writeVoucherError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voucher)
+}
+
+// CreateVoucher - handler untuk POST /api/vouchers
+// Body: {"code":"MERDEKA17","type":"percentage","percent":17,"max_discount":25000,"usage_limit":100,
+// "per_customer_limit":1,"expires_at":"2026-08-18T00:00:00+07:00"}
+// atau {"code":"HEMAT10K","type":"fixed","amount":10000,"min_subtotal":50000}
+func (h *VoucherHandler) CreateVoucher(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing vouchers requires manager role", http.StatusForbidden)
+		return
+	}
+
+	var voucher entity.Voucher
+	if err := json.NewDecoder(r.Body).Decode(&voucher); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.Create(voucher)
+	if writeVoucherError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateVoucher - handler untuk PUT /api/vouchers/{id}
+func (h *VoucherHandler) UpdateVoucher(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing vouchers requires manager role", http.StatusForbidden)
+		return
+	}
+
+	id, err := voucherID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Voucher ID", http.StatusBadRequest)
+		return
+	}
+
+	var voucher entity.Voucher
+	if err := json.NewDecoder(r.Body).Decode(&voucher); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.Update(id, voucher)
+	if writeVoucherError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteVoucher - handler untuk DELETE /api/vouchers/{id}
+func (h *VoucherHandler) DeleteVoucher(w http.ResponseWriter, r *http.Request) {
+	if !h.auth.IsManager(r) {
+		http.Error(w, "Managing vouchers requires manager role", http.StatusForbidden)
+		return
+	}
+
+	id, err := voucherID(r.URL.Path)
+	if err != nil {
+		http.Error(w, "Invalid Voucher ID", http.StatusBadRequest)
+		return
+	}
+
+	if writeVoucherError(w, h.service.Delete(id)) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Voucher deleted successfully",
+	})
+}
+
+// ValidateVoucher - handler untuk POST /api/vouchers/validate, pratinjau potongan voucher
+// untuk keranjang tanpa menyimpan apa pun
+// Body: {"code":"MERDEKA17","customer":"0812345678","items":[{"product_id":1,"quantity":2}]}
+func (h *VoucherHandler) ValidateVoucher(w http.ResponseWriter, r *http.Request) {
+	var req entity.VoucherValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	preview, err := h.service.Validate(req)
+	if writeCartError(w, err) || writeVoucherError(w, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preview)
+}
+
+// writeVoucherError - tulis status HTTP untuk error voucher, false jika err nil
+func writeVoucherError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case writeConflict(w, err):
+	case errors.Is(err, repository.ErrVoucherNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, repository.ErrVoucherUsageLimit), errors.Is(err, repository.ErrVoucherCustomerLimit):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrVoucherCode), errors.Is(err, service.ErrVoucherType),
+		errors.Is(err, service.ErrVoucherPercent), errors.Is(err, service.ErrVoucherAmount),
+		errors.Is(err, service.ErrVoucherMaxDiscount), errors.Is(err, service.ErrVoucherLimit),
+		errors.Is(err, service.ErrVoucherLimitBelowUsed), errors.Is(err, service.ErrVoucherRange),
+		errors.Is(err, service.ErrVoucherNotStarted), errors.Is(err, service.ErrVoucherExpired),
+		errors.Is(err, service.ErrVoucherMinSubtotal), errors.Is(err, service.ErrVoucherCustomerRequired),
+		errors.Is(err, service.ErrNegativeHarga), errors.Is(err, service.ErrMixedCurrency):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return true
+}
@@ -1,199 +1,42 @@
 package main
 
 import (
-	"encoding/json"
 	_ "kasir-api/docs"
-	"kasir-api/handler"
-	"kasir-api/repository"
-	"kasir-api/service"
-	"net/http"
 	"os"
+	"strings"
 
+	"kasir-api/app"
 	"kasir-api/config"
-
-	"github.com/joho/godotenv"
-	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-// APIInfo represents the API information for root endpoint
-type APIInfo struct {
-	Name        string       `json:"name"`
-	Version     string       `json:"version"`
-	Description string       `json:"description"`
-	Database    string       `json:"database"`
-	Endpoints   Endpoints    `json:"endpoints"`
-	Architecture Architecture `json:"architecture"`
-}
-
-// Endpoints represents all available endpoints
-type Endpoints struct {
-	Root       string `json:"root"`
-	Health     string `json:"health"`
-	Swagger    string `json:"swagger"`
-	Categories string `json:"categories"`
-	Products   string `json:"products"`
-}
-
-// Architecture represents the layered architecture
-type Architecture struct {
-	Layers []Layer `json:"layers"`
-}
-
-// Layer represents a single layer
-type Layer struct {
-	Name        string `json:"name"`
-	Path        string `json:"path"`
-	Description string `json:"description"`
-}
-
-func getAPIInfo() APIInfo {
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
-	}
-	
-	baseURL := "http://localhost:" + port
-	
-	return APIInfo{
-		Name:        "Kasir API",
-		Version:     "1.0.0",
-		Description: "API Kasir dengan Layered Architecture - Week 2 Challenge",
-		Database:    "PostgreSQL (Neon)",
-		Endpoints: Endpoints{
-			Root:       baseURL + "/",
-			Health:     baseURL + "/health",
-			Swagger:    baseURL + "/swagger/",
-			Categories: baseURL + "/api/categories",
-			Products:   baseURL + "/api/produk",
-		},
-		Architecture: Architecture{
-			Layers: []Layer{
-				{Name: "Handler", Path: "handler/", Description: "HTTP Layer - Request/Response handling"},
-				{Name: "Service", Path: "service/", Description: "Business Logic Layer"},
-				{Name: "Repository", Path: "repository/", Description: "Data Access Layer (PostgreSQL/Neon)"},
-				{Name: "Entity", Path: "entity/", Description: "Models/Entities"},
-			},
-		},
+func main() {
+	// CLI subcommands, e.g. `kasir-api seed --fake --products 10000`
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			println("❌ Command failed:", err.Error())
+			os.Exit(1)
+		}
+		return
 	}
-}
 
-func main() {
-	// Load .env file
-	err := godotenv.Load()
+	// Load config from .env, environment variables and flags
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
-		println("⚠️  Warning: .env file not found, using environment variables")
+		println("❌ Konfigurasi tidak valid:", err.Error())
+		os.Exit(1)
 	}
+	println(cfg.String())
 
-	// Connect to PostgreSQL Database (Neon)
-	db := config.ConnectDB()
-	defer db.Close()
-
-	// Run migrations and seeders
-	config.SetupDatabase()
-
-	// ===== LAYERED ARCHITECTURE SETUP =====
-	
-	// Repository Layer (Data Access with PostgreSQL/Neon)
-	categoryRepo := repository.NewCategoryRepository(db)
-	productRepo := repository.NewProductRepository(db)
-	
-	// Service Layer (Business Logic)
-	categoryService := service.NewCategoryService(categoryRepo)
-	productService := service.NewProductService(productRepo, categoryRepo) // Inject categoryRepo untuk JOIN
-	
-	// Handler Layer (HTTP Handler/Controller)
-	categoryHandler := handler.NewCategoryHandler(categoryService)
-	productHandler := handler.NewProductHandler(productService)
-	
-	// ===== ROUTES =====
-	
-	// Root endpoint - Simple JSON
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/" {
-			http.NotFound(w, r)
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(getAPIInfo())
-	})
-	
-	// Swagger UI
-	http.HandleFunc("/swagger/", httpSwagger.WrapHandler)
-	
-	// Category Routes (Layered Architecture)
-	http.HandleFunc("/api/categories/", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			categoryHandler.GetCategoryByID(w, r)
-		case "PUT":
-			categoryHandler.UpdateCategory(w, r)
-		case "DELETE":
-			categoryHandler.DeleteCategory(w, r)
-		}
-	})
-	
-	http.HandleFunc("/api/categories", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			categoryHandler.GetAllCategories(w, r)
-		case "POST":
-			categoryHandler.CreateCategory(w, r)
-		}
-	})
-	
-	// Product Routes (Layered Architecture dengan CHALLENGE: JOIN)
-	http.HandleFunc("/api/produk/", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			// CHALLENGE: Get Detail Product dengan Category Name (JOIN)
-			productHandler.GetProductByID(w, r)
-		case "PUT":
-			productHandler.UpdateProduct(w, r)
-		case "DELETE":
-			productHandler.DeleteProduct(w, r)
-		}
-	})
-	
-	http.HandleFunc("/api/produk", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			productHandler.GetAllProducts(w, r)
-		case "POST":
-			productHandler.CreateProduct(w, r)
-		}
-	})
-	
-	// Health check
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(`{"status":"OK","message":"API Running with PostgreSQL (Neon)"}`))
-	})
-	
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
+	// Connect to PostgreSQL Database (Neon), run migrations and seeders,
+	// then wire the layered architecture
+	application, err := app.New(cfg)
+	if err != nil {
+		println("❌ Gagal menyiapkan aplikasi:", err.Error())
+		os.Exit(1)
 	}
-	
-	// Server info
-	println("╔════════════════════════════════════════════════════════════╗")
-	println("║                    🚀 Kasir API Server                     ║")
-	println("╠════════════════════════════════════════════════════════════╣")
-	println("║  📍 Endpoint Utama:  http://localhost:" + port + "/                ║")
-	println("║  📖 Swagger UI:      http://localhost:" + port + "/swagger/        ║")
-	println("║  💓 Health Check:    http://localhost:" + port + "/health          ║")
-	println("╠════════════════════════════════════════════════════════════╣")
-	println("║  🐘 Database: PostgreSQL (Neon Serverless)                 ║")
-	println("╠════════════════════════════════════════════════════════════╣")
-	println("║  Layered Architecture:                                     ║")
-	println("║    • Entity     → entity/                                  ║")
-	println("║    • Repository → repository/ (PostgreSQL)                 ║")
-	println("║    • Service    → service/                                 ║")
-	println("║    • Handler    → handler/                                 ║")
-	println("╠════════════════════════════════════════════════════════════╣")
-	println("║  ⭐ Challenge: JOIN Product dengan Category!                ║")
-	println("╚════════════════════════════════════════════════════════════╝")
-	
-	err = http.ListenAndServe(":"+port, nil)
+	defer application.Close()
+
+	err = application.Run()
 	if err != nil {
 		println("❌ Gagal running server:", err.Error())
 	}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/entity"
+	"strings"
+)
+
+// BundleRepositoryInterface - interface untuk komponen produk bundle
+type BundleRepositoryInterface interface {
+	GetComponents(bundleID int) ([]entity.BundleComponent, error)
+	GetComponentsByBundles(bundleIDs []int) (map[int][]entity.BundleComponent, error)
+	SetComponents(bundleID int, components []entity.BundleComponent) error
+	IsComponent(productID int, variantID *int) (bool, error)
+}
+
+// BundleRepository - struct untuk bundle repository
+type BundleRepository struct {
+	db DBTX
+}
+
+// NewBundleRepository - constructor untuk BundleRepository
+func NewBundleRepository(db DBTX) *BundleRepository {
+	return &BundleRepository{db: db}
+}
+
+// bundleComponentQuery - komponen beserta nama produk dan varian, %s = WHERE clause
+const bundleComponentQuery = `
+	SELECT b.bundle_id, b.product_id, b.variant_id, b.quantity, p.nama, v.nama
+	FROM bundle_components b
+	JOIN products p ON p.id = b.product_id
+	LEFT JOIN product_variants v ON v.id = b.variant_id
+	%s
+	ORDER BY b.id`
+
+// GetComponents - komponen satu bundle, urut sesuai urutan input
+func (r *BundleRepository) GetComponents(bundleID int) ([]entity.BundleComponent, error) {
+	grouped, err := r.GetComponentsByBundles([]int{bundleID})
+	if err != nil {
+		return nil, err
+	}
+	return grouped[bundleID], nil
+}
+
+// GetComponentsByBundles - komponen banyak bundle sekaligus (satu query), dikelompokkan per bundle ID
+func (r *BundleRepository) GetComponentsByBundles(bundleIDs []int) (map[int][]entity.BundleComponent, error) {
+	grouped := make(map[int][]entity.BundleComponent)
+	if len(bundleIDs) == 0 {
+		return grouped, nil
+	}
+
+	placeholders := make([]string, len(bundleIDs))
+	args := make([]interface{}, len(bundleIDs))
+	for i, id := range bundleIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	rows, err := r.db.Query(
+		fmt.Sprintf(bundleComponentQuery, "WHERE b.bundle_id IN ("+strings.Join(placeholders, ", ")+")"),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bundleID int
+		var c entity.BundleComponent
+		var variantID sql.NullInt64
+		var variantNama sql.NullString
+		if err := rows.Scan(&bundleID, &c.ProductID, &variantID, &c.Quantity, &c.Nama, &variantNama); err != nil {
+			return nil, err
+		}
+		c.VariantID = nullableInt(variantID)
+		c.Nama = componentName(c.Nama, variantNama)
+		grouped[bundleID] = append(grouped[bundleID], c)
+	}
+	return grouped, rows.Err()
+}
+
+// SetComponents - ganti semua komponen bundle
+func (r *BundleRepository) SetComponents(bundleID int, components []entity.BundleComponent) error {
+	if _, err := r.db.Exec("DELETE FROM bundle_components WHERE bundle_id = $1", bundleID); err != nil {
+		return err
+	}
+	for _, c := range components {
+		_, err := r.db.Exec(
+			"INSERT INTO bundle_components (bundle_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, $4)",
+			bundleID, c.ProductID, c.VariantID, c.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsComponent - apakah produk (atau hanya varian tersebut jika variantID diisi) dipakai oleh bundle
+func (r *BundleRepository) IsComponent(productID int, variantID *int) (bool, error) {
+	query := "SELECT 1 FROM bundle_components WHERE product_id = $1"
+	args := []interface{}{productID}
+	if variantID != nil {
+		query += " AND variant_id = $2"
+		args = append(args, *variantID)
+	}
+
+	var one int
+	err := r.db.QueryRow(query+" LIMIT 1", args...).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// componentName - "Es Teh Manis (Jumbo)" untuk komponen varian, nama produk saja jika tanpa varian
+func componentName(product string, variant sql.NullString) string {
+	if !variant.Valid {
+		return product
+	}
+	return product + " (" + variant.String + ")"
+}
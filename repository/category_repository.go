@@ -2,7 +2,6 @@ package repository
 
 import (
 	"database/sql"
-	"errors"
 	"kasir-api/entity"
 )
 
@@ -13,21 +12,39 @@ type CategoryRepositoryInterface interface {
 	Create(category entity.Category) (entity.Category, error)
 	Update(id int, category entity.Category) (entity.Category, error)
 	Delete(id int) error
+	GetPath(id int) ([]entity.CategoryCrumb, error)
+	MoveChildren(fromParentID int, toParentID *int) (int, error)
+}
+
+// maxCategoryDepth - batas kedalaman CTE rekursif, pengaman jika data berisi siklus
+const maxCategoryDepth = 100
+
+// scanCategory - scan satu baris id, name, description, parent_id
+func scanCategory(row interface{ Scan(dest ...interface{}) error }) (entity.Category, error) {
+	var c entity.Category
+	var description sql.NullString
+	var parentID sql.NullInt64
+	if err := row.Scan(&c.ID, &c.Name, &description, &parentID); err != nil {
+		return entity.Category{}, err
+	}
+	c.Description = description.String
+	c.ParentID = nullableInt(parentID)
+	return c, nil
 }
 
 // CategoryRepository - struct untuk category repository
 type CategoryRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 // NewCategoryRepository - constructor untuk CategoryRepository
-func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+func NewCategoryRepository(db DBTX) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
 // GetAll - ambil semua kategori
 func (r *CategoryRepository) GetAll() ([]entity.Category, error) {
-	rows, err := r.db.Query("SELECT id, name, description FROM categories")
+	rows, err := r.db.Query("SELECT id, name, description, parent_id FROM categories ORDER BY id")
 	if err != nil {
 		return nil, err
 	}
@@ -35,25 +52,22 @@ func (r *CategoryRepository) GetAll() ([]entity.Category, error) {
 
 	var categories []entity.Category
 	for rows.Next() {
-		var c entity.Category
-		err := rows.Scan(&c.ID, &c.Name, &c.Description)
+		c, err := scanCategory(rows)
 		if err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
 	}
 
-	return categories, nil
+	return categories, rows.Err()
 }
 
 // GetByID - ambil kategori berdasarkan ID
 func (r *CategoryRepository) GetByID(id int) (entity.Category, error) {
-	var c entity.Category
-	err := r.db.QueryRow("SELECT id, name, description FROM categories WHERE id = $1", id).
-		Scan(&c.ID, &c.Name, &c.Description)
+	c, err := scanCategory(r.db.QueryRow("SELECT id, name, description, parent_id FROM categories WHERE id = $1", id))
 	
 	if err == sql.ErrNoRows {
-		return entity.Category{}, errors.New("category not found")
+		return entity.Category{}, ErrCategoryNotFound
 	}
 	if err != nil {
 		return entity.Category{}, err
@@ -62,14 +76,21 @@ func (r *CategoryRepository) GetByID(id int) (entity.Category, error) {
 	return c, nil
 }
 
-// Create - tambah kategori baru
+// Create - tambah kategori baru, nama harus unik (case-insensitive) di bawah parent yang sama
 func (r *CategoryRepository) Create(category entity.Category) (entity.Category, error) {
+	if err := r.checkConflict(0, category); err != nil {
+		return entity.Category{}, err
+	}
+
 	var id int
 	err := r.db.QueryRow(
-		"INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id",
-		category.Name, category.Description,
+		"INSERT INTO categories (name, description, parent_id) VALUES ($1, $2, $3) RETURNING id",
+		category.Name, category.Description, category.ParentID,
 	).Scan(&id)
 	
+	if isUniqueViolation(err) {
+		return entity.Category{}, categoryConflict(category, nil)
+	}
 	if err != nil {
 		return entity.Category{}, err
 	}
@@ -78,12 +99,19 @@ func (r *CategoryRepository) Create(category entity.Category) (entity.Category,
 	return category, nil
 }
 
-// Update - update kategori
+// Update - update kategori, nama harus unik (case-insensitive) di bawah parent yang sama
 func (r *CategoryRepository) Update(id int, category entity.Category) (entity.Category, error) {
+	if err := r.checkConflict(id, category); err != nil {
+		return entity.Category{}, err
+	}
+
 	result, err := r.db.Exec(
-		"UPDATE categories SET name = $1, description = $2 WHERE id = $3",
-		category.Name, category.Description, id,
+		"UPDATE categories SET name = $1, description = $2, parent_id = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4",
+		category.Name, category.Description, category.ParentID, id,
 	)
+	if isUniqueViolation(err) {
+		return entity.Category{}, categoryConflict(category, nil)
+	}
 	if err != nil {
 		return entity.Category{}, err
 	}
@@ -93,7 +121,7 @@ func (r *CategoryRepository) Update(id int, category entity.Category) (entity.Ca
 		return entity.Category{}, err
 	}
 	if rowsAffected == 0 {
-		return entity.Category{}, errors.New("category not found")
+		return entity.Category{}, ErrCategoryNotFound
 	}
 
 	category.ID = id
@@ -112,8 +140,118 @@ func (r *CategoryRepository) Delete(id int) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("category not found")
+		return ErrCategoryNotFound
 	}
 
 	return nil
 }
+
+// categoryPathQuery - naik dari kategori ke root dengan recursive CTE
+const categoryPathQuery = `
+	WITH RECURSIVE ancestors (id, name, parent_id, depth) AS (
+		SELECT id, name, parent_id, 0 FROM categories WHERE id = $1
+		UNION ALL
+		SELECT c.id, c.name, c.parent_id, a.depth + 1
+		FROM categories c
+		JOIN ancestors a ON c.id = a.parent_id
+		WHERE a.depth < $2
+	)
+	SELECT id, name FROM ancestors ORDER BY depth DESC`
+
+// GetPath - breadcrumb dari root sampai kategori id
+func (r *CategoryRepository) GetPath(id int) ([]entity.CategoryCrumb, error) {
+	rows, err := r.db.Query(categoryPathQuery, id, maxCategoryDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var path []entity.CategoryCrumb
+	for rows.Next() {
+		var crumb entity.CategoryCrumb
+		if err := rows.Scan(&crumb.ID, &crumb.Name); err != nil {
+			return nil, err
+		}
+		path = append(path, crumb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+
+	return path, nil
+}
+
+// MoveChildren - pindahkan sub-kategori langsung ke parent lain (nil = jadi root)
+func (r *CategoryRepository) MoveChildren(fromParentID int, toParentID *int) (int, error) {
+	if err := r.checkMoveConflict(fromParentID, toParentID); err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Exec(
+		"UPDATE categories SET parent_id = $1, updated_at = CURRENT_TIMESTAMP WHERE parent_id = $2",
+		toParentID, fromParentID,
+	)
+	if isUniqueViolation(err) {
+		return 0, categoryConflict(entity.Category{ParentID: toParentID}, nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	return int(rowsAffected), err
+}
+
+// checkConflict - cari kategori lain dengan nama sama di bawah parent yang sama (id = kategori yang dikecualikan)
+func (r *CategoryRepository) checkConflict(id int, category entity.Category) error {
+	existing, err := scanCategory(r.db.QueryRow(`
+		SELECT id, name, description, parent_id FROM categories
+		WHERE COALESCE(parent_id, 0) = COALESCE($1, 0) AND LOWER(name) = LOWER($2) AND id <> $3
+		LIMIT 1`,
+		category.ParentID, category.Name, id,
+	))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return categoryConflict(category, &existing)
+}
+
+// checkMoveConflict - sub-kategori fromParentID yang namanya sudah dipakai di bawah parent tujuan
+func (r *CategoryRepository) checkMoveConflict(fromParentID int, toParentID *int) error {
+	var name string
+	var existingID int
+	err := r.db.QueryRow(`
+		SELECT c.name, q.id
+		FROM categories c
+		JOIN categories q ON LOWER(q.name) = LOWER(c.name) AND COALESCE(q.parent_id, 0) = COALESCE($2, 0) AND q.id <> c.id
+		WHERE c.parent_id = $1
+		LIMIT 1`,
+		fromParentID, toParentID,
+	).Scan(&name, &existingID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := r.GetByID(existingID)
+	if err != nil {
+		return err
+	}
+	return categoryConflict(entity.Category{Name: name, ParentID: toParentID}, &existing)
+}
+
+// categoryConflict - ConflictError untuk nama kategori, existing nil jika tidak diketahui
+func categoryConflict(category entity.Category, existing *entity.Category) *ConflictError {
+	err := &ConflictError{Resource: "category", Name: category.Name, Scope: "parent category"}
+	if existing != nil {
+		err.Existing = *existing
+	}
+	return err
+}
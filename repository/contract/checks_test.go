@@ -0,0 +1,2975 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// Repos is a fresh, empty set of repositories under test
+type Repos struct {
+	Category    repository.CategoryRepositoryInterface
+	Product     repository.ProductRepositoryInterface
+	Transaction repository.TransactionRepositoryInterface
+	Price       repository.PriceRepositoryInterface
+	Variant     repository.VariantRepositoryInterface
+	Modifier    repository.ModifierRepositoryInterface
+	Bundle      repository.BundleRepositoryInterface
+	Unit        repository.UnitRepositoryInterface
+	Promotion   repository.PromotionRepositoryInterface
+	Voucher     repository.VoucherRepositoryInterface
+	TaxRate     repository.TaxRateRepositoryInterface
+	TxManager   repository.TxManagerInterface
+}
+
+// Check is a single contract rule
+type Check struct {
+	Name string
+	Run  func(r Repos) error
+}
+
+// Checks lists every contract rule in execution order
+var Checks = []Check{
+	{"empty store lists nothing", checkEmpty},
+	{"category IDs follow a sequence starting at 1", checkCategorySequence},
+	{"category CRUD round trip", checkCategoryCRUD},
+	{"unknown category returns ErrCategoryNotFound", checkCategoryNotFound},
+	{"product CRUD round trip", checkProductCRUD},
+	{"unknown product returns ErrProductNotFound", checkProductNotFound},
+	{"product requires an existing category", checkProductForeignKey},
+	{"product harga must not be negative", checkProductNegativeHarga},
+	{"product harga keeps minor units and currency", checkProductMoney},
+	{"product harga beli is optional and round trips", checkProductCost},
+	{"names longer than 100 characters are rejected", checkNameTooLong},
+	{"lists are ordered by ID", checkOrdering},
+	{"deleting a category keeps its products uncategorized", checkDeleteSetNull},
+	{"reassign moves products to another category", checkReassignCategory},
+	{"reassign rejects an unknown target category", checkReassignUnknownTarget},
+	{"soft deleted products are hidden", checkSoftDeleteByCategory},
+	{"category parent must exist and differ from itself", checkCategoryParent},
+	{"category path walks up to the root", checkCategoryPath},
+	{"products of a category include sub-categories on request", checkSubcategoryProducts},
+	{"moving children re-parents direct sub-categories only", checkMoveChildren},
+	{"reassigning products or moving children onto a taken name is a conflict", checkBulkMoveConflict},
+	{"category names are unique per parent, ignoring case", checkCategoryNameConflict},
+	{"product names are unique per category, ignoring case", checkProductNameConflict},
+	{"products embed their category via JOIN", checkWithCategory},
+	{"products may have no category", checkUncategorized},
+	{"transaction saves details with cost snapshots", checkTransactionRoundTrip},
+	{"unknown transaction returns ErrTransactionNotFound", checkTransactionNotFound},
+	{"deleting a product keeps its sale lines", checkDeleteProductKeepsSales},
+	{"margin report groups sales by product and category", checkMarginReport},
+	{"product prices resolve by effective time", checkPriceRanges},
+	{"product prices are unique per start time", checkPriceDuplicateStart},
+	{"deleting a product deletes its prices", checkDeleteProductPrices},
+	{"variant CRUD round trip keeps options", checkVariantCRUD},
+	{"variant harga beli is optional and round trips", checkVariantCost},
+	{"variant SKUs are unique per store and names per product", checkVariantConflict},
+	{"variant stock never goes negative", checkVariantStock},
+	{"deleting a product deletes its variants", checkDeleteProductVariants},
+	{"deleting a variant keeps its sale lines", checkDeleteVariantKeepsSales},
+	{"modifier groups round trip with their modifiers", checkModifierGroupCRUD},
+	{"modifier groups belong to exactly one product or category", checkModifierGroupTarget},
+	{"modifier names are unique per group, ignoring case", checkModifierConflict},
+	{"deleting a product or category deletes its modifier groups", checkDeleteModifierGroupCascade},
+	{"sale lines keep modifier copies after the modifier is deleted", checkDeleteModifierKeepsSales},
+	{"product type and tracked stock round trip", checkProductStock},
+	{"bundle components round trip with names", checkBundleComponents},
+	{"bundle components restrict deleting their products and variants", checkBundleComponentRestrict},
+	{"default units exist and units round trip", checkUnitCRUD},
+	{"units still used by a product cannot be deleted", checkUnitInUse},
+	{"product units and conversions round trip", checkProductUnits},
+	{"sale lines keep decimal quantities, unit and base quantity", checkDecimalSaleLines},
+	{"product images are set, kept on update and cleared", checkProductImage},
+	{"promotion CRUD round trips and active promotions are ordered by priority", checkPromotionCRUD},
+	{"deleting a product or category deletes its promotions", checkDeletePromotionCascade},
+	{"transaction discounts round trip and keep history after the promotion is deleted", checkTransactionDiscounts},
+	{"voucher CRUD with unique codes", checkVoucherCRUD},
+	{"voucher redemption respects global and per-customer limits", checkVoucherRedeem},
+	{"deleting a voucher removes redemptions and keeps transaction discounts", checkDeleteVoucherCascade},
+	{"tax rate CRUD starts from an empty table", checkTaxRateCRUD},
+	{"deleting a category removes it from tax exemptions", checkDeleteCategoryTaxExemption},
+	{"transaction taxes round trip, feed the tax report and keep history after the rate is deleted", checkTransactionTaxes},
+	{"concurrent creates get unique IDs", checkConcurrentCreate},
+	{"transaction commits every write", checkTxCommit},
+	{"transaction rolls back on error", checkTxRollback},
+	{"transaction rolls back on panic", checkTxPanic},
+	{"read-only transaction sees committed data and returns fn's error", checkReadTx},
+}
+
+func checkEmpty(r Repos) error {
+	categories, err := r.Category.GetAll()
+	if err != nil {
+		return err
+	}
+	products, err := r.Product.GetAll(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	if len(categories) != 0 || len(products) != 0 {
+		return fmt.Errorf("expected empty lists, got %d categories and %d products", len(categories), len(products))
+	}
+	return nil
+}
+
+func checkCategorySequence(r Repos) error {
+	for want := 1; want <= 3; want++ {
+		c, err := r.Category.Create(entity.Category{Name: fmt.Sprintf("Kategori %d", want)})
+		if err != nil {
+			return err
+		}
+		if c.ID != want {
+			return fmt.Errorf("expected ID %d, got %d", want, c.ID)
+		}
+	}
+	return nil
+}
+
+func checkCategoryCRUD(r Repos) error {
+	created, err := r.Category.Create(entity.Category{Name: "Minuman", Description: "Segala jenis minuman"})
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Category.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.Name != "Minuman" || got.Description != "Segala jenis minuman" {
+		return fmt.Errorf("GetByID returned %+v", got)
+	}
+
+	updated, err := r.Category.Update(created.ID, entity.Category{Name: "Minuman Dingin", Description: "Es"})
+	if err != nil {
+		return err
+	}
+	if updated.ID != created.ID {
+		return fmt.Errorf("Update changed ID from %d to %d", created.ID, updated.ID)
+	}
+	got, err = r.Category.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.Name != "Minuman Dingin" || got.Description != "Es" {
+		return fmt.Errorf("update not persisted, got %+v", got)
+	}
+
+	if err := r.Category.Delete(created.ID); err != nil {
+		return err
+	}
+	if _, err := r.Category.GetByID(created.ID); !errors.Is(err, repository.ErrCategoryNotFound) {
+		return fmt.Errorf("expected ErrCategoryNotFound after delete, got %v", err)
+	}
+	return nil
+}
+
+func checkCategoryNotFound(r Repos) error {
+	if _, err := r.Category.GetByID(999); !errors.Is(err, repository.ErrCategoryNotFound) {
+		return fmt.Errorf("GetByID: expected ErrCategoryNotFound, got %v", err)
+	}
+	if _, err := r.Category.Update(999, entity.Category{Name: "X"}); !errors.Is(err, repository.ErrCategoryNotFound) {
+		return fmt.Errorf("Update: expected ErrCategoryNotFound, got %v", err)
+	}
+	if err := r.Category.Delete(999); !errors.Is(err, repository.ErrCategoryNotFound) {
+		return fmt.Errorf("Delete: expected ErrCategoryNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkProductCRUD(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	makanan, err := r.Category.Create(entity.Category{Name: "Makanan"})
+	if err != nil {
+		return err
+	}
+
+	created, err := r.Product.Create(entity.Product{Nama: "Es Teh Manis", Harga: entity.IDR(5000), CategoryID: intPtr(minuman.ID)})
+	if err != nil {
+		return err
+	}
+	if created.ID != 1 {
+		return fmt.Errorf("expected first product ID 1, got %d", created.ID)
+	}
+
+	got, err := r.Product.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.Nama != "Es Teh Manis" || got.Harga != entity.IDR(5000) || !hasCategory(got, minuman.ID) {
+		return fmt.Errorf("GetByID returned %+v", got)
+	}
+
+	_, err = r.Product.Update(created.ID, entity.Product{Nama: "Nasi Goreng", Harga: entity.IDR(15000), CategoryID: intPtr(makanan.ID)})
+	if err != nil {
+		return err
+	}
+	got, err = r.Product.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.Nama != "Nasi Goreng" || got.Harga != entity.IDR(15000) || !hasCategory(got, makanan.ID) {
+		return fmt.Errorf("update not persisted, got %+v", got)
+	}
+
+	if err := r.Product.Delete(created.ID); err != nil {
+		return err
+	}
+	if _, err := r.Product.GetByID(created.ID); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("expected ErrProductNotFound after delete, got %v", err)
+	}
+	return nil
+}
+
+func checkProductNotFound(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Snack"})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Product.GetByID(999); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("GetByID: expected ErrProductNotFound, got %v", err)
+	}
+	if _, err := r.Product.Update(999, entity.Product{Nama: "X", CategoryID: intPtr(c.ID)}); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("Update: expected ErrProductNotFound, got %v", err)
+	}
+	if err := r.Product.Delete(999); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("Delete: expected ErrProductNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkProductForeignKey(r Repos) error {
+	if _, err := r.Product.Create(entity.Product{Nama: "Yatim", Harga: entity.IDR(1000), CategoryID: intPtr(999)}); err == nil {
+		return errors.New("Create with unknown category succeeded")
+	}
+
+	c, err := r.Category.Create(entity.Category{Name: "Snack"})
+	if err != nil {
+		return err
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Chocolatos", Harga: entity.IDR(2000), CategoryID: intPtr(c.ID)})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Product.Update(p.ID, entity.Product{Nama: "Chocolatos", Harga: entity.IDR(2000), CategoryID: intPtr(999)}); err == nil {
+		return errors.New("Update to unknown category succeeded")
+	}
+	return nil
+}
+
+func checkProductNegativeHarga(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Snack"})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "Minus", Harga: entity.IDR(-1), CategoryID: intPtr(c.ID)}); err == nil {
+		return errors.New("Create with negative harga succeeded")
+	}
+	return nil
+}
+
+func checkProductMoney(r Repos) error {
+	cost, err := entity.ParseMoney("1250.50", "IDR")
+	if err != nil {
+		return err
+	}
+	usd := entity.NewMoney(1999, "USD")
+
+	for _, tc := range []struct {
+		nama  string
+		harga entity.Money
+		want  entity.Money
+	}{
+		{"Gula Pasir Curah", cost, cost},
+		{"Imported Snack", usd, usd},
+		{"Tanpa Mata Uang", entity.Money{Amount: 500000}, entity.IDR(5000)},
+	} {
+		created, err := r.Product.Create(entity.Product{Nama: tc.nama, Harga: tc.harga})
+		if err != nil {
+			return err
+		}
+		if created.Harga != tc.want {
+			return fmt.Errorf("Create %s returned harga %+v, want %+v", tc.nama, created.Harga, tc.want)
+		}
+		got, err := r.Product.GetByIDWithCategory(created.ID)
+		if err != nil {
+			return err
+		}
+		if got.Harga != tc.want {
+			return fmt.Errorf("%s: expected harga %+v, got %+v", tc.nama, tc.want, got.Harga)
+		}
+	}
+	return nil
+}
+
+func checkProductCost(r Repos) error {
+	cost := entity.IDR(8000)
+	created, err := r.Product.Create(entity.Product{Nama: "Kopi Susu", Harga: entity.IDR(12000), HargaBeli: &cost})
+	if err != nil {
+		return err
+	}
+	got, err := r.Product.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.HargaBeli == nil || *got.HargaBeli != cost {
+		return fmt.Errorf("expected harga beli %+v, got %+v", cost, got.HargaBeli)
+	}
+
+	// Produk tanpa harga beli tetap valid
+	got.HargaBeli = nil
+	if _, err := r.Product.Update(got.ID, got); err != nil {
+		return err
+	}
+	products, err := r.Product.GetAllWithCategory(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	if len(products) != 1 || products[0].HargaBeli != nil {
+		return fmt.Errorf("expected harga beli cleared, got %+v", products)
+	}
+
+	negative := entity.IDR(-1)
+	got.HargaBeli = &negative
+	if _, err := r.Product.Update(got.ID, got); err == nil {
+		return errors.New("Update with negative harga beli succeeded")
+	}
+	return nil
+}
+
+func checkNameTooLong(r Repos) error {
+	long := strings.Repeat("a", 101)
+	if _, err := r.Category.Create(entity.Category{Name: long}); err == nil {
+		return errors.New("Create category with 101 character name succeeded")
+	}
+
+	c, err := r.Category.Create(entity.Category{Name: strings.Repeat("a", 100)})
+	if err != nil {
+		return fmt.Errorf("100 character name must be accepted: %w", err)
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: long, CategoryID: intPtr(c.ID)}); err == nil {
+		return errors.New("Create product with 101 character nama succeeded")
+	}
+	return nil
+}
+
+func checkOrdering(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Makanan"})
+	if err != nil {
+		return err
+	}
+	for _, nama := range []string{"Nasi Goreng", "Mie Ayam", "Bakso"} {
+		if _, err := r.Product.Create(entity.Product{Nama: nama, Harga: entity.IDR(10000), CategoryID: intPtr(c.ID)}); err != nil {
+			return err
+		}
+	}
+	// Update tidak boleh mengubah urutan
+	if _, err := r.Product.Update(1, entity.Product{Nama: "Nasi Goreng Spesial", Harga: entity.IDR(20000), CategoryID: intPtr(c.ID)}); err != nil {
+		return err
+	}
+
+	products, err := r.Product.GetAll(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	for i, p := range products {
+		if p.ID != i+1 {
+			return fmt.Errorf("expected product %d at position %d, got %d", i+1, i, p.ID)
+		}
+	}
+	return nil
+}
+
+func checkDeleteSetNull(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Elektronik"})
+	if err != nil {
+		return err
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Kabel Data", Harga: entity.IDR(25000), CategoryID: intPtr(c.ID)})
+	if err != nil {
+		return err
+	}
+
+	if err := r.Category.Delete(c.ID); err != nil {
+		return err
+	}
+
+	got, err := r.Product.GetByID(p.ID)
+	if err != nil {
+		return fmt.Errorf("product must survive category delete: %w", err)
+	}
+	if got.CategoryID != nil {
+		return fmt.Errorf("expected no category after delete, got category %d", *got.CategoryID)
+	}
+
+	products, err := r.Product.GetAll(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	if len(products) != 1 {
+		return fmt.Errorf("expected 1 product, got %d", len(products))
+	}
+	return nil
+}
+
+func checkReassignCategory(r Repos) error {
+	from, err := r.Category.Create(entity.Category{Name: "Lama"})
+	if err != nil {
+		return err
+	}
+	to, err := r.Category.Create(entity.Category{Name: "Baru"})
+	if err != nil {
+		return err
+	}
+	for _, nama := range []string{"Teh Botol", "Kopi Susu"} {
+		if _, err := r.Product.Create(entity.Product{Nama: nama, Harga: entity.IDR(5000), CategoryID: intPtr(from.ID)}); err != nil {
+			return err
+		}
+	}
+	other, err := r.Product.Create(entity.Product{Nama: "Roti", Harga: entity.IDR(8000), CategoryID: intPtr(to.ID)})
+	if err != nil {
+		return err
+	}
+
+	moved, err := r.Product.ReassignCategory(from.ID, intPtr(to.ID))
+	if err != nil {
+		return err
+	}
+	if moved != 2 {
+		return fmt.Errorf("expected 2 products moved, got %d", moved)
+	}
+
+	products, err := r.Product.GetAll(entity.ProductFilter{CategoryID: to.ID})
+	if err != nil {
+		return err
+	}
+	if len(products) != 3 {
+		return fmt.Errorf("expected 3 products in target category, got %d", len(products))
+	}
+	products, err = r.Product.GetAll(entity.ProductFilter{CategoryID: from.ID})
+	if err != nil {
+		return err
+	}
+	if len(products) != 0 {
+		return fmt.Errorf("expected source category to be empty, got %d products", len(products))
+	}
+	got, err := r.Product.GetByID(other.ID)
+	if err != nil {
+		return err
+	}
+	if !hasCategory(got, to.ID) {
+		return fmt.Errorf("product outside the source category must not change")
+	}
+	return nil
+}
+
+func checkReassignUnknownTarget(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Lama"})
+	if err != nil {
+		return err
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Teh Botol", Harga: entity.IDR(5000), CategoryID: intPtr(c.ID)})
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.Product.ReassignCategory(c.ID, intPtr(999)); err == nil {
+		return fmt.Errorf("expected error reassigning to a missing category")
+	}
+
+	got, err := r.Product.GetByID(p.ID)
+	if err != nil {
+		return err
+	}
+	if !hasCategory(got, c.ID) {
+		return fmt.Errorf("failed reassign must not move products")
+	}
+	return nil
+}
+
+func checkSoftDeleteByCategory(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Musiman"})
+	if err != nil {
+		return err
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Kue Lebaran", Harga: entity.IDR(45000), CategoryID: intPtr(c.ID)})
+	if err != nil {
+		return err
+	}
+	kept, err := r.Product.Create(entity.Product{Nama: "Air Mineral", Harga: entity.IDR(3000)})
+	if err != nil {
+		return err
+	}
+
+	deleted, err := r.Product.SoftDeleteByCategory(c.ID)
+	if err != nil {
+		return err
+	}
+	if deleted != 1 {
+		return fmt.Errorf("expected 1 product soft deleted, got %d", deleted)
+	}
+
+	if _, err := r.Product.GetByID(p.ID); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("GetByID of soft deleted product: expected ErrProductNotFound, got %v", err)
+	}
+	if _, err := r.Product.GetByIDWithCategory(p.ID); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("GetByIDWithCategory of soft deleted product: expected ErrProductNotFound, got %v", err)
+	}
+	if _, err := r.Product.Update(p.ID, entity.Product{Nama: "X"}); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("Update of soft deleted product: expected ErrProductNotFound, got %v", err)
+	}
+	if err := r.Product.Delete(p.ID); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("Delete of soft deleted product: expected ErrProductNotFound, got %v", err)
+	}
+
+	products, err := r.Product.GetAllWithCategory(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	if len(products) != 1 || products[0].ID != kept.ID {
+		return fmt.Errorf("expected only product %d to remain visible, got %d products", kept.ID, len(products))
+	}
+
+	// Kategori tetap bisa dihapus setelah produknya di-soft delete
+	return r.Category.Delete(c.ID)
+}
+
+func checkCategoryParent(r Repos) error {
+	if _, err := r.Category.Create(entity.Category{Name: "Yatim", ParentID: intPtr(999)}); err == nil {
+		return fmt.Errorf("expected error for missing parent category")
+	}
+
+	parent, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	child, err := r.Category.Create(entity.Category{Name: "Kopi", ParentID: intPtr(parent.ID)})
+	if err != nil {
+		return err
+	}
+	got, err := r.Category.GetByID(child.ID)
+	if err != nil {
+		return err
+	}
+	if got.ParentID == nil || *got.ParentID != parent.ID {
+		return fmt.Errorf("expected parent %d, got %v", parent.ID, got.ParentID)
+	}
+
+	if _, err := r.Category.Update(parent.ID, entity.Category{Name: "Minuman", ParentID: intPtr(parent.ID)}); err == nil {
+		return fmt.Errorf("expected error when a category is its own parent")
+	}
+
+	// Menghapus parent membuat sub-kategori menjadi root (ON DELETE SET NULL)
+	if err := r.Category.Delete(parent.ID); err != nil {
+		return err
+	}
+	got, err = r.Category.GetByID(child.ID)
+	if err != nil {
+		return fmt.Errorf("sub-category must survive parent delete: %w", err)
+	}
+	if got.ParentID != nil {
+		return fmt.Errorf("expected root category after parent delete, got parent %d", *got.ParentID)
+	}
+	return nil
+}
+
+func checkCategoryPath(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	kopi, err := r.Category.Create(entity.Category{Name: "Kopi", ParentID: intPtr(minuman.ID)})
+	if err != nil {
+		return err
+	}
+	kopiSusu, err := r.Category.Create(entity.Category{Name: "Kopi Susu", ParentID: intPtr(kopi.ID)})
+	if err != nil {
+		return err
+	}
+
+	path, err := r.Category.GetPath(kopiSusu.ID)
+	if err != nil {
+		return err
+	}
+	want := []entity.CategoryCrumb{{ID: minuman.ID, Name: "Minuman"}, {ID: kopi.ID, Name: "Kopi"}, {ID: kopiSusu.ID, Name: "Kopi Susu"}}
+	if fmt.Sprint(path) != fmt.Sprint(want) {
+		return fmt.Errorf("expected path %v, got %v", want, path)
+	}
+
+	if _, err := r.Category.GetPath(999); !errors.Is(err, repository.ErrCategoryNotFound) {
+		return fmt.Errorf("GetPath(999): expected ErrCategoryNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkSubcategoryProducts(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	kopi, err := r.Category.Create(entity.Category{Name: "Kopi", ParentID: intPtr(minuman.ID)})
+	if err != nil {
+		return err
+	}
+	kopiSusu, err := r.Category.Create(entity.Category{Name: "Kopi Susu", ParentID: intPtr(kopi.ID)})
+	if err != nil {
+		return err
+	}
+	makanan, err := r.Category.Create(entity.Category{Name: "Makanan"})
+	if err != nil {
+		return err
+	}
+	for _, p := range []entity.Product{
+		{Nama: "Air Mineral", Harga: entity.IDR(3000), CategoryID: intPtr(minuman.ID)},
+		{Nama: "Kopi Hitam", Harga: entity.IDR(8000), CategoryID: intPtr(kopi.ID)},
+		{Nama: "Es Kopi Susu", Harga: entity.IDR(15000), CategoryID: intPtr(kopiSusu.ID)},
+		{Nama: "Nasi Goreng", Harga: entity.IDR(15000), CategoryID: intPtr(makanan.ID)},
+	} {
+		if _, err := r.Product.Create(p); err != nil {
+			return err
+		}
+	}
+
+	cases := []struct {
+		filter entity.ProductFilter
+		want   int
+	}{
+		{entity.ProductFilter{CategoryID: minuman.ID}, 1},
+		{entity.ProductFilter{CategoryID: minuman.ID, IncludeSubcategories: true}, 3},
+		{entity.ProductFilter{CategoryID: kopi.ID, IncludeSubcategories: true}, 2},
+		{entity.ProductFilter{CategoryID: kopiSusu.ID, IncludeSubcategories: true}, 1},
+	}
+	for _, tc := range cases {
+		products, err := r.Product.GetAllWithCategory(tc.filter)
+		if err != nil {
+			return err
+		}
+		if len(products) != tc.want {
+			return fmt.Errorf("filter %+v: expected %d products, got %d", tc.filter, tc.want, len(products))
+		}
+	}
+	return nil
+}
+
+func checkBulkMoveConflict(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	lama, err := r.Category.Create(entity.Category{Name: "Lama"})
+	if err != nil {
+		return err
+	}
+	teh, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000), CategoryID: &minuman.ID})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(8000), CategoryID: &lama.ID}); err != nil {
+		return err
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "ES TEH", Harga: entity.IDR(6000), CategoryID: &lama.ID}); err != nil {
+		return err
+	}
+
+	// Tidak ada produk yang pindah jika satu saja bentrok
+	_, err = r.Product.ReassignCategory(lama.ID, &minuman.ID)
+	if err := expectConflict(err, teh.ID); err != nil {
+		return err
+	}
+	products, err := r.Product.GetAll(entity.ProductFilter{CategoryID: lama.ID})
+	if err != nil {
+		return err
+	}
+	if len(products) != 2 {
+		return fmt.Errorf("expected products to stay in category %d, got %+v", lama.ID, products)
+	}
+
+	dingin, err := r.Category.Create(entity.Category{Name: "Dingin", ParentID: &lama.ID})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Category.Create(entity.Category{Name: "dingin", ParentID: &minuman.ID}); err != nil {
+		return err
+	}
+	_, err = r.Category.MoveChildren(minuman.ID, &lama.ID)
+	if err := expectConflict(err, dingin.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func checkMoveChildren(r Repos) error {
+	root, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	kopi, err := r.Category.Create(entity.Category{Name: "Kopi", ParentID: intPtr(root.ID)})
+	if err != nil {
+		return err
+	}
+	kopiSusu, err := r.Category.Create(entity.Category{Name: "Kopi Susu", ParentID: intPtr(kopi.ID)})
+	if err != nil {
+		return err
+	}
+
+	moved, err := r.Category.MoveChildren(root.ID, nil)
+	if err != nil {
+		return err
+	}
+	if moved != 1 {
+		return fmt.Errorf("expected 1 sub-category moved, got %d", moved)
+	}
+
+	got, err := r.Category.GetByID(kopi.ID)
+	if err != nil {
+		return err
+	}
+	if got.ParentID != nil {
+		return fmt.Errorf("expected %q to become a root category", got.Name)
+	}
+	got, err = r.Category.GetByID(kopiSusu.ID)
+	if err != nil {
+		return err
+	}
+	if got.ParentID == nil || *got.ParentID != kopi.ID {
+		return fmt.Errorf("grandchild must keep its parent")
+	}
+	return nil
+}
+
+func checkCategoryNameConflict(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Category.Create(entity.Category{Name: "MINUMAN"})
+	if err := expectConflict(err, minuman.ID); err != nil {
+		return fmt.Errorf("duplicate root category: %w", err)
+	}
+
+	// Nama sama boleh di bawah parent berbeda
+	kopi, err := r.Category.Create(entity.Category{Name: "Kopi", ParentID: intPtr(minuman.ID)})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Category.Create(entity.Category{Name: "Kopi"}); err != nil {
+		return fmt.Errorf("same name under another parent must be allowed: %w", err)
+	}
+	_, err = r.Category.Create(entity.Category{Name: "kopi", ParentID: intPtr(minuman.ID)})
+	if err := expectConflict(err, kopi.ID); err != nil {
+		return fmt.Errorf("duplicate sub-category: %w", err)
+	}
+
+	// Update ke nama sendiri boleh, ke nama kategori lain ditolak
+	if _, err := r.Category.Update(kopi.ID, entity.Category{Name: "KOPI", ParentID: intPtr(minuman.ID)}); err != nil {
+		return fmt.Errorf("renaming a category to its own name must be allowed: %w", err)
+	}
+	makanan, err := r.Category.Create(entity.Category{Name: "Makanan"})
+	if err != nil {
+		return err
+	}
+	_, err = r.Category.Update(makanan.ID, entity.Category{Name: "minuman"})
+	return expectConflict(err, minuman.ID)
+}
+
+func checkProductNameConflict(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	snack, err := r.Category.Create(entity.Category{Name: "Snack"})
+	if err != nil {
+		return err
+	}
+	teh, err := r.Product.Create(entity.Product{Nama: "Teh Botol", Harga: entity.IDR(5000), CategoryID: intPtr(minuman.ID)})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Product.Create(entity.Product{Nama: "teh botol", Harga: entity.IDR(6000), CategoryID: intPtr(minuman.ID)})
+	if err := expectConflict(err, teh.ID); err != nil {
+		return fmt.Errorf("duplicate product: %w", err)
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "Teh Botol", Harga: entity.IDR(5000), CategoryID: intPtr(snack.ID)}); err != nil {
+		return fmt.Errorf("same name in another category must be allowed: %w", err)
+	}
+
+	air, err := r.Product.Create(entity.Product{Nama: "Air Mineral", Harga: entity.IDR(3000)})
+	if err != nil {
+		return err
+	}
+	_, err = r.Product.Create(entity.Product{Nama: "AIR MINERAL", Harga: entity.IDR(3000)})
+	if err := expectConflict(err, air.ID); err != nil {
+		return fmt.Errorf("duplicate uncategorized product: %w", err)
+	}
+
+	// Produk yang sudah di-soft delete tidak menghalangi nama yang sama
+	if _, err := r.Product.SoftDeleteByCategory(minuman.ID); err != nil {
+		return err
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "Teh Botol", Harga: entity.IDR(5500), CategoryID: intPtr(minuman.ID)}); err != nil {
+		return fmt.Errorf("name of a soft deleted product must be reusable: %w", err)
+	}
+	return nil
+}
+
+func checkWithCategory(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Minuman", Description: "Segala jenis minuman"})
+	if err != nil {
+		return err
+	}
+	other, err := r.Category.Create(entity.Category{Name: "Hapus"})
+	if err != nil {
+		return err
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Es Teh Manis", Harga: entity.IDR(5000), CategoryID: intPtr(c.ID)})
+	if err != nil {
+		return err
+	}
+	orphan, err := r.Product.Create(entity.Product{Nama: "Yatim", Harga: entity.IDR(1000), CategoryID: intPtr(other.ID)})
+	if err != nil {
+		return err
+	}
+	if err := r.Category.Delete(other.ID); err != nil {
+		return err
+	}
+
+	got, err := r.Product.GetByIDWithCategory(p.ID)
+	if err != nil {
+		return err
+	}
+	if got.Category == nil || got.Category.ID != c.ID || got.Category.Name != "Minuman" || got.Category.Description != "Segala jenis minuman" {
+		return fmt.Errorf("GetByIDWithCategory returned category %+v", got.Category)
+	}
+	if _, err := r.Product.GetByIDWithCategory(999); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+
+	products, err := r.Product.GetAllWithCategory(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	if len(products) != 2 {
+		return fmt.Errorf("expected 2 products, got %d", len(products))
+	}
+	if products[0].Category == nil || products[0].Category.Name != "Minuman" {
+		return fmt.Errorf("expected first product in Minuman, got %+v", products[0].Category)
+	}
+	if products[1].ID != orphan.ID || products[1].Category != nil {
+		return fmt.Errorf("expected uncategorized product without category, got %+v", products[1].Category)
+	}
+	return nil
+}
+
+func checkUncategorized(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Snack"})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "Chocolatos", Harga: entity.IDR(2000), CategoryID: intPtr(c.ID)}); err != nil {
+		return err
+	}
+	loose, err := r.Product.Create(entity.Product{Nama: "Kantong Plastik", Harga: entity.IDR(500)})
+	if err != nil {
+		return fmt.Errorf("product without category must be accepted: %w", err)
+	}
+
+	got, err := r.Product.GetByIDWithCategory(loose.ID)
+	if err != nil {
+		return err
+	}
+	if got.CategoryID != nil || got.Category != nil {
+		return fmt.Errorf("expected no category, got %v / %+v", got.CategoryID, got.Category)
+	}
+
+	uncategorized, err := r.Product.GetAll(entity.ProductFilter{Uncategorized: true})
+	if err != nil {
+		return err
+	}
+	if len(uncategorized) != 1 || uncategorized[0].ID != loose.ID {
+		return fmt.Errorf("uncategorized filter returned %+v", uncategorized)
+	}
+
+	inCategory, err := r.Product.GetAllWithCategory(entity.ProductFilter{CategoryID: c.ID})
+	if err != nil {
+		return err
+	}
+	if len(inCategory) != 1 || inCategory[0].Nama != "Chocolatos" {
+		return fmt.Errorf("category filter returned %+v", inCategory)
+	}
+	return nil
+}
+
+// saleLine is one product sold in a contract transaction
+type saleLine struct {
+	product  entity.Product
+	quantity int
+}
+
+// sale creates a transaction with one line per product, cost snapshots from the products
+func sale(r Repos, at time.Time, lines ...saleLine) (entity.Transaction, error) {
+	t := entity.Transaction{CreatedAt: at}
+	for _, line := range lines {
+		p := line.product
+		subtotal, err := p.Harga.Mul(int64(line.quantity))
+		if err != nil {
+			return entity.Transaction{}, err
+		}
+		if t.TotalAmount, err = t.TotalAmount.Add(subtotal); err != nil {
+			return entity.Transaction{}, err
+		}
+		t.Details = append(t.Details, entity.TransactionDetail{
+			ProductID: intPtr(p.ID), NamaProduk: p.Nama, Harga: p.Harga, HargaBeli: p.HargaBeli,
+			Quantity: entity.Qty(int64(line.quantity)), Subtotal: subtotal,
+		})
+	}
+	return r.Transaction.Create(t)
+}
+
+func checkTransactionRoundTrip(r Repos) error {
+	cost := entity.IDR(3000)
+	teh, err := r.Product.Create(entity.Product{Nama: "Teh", Harga: entity.IDR(5000), HargaBeli: &cost})
+	if err != nil {
+		return err
+	}
+	roti, err := r.Product.Create(entity.Product{Nama: "Roti", Harga: entity.IDR(7000)})
+	if err != nil {
+		return err
+	}
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	created, err := sale(r, at, saleLine{teh, 2}, saleLine{roti, 1})
+	if err != nil {
+		return err
+	}
+	if created.ID != 1 || len(created.Details) != 2 || created.Details[0].ID == 0 {
+		return fmt.Errorf("unexpected created transaction %+v", created)
+	}
+
+	got, err := r.Transaction.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.TotalAmount != entity.IDR(17000) || !got.CreatedAt.Equal(at) {
+		return fmt.Errorf("expected total Rp 17.000 at %s, got %s at %s", at, got.TotalAmount, got.CreatedAt)
+	}
+	if len(got.Details) != 2 {
+		return fmt.Errorf("expected 2 details, got %d", len(got.Details))
+	}
+	tehLine, rotiLine := got.Details[0], got.Details[1]
+	if tehLine.ProductID == nil || *tehLine.ProductID != teh.ID || tehLine.Subtotal != entity.IDR(10000) ||
+		tehLine.HargaBeli == nil || *tehLine.HargaBeli != cost {
+		return fmt.Errorf("unexpected teh line %+v", tehLine)
+	}
+	if rotiLine.HargaBeli != nil || rotiLine.NamaProduk != "Roti" || rotiLine.TransactionID != created.ID {
+		return fmt.Errorf("unexpected roti line %+v", rotiLine)
+	}
+	return nil
+}
+
+func checkTransactionNotFound(r Repos) error {
+	if _, err := r.Transaction.GetByID(999); !errors.Is(err, repository.ErrTransactionNotFound) {
+		return fmt.Errorf("expected ErrTransactionNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkDeleteProductKeepsSales(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Musiman", Harga: entity.IDR(1000)})
+	if err != nil {
+		return err
+	}
+	t, err := sale(r, time.Now(), saleLine{p, 3})
+	if err != nil {
+		return err
+	}
+	if err := r.Product.Delete(p.ID); err != nil {
+		return err
+	}
+
+	got, err := r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	if len(got.Details) != 1 || got.Details[0].ProductID != nil || got.Details[0].NamaProduk != "Musiman" {
+		return fmt.Errorf("expected sale line kept without product_id, got %+v", got.Details)
+	}
+	return nil
+}
+
+func checkMarginReport(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	cost := entity.IDR(3000)
+	teh, err := r.Product.Create(entity.Product{Nama: "Teh", Harga: entity.IDR(5000), HargaBeli: &cost, CategoryID: intPtr(minuman.ID)})
+	if err != nil {
+		return err
+	}
+	roti, err := r.Product.Create(entity.Product{Nama: "Roti", Harga: entity.IDR(7000)})
+	if err != nil {
+		return err
+	}
+
+	jan := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)
+	if _, err := sale(r, jan, saleLine{teh, 2}, saleLine{roti, 1}); err != nil {
+		return err
+	}
+	if _, err := sale(r, feb, saleLine{teh, 1}); err != nil {
+		return err
+	}
+
+	byProduct, err := r.Transaction.MarginReport(entity.MarginFilter{GroupBy: entity.MarginByProduct})
+	if err != nil {
+		return err
+	}
+	rows := map[string]entity.MarginRow{}
+	for _, row := range byProduct {
+		rows[row.Name] = row
+	}
+	if row := rows["Teh"]; len(byProduct) != 2 || row.Quantity != entity.Qty(3) || row.Revenue != entity.IDR(15000) ||
+		row.Cost != entity.IDR(9000) || !row.UncostedRevenue.IsZero() {
+		return fmt.Errorf("unexpected product report %+v", byProduct)
+	}
+	if row := rows["Roti"]; row.Cost.Amount != 0 || row.UncostedRevenue != entity.IDR(7000) {
+		return fmt.Errorf("uncosted line must be reported separately, got %+v", row)
+	}
+
+	januari, err := r.Transaction.MarginReport(entity.MarginFilter{
+		GroupBy: entity.MarginByCategory,
+		From:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return err
+	}
+	if len(januari) != 2 {
+		return fmt.Errorf("expected Minuman and uncategorized rows, got %+v", januari)
+	}
+	for _, row := range januari {
+		switch {
+		case row.ID != nil && *row.ID == minuman.ID:
+			if row.Name != "Minuman" || row.Revenue != entity.IDR(10000) || row.Cost != entity.IDR(6000) {
+				return fmt.Errorf("unexpected Minuman row %+v", row)
+			}
+		case row.ID == nil:
+			if row.Revenue != entity.IDR(7000) || row.UncostedRevenue != entity.IDR(7000) {
+				return fmt.Errorf("unexpected uncategorized row %+v", row)
+			}
+		default:
+			return fmt.Errorf("unexpected row %+v", row)
+		}
+	}
+	return nil
+}
+
+func checkPriceRanges(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Nasi Goreng", Harga: entity.IDR(15000)})
+	if err != nil {
+		return err
+	}
+	oct := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	nov := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	// Dibuat tidak berurutan, dibaca urut effective_from
+	scheduled, err := r.Price.Create(entity.ProductPrice{ProductID: p.ID, Harga: entity.IDR(18000), EffectiveFrom: nov})
+	if err != nil {
+		return err
+	}
+	first, err := r.Price.Create(entity.ProductPrice{ProductID: p.ID, Harga: entity.IDR(15000), EffectiveFrom: oct, EffectiveTo: &nov})
+	if err != nil {
+		return err
+	}
+
+	prices, err := r.Price.GetByProduct(p.ID)
+	if err != nil {
+		return err
+	}
+	if len(prices) != 2 || prices[0].ID != first.ID || prices[1].ID != scheduled.ID {
+		return fmt.Errorf("expected prices ordered by effective_from, got %+v", prices)
+	}
+	if prices[0].EffectiveTo == nil || !prices[0].EffectiveTo.Equal(nov) || prices[1].EffectiveTo != nil {
+		return fmt.Errorf("unexpected ranges %+v", prices)
+	}
+
+	for _, tc := range []struct {
+		at   time.Time
+		want entity.Money
+	}{
+		{oct, entity.IDR(15000)},
+		{nov.Add(-time.Microsecond), entity.IDR(15000)},
+		{nov, entity.IDR(18000)},
+		{nov.AddDate(1, 0, 0), entity.IDR(18000)},
+	} {
+		got, err := r.Price.GetEffective(p.ID, tc.at)
+		if err != nil {
+			return fmt.Errorf("GetEffective at %s: %w", tc.at, err)
+		}
+		if got.Harga != tc.want {
+			return fmt.Errorf("at %s expected %s, got %s", tc.at, tc.want, got.Harga)
+		}
+		current, err := r.Price.CurrentPrices(tc.at)
+		if err != nil {
+			return err
+		}
+		if current[p.ID] != tc.want {
+			return fmt.Errorf("CurrentPrices at %s expected %s, got %+v", tc.at, tc.want, current)
+		}
+	}
+	if _, err := r.Price.GetEffective(p.ID, oct.Add(-time.Second)); !errors.Is(err, repository.ErrPriceNotFound) {
+		return fmt.Errorf("expected ErrPriceNotFound before the first price, got %v", err)
+	}
+
+	// Batalkan jadwal: hapus harga November dan buka lagi rentang Oktober
+	if err := r.Price.Delete(scheduled.ID); err != nil {
+		return err
+	}
+	first.EffectiveTo = nil
+	if err := r.Price.Update(first); err != nil {
+		return err
+	}
+	got, err := r.Price.GetEffective(p.ID, nov)
+	if err != nil {
+		return err
+	}
+	if got.ID != first.ID {
+		return fmt.Errorf("expected open ended price %d, got %+v", first.ID, got)
+	}
+	if _, err := r.Price.GetByID(scheduled.ID); !errors.Is(err, repository.ErrPriceNotFound) {
+		return fmt.Errorf("expected ErrPriceNotFound after delete, got %v", err)
+	}
+	return nil
+}
+
+func checkPriceDuplicateStart(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Mie Ayam", Harga: entity.IDR(12000)})
+	if err != nil {
+		return err
+	}
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := r.Price.Create(entity.ProductPrice{ProductID: p.ID, Harga: entity.IDR(12000), EffectiveFrom: from}); err != nil {
+		return err
+	}
+	if _, err := r.Price.Create(entity.ProductPrice{ProductID: p.ID, Harga: entity.IDR(13000), EffectiveFrom: from}); err == nil {
+		return errors.New("Create with duplicate effective_from succeeded")
+	}
+	before := from.Add(-time.Hour)
+	if _, err := r.Price.Create(entity.ProductPrice{ProductID: p.ID, Harga: entity.IDR(11000), EffectiveFrom: from, EffectiveTo: &before}); err == nil {
+		return errors.New("Create with effective_to before effective_from succeeded")
+	}
+	return nil
+}
+
+func checkDeleteProductPrices(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Es Jeruk", Harga: entity.IDR(6000)})
+	if err != nil {
+		return err
+	}
+	price, err := r.Price.Create(entity.ProductPrice{ProductID: p.ID, Harga: entity.IDR(6000), EffectiveFrom: time.Now()})
+	if err != nil {
+		return err
+	}
+	if err := r.Product.Delete(p.ID); err != nil {
+		return err
+	}
+	if _, err := r.Price.GetByID(price.ID); !errors.Is(err, repository.ErrPriceNotFound) {
+		return fmt.Errorf("expected price deleted with its product, got %v", err)
+	}
+	return nil
+}
+
+func checkVariantCRUD(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	created, err := r.Variant.Create(entity.ProductVariant{
+		ProductID: p.ID, SKU: "TEH-JMB", Nama: "Jumbo",
+		Options: map[string]string{"ukuran": "jumbo", "gula": "normal"},
+		Harga:   entity.IDR(8000), Stock: qtyPtr(entity.Qty(10)),
+	})
+	if err != nil {
+		return err
+	}
+	if created.ID != 1 {
+		return fmt.Errorf("expected first variant ID 1, got %d", created.ID)
+	}
+
+	got, err := r.Variant.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.SKU != "TEH-JMB" || got.Harga != entity.IDR(8000) || *got.Stock != entity.Qty(10) ||
+		got.Options["ukuran"] != "jumbo" || got.Options["gula"] != "normal" || len(got.Options) != 2 {
+		return fmt.Errorf("variant did not round trip: %+v", got)
+	}
+
+	got.Nama = "Jumbo Less Sugar"
+	got.Options = map[string]string{"ukuran": "jumbo", "gula": "sedikit"}
+	got.Harga = entity.IDR(8500)
+	updated, err := r.Variant.Update(got.ID, got)
+	if err != nil {
+		return err
+	}
+	if updated.Nama != "Jumbo Less Sugar" || updated.Options["gula"] != "sedikit" || updated.Harga != entity.IDR(8500) {
+		return fmt.Errorf("update not applied: %+v", updated)
+	}
+	if _, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-REG", Nama: "Reguler", Harga: entity.IDR(5000)}); err != nil {
+		return err
+	}
+
+	variants, err := r.Variant.GetByProduct(p.ID)
+	if err != nil {
+		return err
+	}
+	if len(variants) != 2 || variants[0].ID != created.ID || variants[1].Options == nil {
+		return fmt.Errorf("expected 2 variants ordered by ID with options, got %+v", variants)
+	}
+	grouped, err := r.Variant.GetByProducts([]int{p.ID, p.ID + 100})
+	if err != nil {
+		return err
+	}
+	if len(grouped[p.ID]) != 2 || len(grouped[p.ID+100]) != 0 {
+		return fmt.Errorf("GetByProducts grouped wrongly: %+v", grouped)
+	}
+
+	if err := r.Variant.Delete(created.ID); err != nil {
+		return err
+	}
+	if _, err := r.Variant.GetByID(created.ID); !errors.Is(err, repository.ErrVariantNotFound) {
+		return fmt.Errorf("expected ErrVariantNotFound after delete, got %v", err)
+	}
+	if err := r.Variant.Delete(created.ID); !errors.Is(err, repository.ErrVariantNotFound) {
+		return fmt.Errorf("expected ErrVariantNotFound deleting twice, got %v", err)
+	}
+	if _, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID + 100, SKU: "X-1", Nama: "X", Harga: entity.IDR(1)}); err == nil {
+		return errors.New("Create with unknown product succeeded")
+	}
+	return nil
+}
+
+func checkVariantCost(r Repos) error {
+	cost := entity.IDR(2000)
+	p, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000), HargaBeli: &cost})
+	if err != nil {
+		return err
+	}
+	jumboCost := entity.NewMoney(350050, entity.DefaultCurrency)
+	jumbo, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-JMB", Nama: "Jumbo", Harga: entity.IDR(8000), HargaBeli: &jumboCost})
+	if err != nil {
+		return err
+	}
+	reguler, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-REG", Nama: "Reguler", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Variant.GetByID(jumbo.ID)
+	if err != nil {
+		return err
+	}
+	if got.HargaBeli == nil || *got.HargaBeli != jumboCost {
+		return fmt.Errorf("expected harga beli %v, got %v", jumboCost, got.HargaBeli)
+	}
+	// Harga beli produk tidak diwariskan ke varian
+	if got, err = r.Variant.GetByID(reguler.ID); err != nil {
+		return err
+	}
+	if got.HargaBeli != nil {
+		return fmt.Errorf("expected unknown harga beli, got %v", got.HargaBeli)
+	}
+
+	jumbo.HargaBeli = nil
+	updated, err := r.Variant.Update(jumbo.ID, jumbo)
+	if err != nil {
+		return err
+	}
+	if updated.HargaBeli != nil {
+		return fmt.Errorf("expected harga beli cleared, got %v", updated.HargaBeli)
+	}
+	variants, err := r.Variant.GetByProducts([]int{p.ID})
+	if err != nil {
+		return err
+	}
+	for _, v := range variants[p.ID] {
+		if v.HargaBeli != nil {
+			return fmt.Errorf("expected no harga beli, got %+v", v)
+		}
+	}
+
+	negative := entity.IDR(-1)
+	if _, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-X", Nama: "X", Harga: entity.IDR(5000), HargaBeli: &negative}); err == nil {
+		return fmt.Errorf("expected negative harga beli to be rejected")
+	}
+	return nil
+}
+
+func checkVariantConflict(r Repos) error {
+	teh, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(7000)})
+	if err != nil {
+		return err
+	}
+	jumbo, err := r.Variant.Create(entity.ProductVariant{ProductID: teh.ID, SKU: "TEH-JMB", Nama: "Jumbo", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+
+	var conflict *repository.ConflictError
+	_, err = r.Variant.Create(entity.ProductVariant{ProductID: kopi.ID, SKU: "teh-jmb", Nama: "Jumbo", Harga: entity.IDR(9000)})
+	if !errors.As(err, &conflict) {
+		return fmt.Errorf("expected ConflictError for duplicate SKU, got %v", err)
+	}
+	if existing, ok := conflict.Existing.(entity.ProductVariant); !ok || existing.ID != jumbo.ID {
+		return fmt.Errorf("conflict should carry the existing variant, got %+v", conflict.Existing)
+	}
+	if _, err := r.Variant.Create(entity.ProductVariant{ProductID: teh.ID, SKU: "TEH-J2", Nama: "JUMBO", Harga: entity.IDR(8000)}); !errors.As(err, &conflict) {
+		return fmt.Errorf("expected ConflictError for duplicate name in product, got %v", err)
+	}
+
+	// Nama yang sama boleh di produk lain
+	if _, err := r.Variant.Create(entity.ProductVariant{ProductID: kopi.ID, SKU: "KOPI-JMB", Nama: "Jumbo", Harga: entity.IDR(9000)}); err != nil {
+		return fmt.Errorf("same variant name on another product rejected: %v", err)
+	}
+	// Update dengan SKU sendiri tidak bentrok dengan dirinya
+	jumbo.SKU = "Teh-Jmb"
+	if _, err := r.Variant.Update(jumbo.ID, jumbo); err != nil {
+		return fmt.Errorf("update keeping its own SKU rejected: %v", err)
+	}
+	return nil
+}
+
+func checkVariantStock(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	v, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-REG", Nama: "Reguler", Harga: entity.IDR(5000), Stock: qtyPtr(entity.Qty(3))})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-NEG", Nama: "Minus", Harga: entity.IDR(5000), Stock: qtyPtr(entity.Qty(-1))}); err == nil {
+		return errors.New("Create with negative stock succeeded")
+	}
+	// Stok tidak dikirim: default kolom 0
+	empty, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-KSG", Nama: "Kosong", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	if empty.Stock == nil || *empty.Stock != 0 {
+		return fmt.Errorf("expected default stock 0, got %v", empty.Stock)
+	}
+
+	adjusted, err := r.Variant.AdjustStock(v.ID, entity.Qty(-2))
+	if err != nil {
+		return err
+	}
+	if *adjusted.Stock != entity.Qty(1) {
+		return fmt.Errorf("expected stock 1 after -2, got %v", *adjusted.Stock)
+	}
+	// Stok disimpan dalam seperseribu unit, setengah unit bisa dijual
+	if adjusted, err = r.Variant.AdjustStock(v.ID, -500); err != nil {
+		return err
+	}
+	if *adjusted.Stock != entity.Quantity(500) {
+		return fmt.Errorf("expected decimal stock 0.5 after -0.5, got %v", *adjusted.Stock)
+	}
+	if _, err := r.Variant.AdjustStock(v.ID, entity.Qty(-1)); !errors.Is(err, repository.ErrInsufficientStock) {
+		return fmt.Errorf("expected ErrInsufficientStock, got %v", err)
+	}
+	if _, err := r.Variant.AdjustStock(v.ID+100, entity.Qty(1)); !errors.Is(err, repository.ErrVariantNotFound) {
+		return fmt.Errorf("expected ErrVariantNotFound, got %v", err)
+	}
+	got, err := r.Variant.GetByID(v.ID)
+	if err != nil {
+		return err
+	}
+	if *got.Stock != entity.Quantity(500) {
+		return fmt.Errorf("failed adjustment changed stock to %v", *got.Stock)
+	}
+	return nil
+}
+
+func checkDeleteProductVariants(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Es Jeruk", Harga: entity.IDR(6000)})
+	if err != nil {
+		return err
+	}
+	v, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "JRK-REG", Nama: "Reguler", Harga: entity.IDR(6000)})
+	if err != nil {
+		return err
+	}
+	if err := r.Product.Delete(p.ID); err != nil {
+		return err
+	}
+	if _, err := r.Variant.GetByID(v.ID); !errors.Is(err, repository.ErrVariantNotFound) {
+		return fmt.Errorf("expected variant deleted with its product, got %v", err)
+	}
+	return nil
+}
+
+func checkDeleteVariantKeepsSales(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	v, err := r.Variant.Create(entity.ProductVariant{ProductID: p.ID, SKU: "TEH-JMB", Nama: "Jumbo", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+	t, err := r.Transaction.Create(entity.Transaction{
+		TotalAmount: entity.IDR(8000),
+		Details: []entity.TransactionDetail{{
+			ProductID: intPtr(p.ID), VariantID: intPtr(v.ID), NamaProduk: "Es Teh (Jumbo)",
+			Harga: entity.IDR(8000), Quantity: entity.Qty(1), Subtotal: entity.IDR(8000),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	got, err := r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	if got.Details[0].VariantID == nil || *got.Details[0].VariantID != v.ID {
+		return fmt.Errorf("sale line lost its variant: %+v", got.Details[0])
+	}
+
+	if err := r.Variant.Delete(v.ID); err != nil {
+		return err
+	}
+	got, err = r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	if got.Details[0].VariantID != nil || got.Details[0].NamaProduk != "Es Teh (Jumbo)" {
+		return fmt.Errorf("expected sale line kept with variant_id NULL, got %+v", got.Details[0])
+	}
+
+	if _, err := r.Transaction.Create(entity.Transaction{
+		TotalAmount: entity.IDR(8000),
+		Details: []entity.TransactionDetail{{
+			ProductID: intPtr(p.ID), VariantID: intPtr(v.ID), NamaProduk: "Es Teh (Jumbo)",
+			Harga: entity.IDR(8000), Quantity: entity.Qty(1), Subtotal: entity.IDR(8000),
+		}},
+	}); err == nil {
+		return errors.New("Create with unknown variant succeeded")
+	}
+	return nil
+}
+
+func checkModifierGroupCRUD(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi Hitam", Harga: entity.IDR(8000), CategoryID: intPtr(minuman.ID)})
+	if err != nil {
+		return err
+	}
+
+	ekstra, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra", ProductID: intPtr(kopi.ID), MaxSelect: 3})
+	if err != nil {
+		return err
+	}
+	if ekstra.ID != 1 || ekstra.Required || ekstra.Modifiers == nil {
+		return fmt.Errorf("unexpected created group: %+v", ekstra)
+	}
+	gula, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Gula", CategoryID: intPtr(minuman.ID), MinSelect: 1, MaxSelect: 1})
+	if err != nil {
+		return err
+	}
+	if !gula.Required {
+		return fmt.Errorf("group with min_select 1 should be required: %+v", gula)
+	}
+
+	shot, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: ekstra.ID, Nama: "Extra Shot", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: gula.ID, Nama: "Less Sugar"}); err != nil {
+		return err
+	}
+	if _, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: ekstra.ID, Nama: "Oat Milk", Harga: entity.IDR(7000)}); err != nil {
+		return err
+	}
+
+	got, err := r.Modifier.GetGroupByID(ekstra.ID)
+	if err != nil {
+		return err
+	}
+	if got.Nama != "Ekstra" || got.ProductID == nil || *got.ProductID != kopi.ID || got.CategoryID != nil || got.MaxSelect != 3 ||
+		len(got.Modifiers) != 2 || got.Modifiers[0].ID != shot.ID || got.Modifiers[0].Harga != entity.IDR(5000) {
+		return fmt.Errorf("group did not round trip: %+v", got)
+	}
+
+	groups, err := r.Modifier.GetGroupsFor(kopi.ID, []int{minuman.ID})
+	if err != nil {
+		return err
+	}
+	if len(groups) != 2 || groups[0].ID != ekstra.ID || groups[1].ID != gula.ID || len(groups[1].Modifiers) != 1 {
+		return fmt.Errorf("expected product and category groups ordered by ID, got %+v", groups)
+	}
+	if groups, err := r.Modifier.GetGroupsFor(kopi.ID, nil); err != nil || len(groups) != 1 {
+		return fmt.Errorf("expected only the product group without categories, got %+v (%v)", groups, err)
+	}
+
+	got.Nama = "Tambahan"
+	got.MinSelect, got.MaxSelect = 1, 2
+	updated, err := r.Modifier.UpdateGroup(got.ID, got)
+	if err != nil {
+		return err
+	}
+	if updated.Nama != "Tambahan" || !updated.Required || updated.MaxSelect != 2 || len(updated.Modifiers) != 2 {
+		return fmt.Errorf("group update not applied: %+v", updated)
+	}
+	shot.Harga = entity.IDR(6000)
+	if shot, err = r.Modifier.UpdateModifier(shot.ID, shot); err != nil || shot.Harga != entity.IDR(6000) {
+		return fmt.Errorf("modifier update not applied: %+v (%v)", shot, err)
+	}
+
+	if err := r.Modifier.DeleteModifier(shot.ID); err != nil {
+		return err
+	}
+	if _, err := r.Modifier.GetModifierByID(shot.ID); !errors.Is(err, repository.ErrModifierNotFound) {
+		return fmt.Errorf("expected ErrModifierNotFound after delete, got %v", err)
+	}
+	if err := r.Modifier.DeleteGroup(gula.ID); err != nil {
+		return err
+	}
+	if _, err := r.Modifier.GetGroupByID(gula.ID); !errors.Is(err, repository.ErrModifierGroupNotFound) {
+		return fmt.Errorf("expected ErrModifierGroupNotFound after delete, got %v", err)
+	}
+	all, err := r.Modifier.GetAllGroups()
+	if err != nil {
+		return err
+	}
+	if len(all) != 1 || all[0].ID != ekstra.ID || len(all[0].Modifiers) != 1 {
+		return fmt.Errorf("expected one group with one modifier left, got %+v", all)
+	}
+	return nil
+}
+
+func checkModifierGroupTarget(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra"}); err == nil {
+		return errors.New("CreateGroup without product or category succeeded")
+	}
+	if _, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra", ProductID: intPtr(p.ID), CategoryID: intPtr(c.ID)}); err == nil {
+		return errors.New("CreateGroup with both product and category succeeded")
+	}
+	if _, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra", ProductID: intPtr(p.ID), MinSelect: 2, MaxSelect: 1}); err == nil {
+		return errors.New("CreateGroup with max_select below min_select succeeded")
+	}
+	if _, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra", CategoryID: intPtr(c.ID + 100)}); err == nil {
+		return errors.New("CreateGroup with unknown category succeeded")
+	}
+	if _, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: 100, Nama: "Extra Shot"}); err == nil {
+		return errors.New("CreateModifier with unknown group succeeded")
+	}
+	return nil
+}
+
+func checkModifierConflict(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+	ekstra, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra", ProductID: intPtr(p.ID)})
+	if err != nil {
+		return err
+	}
+	susu, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Susu", ProductID: intPtr(p.ID)})
+	if err != nil {
+		return err
+	}
+	shot, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: ekstra.ID, Nama: "Extra Shot", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+
+	var conflict *repository.ConflictError
+	_, err = r.Modifier.CreateModifier(entity.Modifier{GroupID: ekstra.ID, Nama: "EXTRA SHOT"})
+	if !errors.As(err, &conflict) {
+		return fmt.Errorf("expected ConflictError for duplicate modifier, got %v", err)
+	}
+	if existing, ok := conflict.Existing.(entity.Modifier); !ok || existing.ID != shot.ID {
+		return fmt.Errorf("conflict should carry the existing modifier, got %+v", conflict.Existing)
+	}
+	if _, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: susu.ID, Nama: "Extra Shot"}); err != nil {
+		return fmt.Errorf("same modifier name in another group rejected: %v", err)
+	}
+	shot.Nama = "extra shot"
+	if _, err := r.Modifier.UpdateModifier(shot.ID, shot); err != nil {
+		return fmt.Errorf("update keeping its own name rejected: %v", err)
+	}
+	return nil
+}
+
+func checkDeleteModifierGroupCascade(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+	byProduct, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra", ProductID: intPtr(p.ID)})
+	if err != nil {
+		return err
+	}
+	shot, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: byProduct.ID, Nama: "Extra Shot", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	byCategory, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Gula", CategoryID: intPtr(c.ID)})
+	if err != nil {
+		return err
+	}
+
+	if err := r.Product.Delete(p.ID); err != nil {
+		return err
+	}
+	if _, err := r.Modifier.GetGroupByID(byProduct.ID); !errors.Is(err, repository.ErrModifierGroupNotFound) {
+		return fmt.Errorf("expected group deleted with its product, got %v", err)
+	}
+	if _, err := r.Modifier.GetModifierByID(shot.ID); !errors.Is(err, repository.ErrModifierNotFound) {
+		return fmt.Errorf("expected modifier deleted with its group, got %v", err)
+	}
+	if err := r.Category.Delete(c.ID); err != nil {
+		return err
+	}
+	if _, err := r.Modifier.GetGroupByID(byCategory.ID); !errors.Is(err, repository.ErrModifierGroupNotFound) {
+		return fmt.Errorf("expected group deleted with its category, got %v", err)
+	}
+	return nil
+}
+
+func checkDeleteModifierKeepsSales(r Repos) error {
+	p, err := r.Product.Create(entity.Product{Nama: "Kopi Hitam", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+	g, err := r.Modifier.CreateGroup(entity.ModifierGroup{Nama: "Ekstra", ProductID: intPtr(p.ID)})
+	if err != nil {
+		return err
+	}
+	shot, err := r.Modifier.CreateModifier(entity.Modifier{GroupID: g.ID, Nama: "Extra Shot", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+
+	t, err := r.Transaction.Create(entity.Transaction{
+		TotalAmount: entity.IDR(26000),
+		Details: []entity.TransactionDetail{{
+			ProductID: intPtr(p.ID), NamaProduk: p.Nama, Harga: entity.IDR(8000), Quantity: entity.Qty(2), Subtotal: entity.IDR(26000),
+			Modifiers: []entity.TransactionDetailModifier{
+				{ModifierID: intPtr(shot.ID), GroupNama: "Ekstra", Nama: "Extra Shot", Harga: entity.IDR(5000)},
+				{GroupNama: "Gula", Nama: "Less Sugar", Harga: entity.IDR(0)},
+			},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	got, err := r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	mods := got.Details[0].Modifiers
+	if len(mods) != 2 || mods[0].ModifierID == nil || *mods[0].ModifierID != shot.ID || mods[0].Harga != entity.IDR(5000) ||
+		mods[1].Nama != "Less Sugar" || mods[1].GroupNama != "Gula" || mods[1].ModifierID != nil {
+		return fmt.Errorf("sale line modifiers did not round trip: %+v", mods)
+	}
+
+	if err := r.Modifier.DeleteModifier(shot.ID); err != nil {
+		return err
+	}
+	got, err = r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	mods = got.Details[0].Modifiers
+	if len(mods) != 2 || mods[0].ModifierID != nil || mods[0].Nama != "Extra Shot" {
+		return fmt.Errorf("expected modifier copy kept with modifier_id NULL, got %+v", mods)
+	}
+	return nil
+}
+
+func checkProductStock(r Repos) error {
+	stock := entity.Qty(5)
+	tracked, err := r.Product.Create(entity.Product{Nama: "Air Mineral", Harga: entity.IDR(3000), Stock: &stock})
+	if err != nil {
+		return err
+	}
+	untracked, err := r.Product.Create(entity.Product{Nama: "Nasi Goreng", Harga: entity.IDR(15000)})
+	if err != nil {
+		return err
+	}
+	if tracked.Type != entity.ProductTypeSingle || untracked.Type != entity.ProductTypeSingle {
+		return fmt.Errorf("expected default type single, got %q and %q", tracked.Type, untracked.Type)
+	}
+
+	got, err := r.Product.GetByID(tracked.ID)
+	if err != nil {
+		return err
+	}
+	if got.Stock == nil || *got.Stock != entity.Qty(5) || got.Unit != entity.DefaultUnit {
+		return fmt.Errorf("stock did not round trip: %v", got.Stock)
+	}
+	if got, err = r.Product.GetByID(untracked.ID); err != nil || got.Stock != nil {
+		return fmt.Errorf("untracked stock should stay nil, got %v (%v)", got.Stock, err)
+	}
+
+	adjusted, err := r.Product.AdjustStock(tracked.ID, -entity.Qty(3))
+	if err != nil {
+		return err
+	}
+	if adjusted.Stock == nil || *adjusted.Stock != entity.Qty(2) {
+		return fmt.Errorf("expected stock 2 after -3, got %v", adjusted.Stock)
+	}
+	if adjusted, err = r.Product.AdjustStock(tracked.ID, -entity.Quantity(1500)); err != nil {
+		return err
+	}
+	if *adjusted.Stock != entity.Quantity(500) {
+		return fmt.Errorf("expected decimal stock 0.5 after -1.5, got %v", adjusted.Stock)
+	}
+	if _, err := r.Product.AdjustStock(tracked.ID, -entity.Qty(1)); !errors.Is(err, repository.ErrInsufficientStock) {
+		return fmt.Errorf("expected ErrInsufficientStock, got %v", err)
+	}
+	if _, err := r.Product.AdjustStock(untracked.ID, entity.Qty(1)); !errors.Is(err, repository.ErrStockNotTracked) {
+		return fmt.Errorf("expected ErrStockNotTracked, got %v", err)
+	}
+	if _, err := r.Product.AdjustStock(untracked.ID+100, entity.Qty(1)); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+
+	negative := entity.Qty(-1)
+	if _, err := r.Product.Create(entity.Product{Nama: "Minus", Harga: entity.IDR(1), Stock: &negative}); err == nil {
+		return errors.New("Create with negative stock succeeded")
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "Aneh", Type: "combo", Harga: entity.IDR(1)}); err == nil {
+		return errors.New("Create with unknown type succeeded")
+	}
+	return nil
+}
+
+func checkBundleComponents(r Repos) error {
+	nasi, err := r.Product.Create(entity.Product{Nama: "Nasi Goreng", Harga: entity.IDR(15000)})
+	if err != nil {
+		return err
+	}
+	teh, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	jumbo, err := r.Variant.Create(entity.ProductVariant{ProductID: teh.ID, SKU: "TEH-JMB", Nama: "Jumbo", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+	paket, err := r.Product.Create(entity.Product{Nama: "Paket Hemat", Type: entity.ProductTypeBundle, Harga: entity.IDR(20000)})
+	if err != nil {
+		return err
+	}
+	if !paket.IsBundle() {
+		return fmt.Errorf("expected bundle type, got %q", paket.Type)
+	}
+
+	err = r.Bundle.SetComponents(paket.ID, []entity.BundleComponent{
+		{ProductID: teh.ID, VariantID: &jumbo.ID, Quantity: 2},
+		{ProductID: nasi.ID, Quantity: 1},
+	})
+	if err != nil {
+		return err
+	}
+	components, err := r.Bundle.GetComponents(paket.ID)
+	if err != nil {
+		return err
+	}
+	if len(components) != 2 || components[0].Nama != "Es Teh (Jumbo)" || components[0].Quantity != 2 ||
+		components[0].VariantID == nil || *components[0].VariantID != jumbo.ID ||
+		components[1].Nama != "Nasi Goreng" || components[1].VariantID != nil {
+		return fmt.Errorf("components did not round trip in input order: %+v", components)
+	}
+
+	grouped, err := r.Bundle.GetComponentsByBundles([]int{paket.ID, nasi.ID})
+	if err != nil {
+		return err
+	}
+	if len(grouped[paket.ID]) != 2 || len(grouped[nasi.ID]) != 0 {
+		return fmt.Errorf("GetComponentsByBundles grouped wrongly: %+v", grouped)
+	}
+
+	for _, c := range []struct {
+		productID int
+		variantID *int
+		want      bool
+	}{
+		{nasi.ID, nil, true},
+		{teh.ID, nil, true},
+		{teh.ID, &jumbo.ID, true},
+		{paket.ID, nil, false},
+	} {
+		got, err := r.Bundle.IsComponent(c.productID, c.variantID)
+		if err != nil {
+			return err
+		}
+		if got != c.want {
+			return fmt.Errorf("IsComponent(%d, %v) = %v, want %v", c.productID, c.variantID, got, c.want)
+		}
+	}
+
+	// SetComponents mengganti seluruh isi paket
+	if err := r.Bundle.SetComponents(paket.ID, []entity.BundleComponent{{ProductID: nasi.ID, Quantity: 3}}); err != nil {
+		return err
+	}
+	if components, err = r.Bundle.GetComponents(paket.ID); err != nil {
+		return err
+	}
+	if len(components) != 1 || components[0].Quantity != 3 {
+		return fmt.Errorf("SetComponents did not replace components: %+v", components)
+	}
+
+	if err := r.Bundle.SetComponents(paket.ID, []entity.BundleComponent{{ProductID: nasi.ID, Quantity: 0}}); err == nil {
+		return errors.New("component with zero quantity accepted")
+	}
+	if err := r.Bundle.SetComponents(paket.ID, []entity.BundleComponent{{ProductID: paket.ID, Quantity: 1}}); err == nil {
+		return errors.New("bundle containing itself accepted")
+	}
+	if err := r.Bundle.SetComponents(paket.ID, []entity.BundleComponent{
+		{ProductID: nasi.ID, Quantity: 1}, {ProductID: nasi.ID, Quantity: 2},
+	}); err == nil {
+		return errors.New("duplicate component accepted")
+	}
+	return nil
+}
+
+func checkBundleComponentRestrict(r Repos) error {
+	nasi, err := r.Product.Create(entity.Product{Nama: "Nasi Goreng", Harga: entity.IDR(15000)})
+	if err != nil {
+		return err
+	}
+	teh, err := r.Product.Create(entity.Product{Nama: "Es Teh", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	jumbo, err := r.Variant.Create(entity.ProductVariant{ProductID: teh.ID, SKU: "TEH-JMB", Nama: "Jumbo", Harga: entity.IDR(8000)})
+	if err != nil {
+		return err
+	}
+	paket, err := r.Product.Create(entity.Product{Nama: "Paket Hemat", Type: entity.ProductTypeBundle, Harga: entity.IDR(20000)})
+	if err != nil {
+		return err
+	}
+	err = r.Bundle.SetComponents(paket.ID, []entity.BundleComponent{
+		{ProductID: nasi.ID, Quantity: 1},
+		{ProductID: teh.ID, VariantID: &jumbo.ID, Quantity: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := r.Product.Delete(nasi.ID); err == nil {
+		return errors.New("deleting a component product succeeded")
+	}
+	if err := r.Variant.Delete(jumbo.ID); err == nil {
+		return errors.New("deleting a component variant succeeded")
+	}
+	if _, err := r.Product.GetByID(nasi.ID); err != nil {
+		return fmt.Errorf("failed delete removed the component product: %v", err)
+	}
+
+	// Menghapus bundle ikut menghapus isi paketnya sehingga komponen bebas dihapus
+	if err := r.Product.Delete(paket.ID); err != nil {
+		return err
+	}
+	if inBundle, err := r.Bundle.IsComponent(nasi.ID, nil); err != nil || inBundle {
+		return fmt.Errorf("expected components deleted with their bundle, got %v (%v)", inBundle, err)
+	}
+	if err := r.Variant.Delete(jumbo.ID); err != nil {
+		return err
+	}
+	return r.Product.Delete(nasi.ID)
+}
+
+func checkUnitCRUD(r Repos) error {
+	units, err := r.Unit.GetAll()
+	if err != nil {
+		return err
+	}
+	codes := make(map[string]entity.Unit, len(units))
+	for _, u := range units {
+		codes[u.Code] = u
+	}
+	if _, ok := codes["pcs"]; !ok || !codes["kg"].AllowDecimal || codes["dus"].AllowDecimal {
+		return fmt.Errorf("expected default units pcs, kg (decimal) and dus, got %+v", units)
+	}
+	for i := 1; i < len(units); i++ {
+		if units[i-1].Code >= units[i].Code {
+			return fmt.Errorf("units not ordered by code: %+v", units)
+		}
+	}
+
+	created, err := r.Unit.Create(entity.Unit{Code: "rim", Nama: "Rim"})
+	if err != nil {
+		return err
+	}
+	if got, err := r.Unit.GetByCode("rim"); err != nil || got != created {
+		return fmt.Errorf("unit did not round trip: %+v (%v)", got, err)
+	}
+	var conflict *repository.ConflictError
+	if _, err := r.Unit.Create(entity.Unit{Code: "rim", Nama: "Rim Lagi"}); !errors.As(err, &conflict) {
+		return fmt.Errorf("expected ConflictError for duplicate code, got %v", err)
+	}
+
+	updated, err := r.Unit.Update("rim", entity.Unit{Nama: "Rim (500 lembar)", AllowDecimal: true})
+	if err != nil {
+		return err
+	}
+	if updated.Code != "rim" || !updated.AllowDecimal {
+		return fmt.Errorf("update not applied: %+v", updated)
+	}
+	if _, err := r.Unit.Update("zzz", entity.Unit{Nama: "X"}); !errors.Is(err, repository.ErrUnitNotFound) {
+		return fmt.Errorf("expected ErrUnitNotFound on update, got %v", err)
+	}
+
+	if err := r.Unit.Delete("rim"); err != nil {
+		return err
+	}
+	if _, err := r.Unit.GetByCode("rim"); !errors.Is(err, repository.ErrUnitNotFound) {
+		return fmt.Errorf("expected ErrUnitNotFound after delete, got %v", err)
+	}
+	if err := r.Unit.Delete("rim"); !errors.Is(err, repository.ErrUnitNotFound) {
+		return fmt.Errorf("expected ErrUnitNotFound deleting twice, got %v", err)
+	}
+	return nil
+}
+
+func checkUnitInUse(r Repos) error {
+	for _, code := range []string{"bal", "krat", "ikat"} {
+		if _, err := r.Unit.Create(entity.Unit{Code: code, Nama: code}); err != nil {
+			return err
+		}
+	}
+	p, err := r.Product.Create(entity.Product{Nama: "Beras", Harga: entity.IDR(12000), Unit: "bal", PurchaseUnit: "krat"})
+	if err != nil {
+		return err
+	}
+	if err := r.Unit.SetConversions(p.ID, []entity.UnitConversion{{Unit: "ikat", Factor: entity.Qty(5)}}); err != nil {
+		return err
+	}
+
+	for _, code := range []string{"bal", "krat", "ikat"} {
+		if err := r.Unit.Delete(code); !errors.Is(err, repository.ErrUnitInUse) {
+			return fmt.Errorf("expected ErrUnitInUse deleting %s, got %v", code, err)
+		}
+	}
+	if err := r.Product.Delete(p.ID); err != nil {
+		return err
+	}
+	for _, code := range []string{"bal", "krat", "ikat"} {
+		if err := r.Unit.Delete(code); err != nil {
+			return fmt.Errorf("unit %s still in use after its product was deleted: %v", code, err)
+		}
+	}
+	return nil
+}
+
+func checkProductUnits(r Repos) error {
+	air, err := r.Product.Create(entity.Product{Nama: "Air Mineral", Harga: entity.IDR(3000), PurchaseUnit: "dus"})
+	if err != nil {
+		return err
+	}
+	gula, err := r.Product.Create(entity.Product{Nama: "Gula", Harga: entity.IDR(16000), Unit: "kg"})
+	if err != nil {
+		return err
+	}
+
+	err = r.Unit.SetConversions(air.ID, []entity.UnitConversion{
+		{Unit: "dus", Factor: entity.Qty(24)},
+		{Unit: "pak", Factor: entity.Qty(6)},
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.Unit.SetConversions(gula.ID, []entity.UnitConversion{{Unit: "g", Factor: entity.Quantity(1)}}); err != nil {
+		return err
+	}
+
+	got, err := r.Product.GetByID(air.ID)
+	if err != nil {
+		return err
+	}
+	if got.Unit != "pcs" || got.PurchaseUnit != "dus" {
+		return fmt.Errorf("product units did not round trip: unit %q purchase %q", got.Unit, got.PurchaseUnit)
+	}
+	conversions, err := r.Unit.GetConversions(air.ID)
+	if err != nil {
+		return err
+	}
+	if len(conversions) != 2 || conversions[0] != (entity.UnitConversion{Unit: "pak", Factor: entity.Qty(6)}) ||
+		conversions[1] != (entity.UnitConversion{Unit: "dus", Factor: entity.Qty(24)}) {
+		return fmt.Errorf("conversions not ordered by factor: %+v", conversions)
+	}
+	grouped, err := r.Unit.GetConversionsByProducts([]int{air.ID, gula.ID, gula.ID + 100})
+	if err != nil {
+		return err
+	}
+	if len(grouped[air.ID]) != 2 || len(grouped[gula.ID]) != 1 || grouped[gula.ID][0].Factor != entity.Quantity(1) ||
+		len(grouped[gula.ID+100]) != 0 {
+		return fmt.Errorf("GetConversionsByProducts grouped wrongly: %+v", grouped)
+	}
+
+	if _, err := r.Product.Create(entity.Product{Nama: "Aneh", Harga: entity.IDR(1), Unit: "zzz"}); err == nil {
+		return errors.New("Create with unknown unit succeeded")
+	}
+	if err := r.Unit.SetConversions(air.ID, []entity.UnitConversion{{Unit: "zzz", Factor: entity.Qty(2)}}); err == nil {
+		return errors.New("conversion with unknown unit accepted")
+	}
+	if err := r.Unit.SetConversions(air.ID, []entity.UnitConversion{{Unit: "dus", Factor: 0}}); err == nil {
+		return errors.New("conversion with zero factor accepted")
+	}
+
+	if err := r.Product.Delete(air.ID); err != nil {
+		return err
+	}
+	if conversions, err = r.Unit.GetConversions(air.ID); err != nil || len(conversions) != 0 {
+		return fmt.Errorf("expected conversions deleted with their product, got %+v (%v)", conversions, err)
+	}
+	return nil
+}
+
+func checkDecimalSaleLines(r Repos) error {
+	cost := entity.IDR(13333)
+	gula, err := r.Product.Create(entity.Product{Nama: "Gula", Harga: entity.IDR(16000), HargaBeli: &cost, Unit: "kg"})
+	if err != nil {
+		return err
+	}
+	air, err := r.Product.Create(entity.Product{Nama: "Air Mineral", Harga: entity.IDR(3000)})
+	if err != nil {
+		return err
+	}
+
+	// 1,5 kg gula dan 2 dus air (48 pcs), harga beli per satuan jual
+	hargaDus := entity.IDR(70000)
+	t, err := r.Transaction.Create(entity.Transaction{
+		CreatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		TotalAmount: entity.IDR(164000),
+		Details: []entity.TransactionDetail{
+			{
+				ProductID: intPtr(gula.ID), NamaProduk: gula.Nama, Harga: gula.Harga, HargaBeli: &cost,
+				Quantity: entity.Quantity(1500), Unit: "kg", BaseQuantity: entity.Quantity(1500), Subtotal: entity.IDR(24000),
+			},
+			{
+				ProductID: intPtr(air.ID), NamaProduk: air.Nama, Harga: hargaDus,
+				Quantity: entity.Qty(2), Unit: "dus", BaseQuantity: entity.Qty(48), Subtotal: entity.IDR(140000),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	got, err := r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	gulaLine, airLine := got.Details[0], got.Details[1]
+	if gulaLine.Quantity != entity.Quantity(1500) || gulaLine.Unit != "kg" || gulaLine.BaseQuantity != entity.Quantity(1500) {
+		return fmt.Errorf("decimal sale line did not round trip: %+v", gulaLine)
+	}
+	if airLine.Quantity != entity.Qty(2) || airLine.Unit != "dus" || airLine.BaseQuantity != entity.Qty(48) {
+		return fmt.Errorf("converted sale line did not round trip: %+v", airLine)
+	}
+
+	// Satuan dan quantity dasar kosong berarti pcs dan sama dengan quantity
+	plain, err := sale(r, time.Now(), saleLine{air, 3})
+	if err != nil {
+		return err
+	}
+	if d := plain.Details[0]; d.Unit != entity.DefaultUnit || d.BaseQuantity != entity.Qty(3) {
+		return fmt.Errorf("expected default unit and base quantity, got %+v", d)
+	}
+
+	report, err := r.Transaction.MarginReport(entity.MarginFilter{GroupBy: entity.MarginByProduct})
+	if err != nil {
+		return err
+	}
+	rows := make(map[string]entity.MarginRow, len(report))
+	for _, row := range report {
+		rows[row.Name] = row
+	}
+	// Rp 13.333 × 1,5 kg = Rp 19.999,50
+	if row := rows["Gula"]; row.Quantity != entity.Quantity(1500) || row.Cost != entity.NewMoney(1999950, "IDR") {
+		return fmt.Errorf("unexpected gula margin row %+v", row)
+	}
+	if row := rows["Air Mineral"]; row.Quantity != entity.Qty(51) {
+		return fmt.Errorf("expected 51 pcs of air sold in base units, got %+v", row)
+	}
+	return nil
+}
+
+func checkProductImage(r Repos) error {
+	created, err := r.Product.Create(entity.Product{Nama: "Es Jeruk", Harga: entity.IDR(6000)})
+	if err != nil {
+		return err
+	}
+	if created.Image != nil {
+		return fmt.Errorf("new product should have no image, got %+v", created.Image)
+	}
+
+	image := entity.ProductImage{Key: "products/1/ab12.jpg", ThumbnailKey: "products/1/ab12-thumb.jpg"}
+	if err := r.Product.SetImage(created.ID, &image); err != nil {
+		return err
+	}
+	got, err := r.Product.GetByID(created.ID)
+	if err != nil {
+		return err
+	}
+	if got.Image == nil || *got.Image != image {
+		return fmt.Errorf("image did not round trip: %+v", got.Image)
+	}
+	if listed, err := r.Product.GetAllWithCategory(entity.ProductFilter{}); err != nil || len(listed) != 1 || listed[0].Image == nil {
+		return fmt.Errorf("listing should include the image, got %+v (%v)", listed, err)
+	}
+
+	got.Nama = "Es Jeruk Peras"
+	if _, err := r.Product.Update(created.ID, got); err != nil {
+		return err
+	}
+	if got, err = r.Product.GetByID(created.ID); err != nil || got.Image == nil || *got.Image != image {
+		return fmt.Errorf("update should keep the image, got %+v (%v)", got.Image, err)
+	}
+
+	if err := r.Product.SetImage(created.ID, nil); err != nil {
+		return err
+	}
+	if got, err = r.Product.GetByID(created.ID); err != nil || got.Image != nil {
+		return fmt.Errorf("image should be cleared, got %+v (%v)", got.Image, err)
+	}
+	if err := r.Product.SetImage(created.ID+100, &image); !errors.Is(err, repository.ErrProductNotFound) {
+		return fmt.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkPromotionCRUD(r Repos) error {
+	snack, err := r.Category.Create(entity.Category{Name: "Snack"})
+	if err != nil {
+		return err
+	}
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	amount, minSubtotal := entity.IDR(5000), entity.IDR(50000)
+	promotions := []entity.Promotion{
+		{Nama: "Beli 2 Gratis 1", Type: entity.PromotionBOGO, Scope: entity.PromotionScopeProduct, ProductID: intPtr(kopi.ID), BuyQuantity: 2, GetQuantity: 1},
+		{Nama: "Snack Weekend", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCategory, CategoryID: intPtr(snack.ID), Percent: 1000, Days: []int{0, 6}, Priority: 10, Stackable: true},
+		{Nama: "Potongan 5rb", Type: entity.PromotionThreshold, Scope: entity.PromotionScopeCart, Amount: &amount, MinSubtotal: &minSubtotal, StartsAt: &start, EndsAt: &end},
+	}
+	for i := range promotions {
+		created, err := r.Promotion.Create(promotions[i])
+		if err != nil {
+			return err
+		}
+		if created.ID != i+1 {
+			return fmt.Errorf("expected promotion ID %d, got %d", i+1, created.ID)
+		}
+		promotions[i].ID = created.ID
+	}
+
+	got, err := r.Promotion.GetByID(promotions[1].ID)
+	if err != nil {
+		return err
+	}
+	if got.Percent != 1000 || !reflect.DeepEqual(got.Days, []int{0, 6}) || got.Priority != 10 || !got.Stackable || *got.CategoryID != snack.ID {
+		return fmt.Errorf("percentage promotion did not round trip: %+v", got)
+	}
+	got, err = r.Promotion.GetByID(promotions[2].ID)
+	if err != nil {
+		return err
+	}
+	if *got.Amount != amount || *got.MinSubtotal != minSubtotal || !got.StartsAt.Equal(start) || !got.EndsAt.Equal(end) || got.Days != nil {
+		return fmt.Errorf("threshold promotion did not round trip: %+v", got)
+	}
+
+	// Di luar rentang tanggal hanya dua promosi, priority tertinggi dulu
+	active, err := r.Promotion.GetActive(end)
+	if err != nil {
+		return err
+	}
+	if len(active) != 2 || active[0].ID != promotions[1].ID || active[1].ID != promotions[0].ID {
+		return fmt.Errorf("unexpected active promotions after ends_at: %+v", active)
+	}
+	if active, err = r.Promotion.GetActive(start); err != nil || len(active) != 3 {
+		return fmt.Errorf("expected 3 active promotions at starts_at, got %+v (%v)", active, err)
+	}
+
+	got.Nama = "Potongan 10rb"
+	ten := entity.IDR(10000)
+	got.Amount, got.EndsAt = &ten, nil
+	if _, err := r.Promotion.Update(got.ID, got); err != nil {
+		return err
+	}
+	if got, err = r.Promotion.GetByID(got.ID); err != nil || got.Nama != "Potongan 10rb" || *got.Amount != ten || got.EndsAt != nil {
+		return fmt.Errorf("update did not round trip: %+v (%v)", got, err)
+	}
+
+	// Scope product tanpa produk ditolak oleh constraint
+	invalid := entity.Promotion{Nama: "Rusak", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeProduct, Percent: 500}
+	if _, err := r.Promotion.Create(invalid); err == nil {
+		return fmt.Errorf("expected constraint error for product promotion without product")
+	}
+
+	if err := r.Promotion.Delete(promotions[0].ID); err != nil {
+		return err
+	}
+	if _, err := r.Promotion.GetByID(promotions[0].ID); !errors.Is(err, repository.ErrPromotionNotFound) {
+		return fmt.Errorf("expected ErrPromotionNotFound after delete, got %v", err)
+	}
+	if err := r.Promotion.Delete(promotions[0].ID); !errors.Is(err, repository.ErrPromotionNotFound) {
+		return fmt.Errorf("expected ErrPromotionNotFound deleting twice, got %v", err)
+	}
+	if _, err := r.Promotion.Update(promotions[0].ID, promotions[0]); !errors.Is(err, repository.ErrPromotionNotFound) {
+		return fmt.Errorf("expected ErrPromotionNotFound updating deleted promotion, got %v", err)
+	}
+	if all, err := r.Promotion.GetAll(); err != nil || len(all) != 2 {
+		return fmt.Errorf("expected 2 promotions, got %+v (%v)", all, err)
+	}
+	return nil
+}
+
+func checkDeletePromotionCascade(r Repos) error {
+	snack, err := r.Category.Create(entity.Category{Name: "Snack"})
+	if err != nil {
+		return err
+	}
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Promotion.Create(entity.Promotion{Nama: "Kopi 10%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeProduct, ProductID: intPtr(kopi.ID), Percent: 1000}); err != nil {
+		return err
+	}
+	if _, err := r.Promotion.Create(entity.Promotion{Nama: "Snack 5%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCategory, CategoryID: intPtr(snack.ID), Percent: 500}); err != nil {
+		return err
+	}
+	cart, err := r.Promotion.Create(entity.Promotion{Nama: "Semua 1%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCart, Percent: 100})
+	if err != nil {
+		return err
+	}
+
+	if err := r.Product.Delete(kopi.ID); err != nil {
+		return err
+	}
+	if err := r.Category.Delete(snack.ID); err != nil {
+		return err
+	}
+	all, err := r.Promotion.GetAll()
+	if err != nil {
+		return err
+	}
+	if len(all) != 1 || all[0].ID != cart.ID {
+		return fmt.Errorf("expected only the cart promotion to remain, got %+v", all)
+	}
+	return nil
+}
+
+func checkTransactionDiscounts(r Repos) error {
+	cost := entity.IDR(3000)
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(5000), HargaBeli: &cost})
+	if err != nil {
+		return err
+	}
+	promo, err := r.Promotion.Create(entity.Promotion{Nama: "Beli 2 Gratis 1", Type: entity.PromotionBOGO, Scope: entity.PromotionScopeProduct, ProductID: intPtr(kopi.ID), BuyQuantity: 2, GetQuantity: 1})
+	if err != nil {
+		return err
+	}
+
+	t, err := r.Transaction.Create(entity.Transaction{
+		CreatedAt:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Subtotal:       entity.IDR(15000),
+		DiscountAmount: entity.IDR(5000),
+		TotalAmount:    entity.IDR(10000),
+		Details: []entity.TransactionDetail{{
+			ProductID: intPtr(kopi.ID), NamaProduk: kopi.Nama, Harga: kopi.Harga, HargaBeli: &cost,
+			Quantity: entity.Qty(3), Subtotal: entity.IDR(15000), Discount: entity.IDR(5000),
+		}},
+		Discounts: []entity.TransactionDiscount{{PromotionID: intPtr(promo.ID), Nama: promo.Nama, Amount: entity.IDR(5000)}},
+	})
+	if err != nil {
+		return err
+	}
+	got, err := r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	if got.Subtotal != entity.IDR(15000) || got.DiscountAmount != entity.IDR(5000) || got.TotalAmount != entity.IDR(10000) {
+		return fmt.Errorf("transaction totals did not round trip: %+v", got)
+	}
+	if got.Details[0].Discount != entity.IDR(5000) {
+		return fmt.Errorf("line discount did not round trip: %+v", got.Details[0])
+	}
+	if len(got.Discounts) != 1 || *got.Discounts[0].PromotionID != promo.ID || got.Discounts[0].Amount != entity.IDR(5000) {
+		return fmt.Errorf("discounts did not round trip: %+v", got.Discounts)
+	}
+
+	// Transaksi tanpa diskon memakai total sebagai subtotal
+	plain, err := sale(r, time.Now(), saleLine{kopi, 1})
+	if err != nil {
+		return err
+	}
+	if got, err := r.Transaction.GetByID(plain.ID); err != nil || got.Subtotal != got.TotalAmount || !got.DiscountAmount.IsZero() || got.Discounts != nil {
+		return fmt.Errorf("expected subtotal equal to total without discount, got %+v (%v)", got, err)
+	}
+
+	// Pendapatan di laporan margin bersih setelah diskon
+	report, err := r.Transaction.MarginReport(entity.MarginFilter{GroupBy: entity.MarginByProduct})
+	if err != nil {
+		return err
+	}
+	if len(report) != 1 || report[0].Revenue != entity.IDR(15000) {
+		return fmt.Errorf("expected net revenue Rp 15.000, got %+v", report)
+	}
+
+	if err := r.Promotion.Delete(promo.ID); err != nil {
+		return err
+	}
+	if got, err = r.Transaction.GetByID(t.ID); err != nil {
+		return err
+	}
+	if len(got.Discounts) != 1 || got.Discounts[0].PromotionID != nil || got.Discounts[0].Nama != promo.Nama {
+		return fmt.Errorf("expected discount kept with promotion_id NULL, got %+v", got.Discounts)
+	}
+	return nil
+}
+
+func checkVoucherCRUD(r Repos) error {
+	maxDiscount, minSubtotal := entity.IDR(25000), entity.IDR(50000)
+	expires := time.Date(2026, 8, 18, 0, 0, 0, 0, time.UTC)
+	v, err := r.Voucher.Create(entity.Voucher{
+		Code: "MERDEKA17", Nama: "Merdeka 17%", Type: entity.VoucherPercentage, Percent: 1700,
+		MaxDiscount: &maxDiscount, MinSubtotal: &minSubtotal, UsageLimit: intPtr(100), PerCustomerLimit: intPtr(1), ExpiresAt: &expires,
+	})
+	if err != nil {
+		return err
+	}
+	if v.ID != 1 || v.UsedCount != 0 {
+		return fmt.Errorf("expected voucher ID 1 unused, got %+v", v)
+	}
+	got, err := r.Voucher.GetByCode("MERDEKA17")
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(got, v) || got.Percent != 1700 || *got.MaxDiscount != maxDiscount || *got.UsageLimit != 100 || !got.ExpiresAt.Equal(expires) {
+		return fmt.Errorf("voucher did not round trip: %+v", got)
+	}
+
+	amount := entity.IDR(10000)
+	hemat, err := r.Voucher.Create(entity.Voucher{Code: "HEMAT10K", Nama: "Hemat 10rb", Type: entity.VoucherFixed, Amount: &amount})
+	if err != nil {
+		return err
+	}
+	_, err = r.Voucher.Create(entity.Voucher{Code: "HEMAT10K", Nama: "Lagi", Type: entity.VoucherFixed, Amount: &amount})
+	if err := expectConflict(err, hemat.ID); err != nil {
+		return err
+	}
+	_, err = r.Voucher.Update(hemat.ID, entity.Voucher{Code: "MERDEKA17", Nama: "Bentrok", Type: entity.VoucherFixed, Amount: &amount})
+	if err := expectConflict(err, v.ID); err != nil {
+		return err
+	}
+
+	amount = entity.IDR(15000)
+	updated, err := r.Voucher.Update(hemat.ID, entity.Voucher{Code: "HEMAT15K", Nama: "Hemat 15rb", Type: entity.VoucherFixed, Amount: &amount})
+	if err != nil {
+		return err
+	}
+	if updated.Code != "HEMAT15K" || *updated.Amount != amount || updated.UsageLimit != nil {
+		return fmt.Errorf("voucher not updated: %+v", updated)
+	}
+	if _, err := r.Voucher.GetByCode("HEMAT10K"); err != repository.ErrVoucherNotFound {
+		return fmt.Errorf("expected old code to be gone, got %v", err)
+	}
+
+	all, err := r.Voucher.GetAll()
+	if err != nil {
+		return err
+	}
+	if len(all) != 2 || all[0].ID != v.ID || all[1].ID != hemat.ID {
+		return fmt.Errorf("expected 2 vouchers by ID, got %+v", all)
+	}
+
+	if err := r.Voucher.Delete(hemat.ID); err != nil {
+		return err
+	}
+	if err := r.Voucher.Delete(hemat.ID); err != repository.ErrVoucherNotFound {
+		return fmt.Errorf("expected ErrVoucherNotFound, got %v", err)
+	}
+	if _, err := r.Voucher.Update(hemat.ID, updated); err != repository.ErrVoucherNotFound {
+		return fmt.Errorf("expected ErrVoucherNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkVoucherRedeem(r Repos) error {
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	amount := entity.IDR(1000)
+	v, err := r.Voucher.Create(entity.Voucher{Code: "KOPI1K", Nama: "Kopi 1rb", Type: entity.VoucherFixed, Amount: &amount, UsageLimit: intPtr(3), PerCustomerLimit: intPtr(2)})
+	if err != nil {
+		return err
+	}
+
+	redeem := func(customer string) error {
+		t, err := sale(r, time.Now(), saleLine{kopi, 1})
+		if err != nil {
+			return err
+		}
+		// Seperti checkout: pemakaian yang ditolak membatalkan seluruh transaksi database
+		return r.TxManager.WithinTx(context.Background(), func(tx repository.Repositories) error {
+			redemption, err := tx.Voucher.Redeem(entity.VoucherRedemption{VoucherID: v.ID, TransactionID: t.ID, Customer: customer, Amount: amount})
+			if err == nil && (redemption.ID == 0 || redemption.Amount != amount) {
+				return fmt.Errorf("redemption not recorded: %+v", redemption)
+			}
+			return err
+		})
+	}
+
+	for _, customer := range []string{"0811", "0811"} {
+		if err := redeem(customer); err != nil {
+			return err
+		}
+	}
+	if err := redeem("0811"); err != repository.ErrVoucherCustomerLimit {
+		return fmt.Errorf("expected ErrVoucherCustomerLimit, got %v", err)
+	}
+	if err := redeem(""); err != repository.ErrVoucherCustomerLimit {
+		return fmt.Errorf("expected ErrVoucherCustomerLimit without customer, got %v", err)
+	}
+	if err := redeem("0822"); err != nil {
+		return err
+	}
+	if err := redeem("0833"); err != repository.ErrVoucherUsageLimit {
+		return fmt.Errorf("expected ErrVoucherUsageLimit, got %v", err)
+	}
+
+	got, err := r.Voucher.GetByID(v.ID)
+	if err != nil {
+		return err
+	}
+	if got.UsedCount != 3 {
+		return fmt.Errorf("expected used_count 3, got %d", got.UsedCount)
+	}
+	for customer, want := range map[string]int{"0811": 2, "0822": 1, "0833": 0} {
+		if n, err := r.Voucher.CountRedemptions(v.ID, customer); err != nil || n != want {
+			return fmt.Errorf("expected %d redemptions for %s, got %d (%v)", want, customer, n, err)
+		}
+	}
+	if _, err := r.Voucher.Redeem(entity.VoucherRedemption{VoucherID: 99, TransactionID: 1}); err != repository.ErrVoucherNotFound {
+		return fmt.Errorf("expected ErrVoucherNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkDeleteVoucherCascade(r Repos) error {
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(5000)})
+	if err != nil {
+		return err
+	}
+	amount := entity.IDR(1000)
+	v, err := r.Voucher.Create(entity.Voucher{Code: "KOPI1K", Nama: "Kopi 1rb", Type: entity.VoucherFixed, Amount: &amount})
+	if err != nil {
+		return err
+	}
+
+	t, err := r.Transaction.Create(entity.Transaction{
+		CreatedAt:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Subtotal:       entity.IDR(5000),
+		DiscountAmount: amount,
+		TotalAmount:    entity.IDR(4000),
+		Details: []entity.TransactionDetail{{
+			ProductID: intPtr(kopi.ID), NamaProduk: kopi.Nama, Harga: kopi.Harga,
+			Quantity: entity.Qty(1), Subtotal: entity.IDR(5000), Discount: amount,
+		}},
+		Discounts: []entity.TransactionDiscount{{VoucherID: intPtr(v.ID), Nama: v.Nama, Amount: amount}},
+	})
+	if err != nil {
+		return err
+	}
+	if got, err := r.Transaction.GetByID(t.ID); err != nil || len(got.Discounts) != 1 || got.Discounts[0].VoucherID == nil || *got.Discounts[0].VoucherID != v.ID {
+		return fmt.Errorf("voucher discount did not round trip: %+v (%v)", got.Discounts, err)
+	}
+	if _, err := r.Voucher.Redeem(entity.VoucherRedemption{VoucherID: v.ID, TransactionID: t.ID, Customer: "0811", Amount: amount}); err != nil {
+		return err
+	}
+
+	if err := r.Voucher.Delete(v.ID); err != nil {
+		return err
+	}
+	if n, err := r.Voucher.CountRedemptions(v.ID, "0811"); err != nil || n != 0 {
+		return fmt.Errorf("expected redemptions removed, got %d (%v)", n, err)
+	}
+	got, err := r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	if len(got.Discounts) != 1 || got.Discounts[0].VoucherID != nil || got.Discounts[0].Nama != v.Nama {
+		return fmt.Errorf("expected discount kept with voucher_id NULL, got %+v", got.Discounts)
+	}
+	return nil
+}
+
+func checkTaxRateCRUD(r Repos) error {
+	// Tarif bawaan dari migrasi 017 dihapus lagi oleh migrasi 020. ID tarif tidak dimulai
+	// dari 1 di SQLite karena AUTOINCREMENT tidak memakai ulang ID tarif yang dihapus.
+	rates, err := r.TaxRate.GetAll()
+	if err != nil {
+		return err
+	}
+	if len(rates) != 0 {
+		return fmt.Errorf("expected no tax rates, got %+v", rates)
+	}
+
+	if _, err := r.TaxRate.Create(entity.TaxRate{Nama: "Service Charge 5%", Rate: 500, Position: 1}); err != nil {
+		return err
+	}
+	existing, err := r.TaxRate.Create(entity.TaxRate{Nama: "PPN 11%", Rate: 1100, Compound: true, Position: 2})
+	if err != nil {
+		return err
+	}
+	if rates, err = r.TaxRate.GetAll(); err != nil {
+		return err
+	}
+	if len(rates) != 2 || rates[0].Nama != "Service Charge 5%" || rates[0].Rate != 500 || rates[0].Compound ||
+		rates[1].Nama != "PPN 11%" || rates[1].Rate != 1100 || !rates[1].Compound || rates[1].Inclusive {
+		return fmt.Errorf("expected service charge and PPN by position, got %+v", rates)
+	}
+	if rates[0].ExemptCategoryIDs == nil || len(rates[0].ExemptCategoryIDs) != 0 {
+		return fmt.Errorf("expected empty exemption list, got %#v", rates[0].ExemptCategoryIDs)
+	}
+
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	sembako, err := r.Category.Create(entity.Category{Name: "Sembako"})
+	if err != nil {
+		return err
+	}
+	pb1, err := r.TaxRate.Create(entity.TaxRate{Nama: "PB1 10%", Rate: 1000, Inclusive: true, ExemptCategoryIDs: []int{sembako.ID, minuman.ID}})
+	if err != nil {
+		return err
+	}
+	if pb1.ID <= existing.ID || !reflect.DeepEqual(pb1.ExemptCategoryIDs, []int{minuman.ID, sembako.ID}) {
+		return fmt.Errorf("expected a new tax rate exempting categories by ID, got %+v", pb1)
+	}
+	if got, err := r.TaxRate.GetByID(pb1.ID); err != nil || !reflect.DeepEqual(got, pb1) {
+		return fmt.Errorf("tax rate did not round trip: %+v (%v)", got, err)
+	}
+	if rates, err = r.TaxRate.GetAll(); err != nil {
+		return err
+	}
+	if len(rates) != 3 || rates[0].ID != pb1.ID {
+		return fmt.Errorf("expected position 0 to come first, got %+v", rates)
+	}
+
+	_, err = r.TaxRate.Create(entity.TaxRate{Nama: "PPN 11%", Rate: 1100})
+	if err := expectConflict(err, existing.ID); err != nil {
+		return err
+	}
+	_, err = r.TaxRate.Update(pb1.ID, entity.TaxRate{Nama: "PPN 11%", Rate: 1000})
+	if err := expectConflict(err, existing.ID); err != nil {
+		return err
+	}
+
+	updated, err := r.TaxRate.Update(pb1.ID, entity.TaxRate{Nama: "PB1 10%", Rate: 1000, Inclusive: true, Position: 3, ExemptCategoryIDs: []int{sembako.ID}})
+	if err != nil {
+		return err
+	}
+	if updated.Position != 3 || !reflect.DeepEqual(updated.ExemptCategoryIDs, []int{sembako.ID}) {
+		return fmt.Errorf("tax rate not updated: %+v", updated)
+	}
+
+	if err := r.TaxRate.Delete(pb1.ID); err != nil {
+		return err
+	}
+	if err := r.TaxRate.Delete(pb1.ID); err != repository.ErrTaxRateNotFound {
+		return fmt.Errorf("expected ErrTaxRateNotFound, got %v", err)
+	}
+	if _, err := r.TaxRate.Update(pb1.ID, updated); err != repository.ErrTaxRateNotFound {
+		return fmt.Errorf("expected ErrTaxRateNotFound, got %v", err)
+	}
+	if _, err := r.TaxRate.GetByID(pb1.ID); err != repository.ErrTaxRateNotFound {
+		return fmt.Errorf("expected ErrTaxRateNotFound, got %v", err)
+	}
+	return nil
+}
+
+func checkDeleteCategoryTaxExemption(r Repos) error {
+	minuman, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	sembako, err := r.Category.Create(entity.Category{Name: "Sembako"})
+	if err != nil {
+		return err
+	}
+	ppn, err := r.TaxRate.Create(entity.TaxRate{Nama: "PPN 11%", Rate: 1100, Compound: true, Position: 2, ExemptCategoryIDs: []int{minuman.ID, sembako.ID}})
+	if err != nil {
+		return err
+	}
+
+	if err := r.Category.Delete(sembako.ID); err != nil {
+		return err
+	}
+	got, err := r.TaxRate.GetByID(ppn.ID)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(got.ExemptCategoryIDs, []int{minuman.ID}) {
+		return fmt.Errorf("expected only category %d exempt, got %v", minuman.ID, got.ExemptCategoryIDs)
+	}
+	return nil
+}
+
+func checkTransactionTaxes(r Repos) error {
+	cost := entity.IDR(3000)
+	kopi, err := r.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(10000), HargaBeli: &cost})
+	if err != nil {
+		return err
+	}
+	service, err := r.TaxRate.Create(entity.TaxRate{Nama: "Service Charge 5%", Rate: 500, Position: 1})
+	if err != nil {
+		return err
+	}
+	ppn, err := r.TaxRate.Create(entity.TaxRate{Nama: "PPN 11%", Rate: 1100, Compound: true, Position: 2})
+	if err != nil {
+		return err
+	}
+	pb1, err := r.TaxRate.Create(entity.TaxRate{Nama: "PB1 10%", Rate: 1000, Inclusive: true})
+	if err != nil {
+		return err
+	}
+
+	// Kopi Rp 20.000 termasuk PB1 Rp 1.818,18, ditambah service charge 5% dan PPN 11% atas keduanya
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	t, err := r.Transaction.Create(entity.Transaction{
+		CreatedAt:   at,
+		Subtotal:    entity.IDR(20000),
+		TaxAmount:   entity.IDR(3311),
+		TotalAmount: entity.IDR(23311),
+		Details: []entity.TransactionDetail{{
+			ProductID: intPtr(kopi.ID), NamaProduk: kopi.Nama, Harga: kopi.Harga, HargaBeli: &cost,
+			Quantity: entity.Qty(2), Subtotal: entity.IDR(20000), TaxIncluded: entity.NewMoney(181818, entity.DefaultCurrency),
+		}},
+		Taxes: []entity.TransactionTax{
+			{TaxRateID: intPtr(pb1.ID), Nama: pb1.Nama, Rate: 1000, Inclusive: true, TaxableAmount: entity.NewMoney(1818182, entity.DefaultCurrency), Amount: entity.NewMoney(181818, entity.DefaultCurrency)},
+			{TaxRateID: intPtr(service.ID), Nama: service.Nama, Rate: 500, TaxableAmount: entity.IDR(20000), Amount: entity.IDR(1000)},
+			{TaxRateID: intPtr(ppn.ID), Nama: ppn.Nama, Rate: 1100, TaxableAmount: entity.IDR(21000), Amount: entity.IDR(2310)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	got, err := r.Transaction.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	if got.TaxAmount != entity.IDR(3311) || got.TotalAmount != entity.IDR(23311) || got.Subtotal != entity.IDR(20000) {
+		return fmt.Errorf("transaction totals did not round trip: %+v", got)
+	}
+	if got.Details[0].TaxIncluded != entity.NewMoney(181818, entity.DefaultCurrency) {
+		return fmt.Errorf("tax_included did not round trip: %+v", got.Details[0])
+	}
+	if !reflect.DeepEqual(got.Taxes, t.Taxes) {
+		return fmt.Errorf("taxes did not round trip: %+v", got.Taxes)
+	}
+
+	// Transaksi tanpa subtotal memakai total - pajak sebagai subtotal
+	plain, err := r.Transaction.Create(entity.Transaction{CreatedAt: at.AddDate(0, 0, 1), TaxAmount: entity.IDR(1000), TotalAmount: entity.IDR(21000),
+		Taxes: []entity.TransactionTax{{TaxRateID: intPtr(service.ID), Nama: service.Nama, Rate: 500, TaxableAmount: entity.IDR(20000), Amount: entity.IDR(1000)}},
+	})
+	if err != nil {
+		return err
+	}
+	if plain.Subtotal != entity.IDR(20000) {
+		return fmt.Errorf("expected subtotal Rp 20.000, got %v", plain.Subtotal)
+	}
+
+	// Pendapatan di laporan margin tanpa pajak inclusive
+	margin, err := r.Transaction.MarginReport(entity.MarginFilter{GroupBy: entity.MarginByProduct})
+	if err != nil {
+		return err
+	}
+	if len(margin) != 1 || margin[0].Revenue != entity.NewMoney(1818182, entity.DefaultCurrency) {
+		return fmt.Errorf("expected revenue without PB1, got %+v", margin)
+	}
+
+	report, err := r.Transaction.TaxReport(entity.TaxFilter{})
+	if err != nil {
+		return err
+	}
+	want := []entity.TaxRow{
+		{Nama: "PB1 10%", Rate: 1000, Inclusive: true, Transactions: 1, TaxableAmount: entity.NewMoney(1818182, entity.DefaultCurrency), Amount: entity.NewMoney(181818, entity.DefaultCurrency)},
+		{Nama: "PPN 11%", Rate: 1100, Transactions: 1, TaxableAmount: entity.IDR(21000), Amount: entity.IDR(2310)},
+		{Nama: "Service Charge 5%", Rate: 500, Transactions: 2, TaxableAmount: entity.IDR(40000), Amount: entity.IDR(2000)},
+	}
+	if !reflect.DeepEqual(report, want) {
+		return fmt.Errorf("expected tax report %+v, got %+v", want, report)
+	}
+	if report, err = r.Transaction.TaxReport(entity.TaxFilter{From: at.AddDate(0, 0, 1)}); err != nil || len(report) != 1 || report[0].Transactions != 1 {
+		return fmt.Errorf("expected only the second day in the report, got %+v (%v)", report, err)
+	}
+
+	if err := r.TaxRate.Delete(pb1.ID); err != nil {
+		return err
+	}
+	if got, err = r.Transaction.GetByID(t.ID); err != nil {
+		return err
+	}
+	if got.Taxes[0].TaxRateID != nil || got.Taxes[0].Nama != pb1.Nama || *got.Taxes[1].TaxRateID != service.ID {
+		return fmt.Errorf("expected tax kept with tax_rate_id NULL, got %+v", got.Taxes)
+	}
+	return nil
+}
+
+func checkConcurrentCreate(r Repos) error {
+	const workers = 20
+
+	var wg sync.WaitGroup
+	ids := make(chan int, workers)
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := r.Category.Create(entity.Category{Name: fmt.Sprintf("Kategori %d", i)})
+			if err != nil {
+				errs <- err
+				return
+			}
+			ids <- c.ID
+		}(i)
+	}
+	wg.Wait()
+	close(ids)
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return err
+	}
+	seen := make(map[int]bool, workers)
+	for id := range ids {
+		if seen[id] {
+			return fmt.Errorf("duplicate ID %d", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != workers {
+		return fmt.Errorf("expected %d categories, got %d", workers, len(seen))
+	}
+	return nil
+}
+
+func checkTxCommit(r Repos) error {
+	err := r.TxManager.WithinTx(context.Background(), func(tx repository.Repositories) error {
+		c, err := tx.Category.Create(entity.Category{Name: "Minuman"})
+		if err != nil {
+			return err
+		}
+		// Data yang ditulis di dalam transaksi harus terbaca di transaksi yang sama
+		if _, err := tx.Category.GetByID(c.ID); err != nil {
+			return err
+		}
+		_, err = tx.Product.Create(entity.Product{Nama: "Es Teh Manis", Harga: entity.IDR(5000), CategoryID: intPtr(c.ID)})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	products, err := r.Product.GetAll(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	if len(products) != 1 {
+		return fmt.Errorf("expected 1 committed product, got %d", len(products))
+	}
+	return nil
+}
+
+func checkTxRollback(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+
+	errAbort := errors.New("abort")
+	err = r.TxManager.WithinTx(context.Background(), func(tx repository.Repositories) error {
+		if _, err := tx.Product.Create(entity.Product{Nama: "Es Teh Manis", Harga: entity.IDR(5000), CategoryID: intPtr(c.ID)}); err != nil {
+			return err
+		}
+		if _, err := tx.Category.Update(c.ID, entity.Category{Name: "Berubah"}); err != nil {
+			return err
+		}
+		if err := tx.Category.Delete(c.ID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		return fmt.Errorf("expected fn error to be returned, got %v", err)
+	}
+
+	return expectUntouched(r, c)
+}
+
+func checkTxPanic(r Repos) (err error) {
+	c, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				err = errors.New("panic was swallowed by WithinTx")
+			}
+		}()
+		r.TxManager.WithinTx(context.Background(), func(tx repository.Repositories) error {
+			if _, err := tx.Category.Update(c.ID, entity.Category{Name: "Berubah"}); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+	if err != nil {
+		return err
+	}
+
+	return expectUntouched(r, c)
+}
+
+// expectUntouched verifies that a rolled back transaction left no trace
+func expectUntouched(r Repos, c entity.Category) error {
+	got, err := r.Category.GetByID(c.ID)
+	if err != nil {
+		return fmt.Errorf("category must survive rollback: %w", err)
+	}
+	if got.Name != c.Name {
+		return fmt.Errorf("update must be rolled back, got name %q", got.Name)
+	}
+	products, err := r.Product.GetAll(entity.ProductFilter{})
+	if err != nil {
+		return err
+	}
+	if len(products) != 0 {
+		return fmt.Errorf("expected no products after rollback, got %d", len(products))
+	}
+	return nil
+}
+
+// intPtr returns a pointer to n, for nullable foreign keys
+func intPtr(n int) *int {
+	return &n
+}
+
+func qtyPtr(q entity.Quantity) *entity.Quantity {
+	return &q
+}
+
+// hasCategory reports whether p belongs to category id
+func hasCategory(p entity.Product, id int) bool {
+	return p.CategoryID != nil && *p.CategoryID == id
+}
+
+// expectConflict verifies err is a ConflictError naming the existing record existingID
+func expectConflict(err error, existingID int) error {
+	if !errors.Is(err, repository.ErrConflict) {
+		return fmt.Errorf("expected ErrConflict, got %v", err)
+	}
+	var conflict *repository.ConflictError
+	if !errors.As(err, &conflict) {
+		return fmt.Errorf("expected *ConflictError, got %T", err)
+	}
+
+	var id int
+	switch existing := conflict.Existing.(type) {
+	case entity.Category:
+		id = existing.ID
+	case entity.Product:
+		id = existing.ID
+	case entity.Voucher:
+		id = existing.ID
+	case entity.TaxRate:
+		id = existing.ID
+	}
+	if id != existingID {
+		return fmt.Errorf("expected conflict with record %d, got %v", existingID, conflict.Existing)
+	}
+	return nil
+}
+
+func checkReadTx(r Repos) error {
+	c, err := r.Category.Create(entity.Category{Name: "Minuman"})
+	if err != nil {
+		return err
+	}
+	if _, err := r.Product.Create(entity.Product{Nama: "Es Teh Manis", Harga: entity.IDR(5000), CategoryID: intPtr(c.ID)}); err != nil {
+		return err
+	}
+
+	var products []entity.Product
+	err = r.TxManager.WithinReadTx(context.Background(), func(tx repository.Repositories) error {
+		if _, err := tx.Category.GetByID(c.ID); err != nil {
+			return err
+		}
+		var err error
+		products, err = tx.Product.GetAllWithCategory(entity.ProductFilter{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(products) != 1 || products[0].Category == nil || products[0].Category.ID != c.ID {
+		return fmt.Errorf("expected 1 product with its category, got %+v", products)
+	}
+
+	sentinel := errors.New("boom")
+	err = r.TxManager.WithinReadTx(context.Background(), func(tx repository.Repositories) error {
+		return sentinel
+	})
+	if err != sentinel {
+		return fmt.Errorf("expected fn's error, got %v", err)
+	}
+	return nil
+}
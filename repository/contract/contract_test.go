@@ -0,0 +1,103 @@
+package contract
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"kasir-api/config"
+	"kasir-api/config/migration"
+	"kasir-api/repository"
+	"kasir-api/repository/memory"
+	"kasir-api/repository/sqlite"
+)
+
+// factory returns empty repositories whose ID sequences start at 1
+type factory func(t *testing.T) Repos
+
+func TestMemory(t *testing.T) {
+	runChecks(t, func(t *testing.T) Repos {
+		store := memory.NewStore()
+		return newRepos(memory.NewRepositories(store), memory.NewTxManager(store))
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	// Database baru di memori untuk setiap check
+	runChecks(t, func(t *testing.T) Repos {
+		db, err := sqlite.Open("sqlite::memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if err := migration.Migrate(db); err != nil {
+			t.Fatal(err)
+		}
+		return newRepos(repository.NewRepositories(db), repository.NewTxManager(db))
+	})
+}
+
+// TestPostgres - semua data di TEST_DATABASE_URL dihapus sebelum setiap check
+func TestPostgres(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set, run make test-postgres")
+	}
+	cfg := config.DefaultDBConfig()
+	cfg.URL = url
+	db, err := config.Connect(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := migration.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	runChecks(t, func(t *testing.T) Repos {
+		if err := resetPostgres(db); err != nil {
+			t.Fatal(err)
+		}
+		return newRepos(repository.NewRepositories(db), repository.NewTxManager(db))
+	})
+}
+
+// resetPostgres - kosongkan semua tabel dan kembalikan satuan bawaan migrasi
+func resetPostgres(db *sql.DB) error {
+	_, err := db.Exec("TRUNCATE transaction_taxes, tax_rate_exemptions, tax_rates, voucher_redemptions, vouchers, promotions, transaction_discounts, product_units, bundle_components, transactions, transaction_details, transaction_detail_modifiers, modifiers, modifier_groups, product_variants, product_prices, products, categories RESTART IDENTITY CASCADE")
+	if err != nil {
+		return err
+	}
+	// Satuan bawaan dari migrasi 013 tetap ada, satuan buatan check dihapus
+	_, err = db.Exec("DELETE FROM units WHERE code NOT IN ('pcs', 'dus', 'pak', 'kg', 'g', 'l', 'ml')")
+	return err
+}
+
+// runChecks runs every check as a subtest against fresh repositories
+func runChecks(t *testing.T, fresh factory) {
+	for _, check := range Checks {
+		t.Run(check.Name, func(t *testing.T) {
+			if err := check.Run(fresh(t)); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// newRepos bundles repositories and their TxManager for the contract suite
+func newRepos(repos repository.Repositories, txManager repository.TxManagerInterface) Repos {
+	return Repos{
+		Category:    repos.Category,
+		Product:     repos.Product,
+		Transaction: repos.Transaction,
+		Price:       repos.Price,
+		Variant:     repos.Variant,
+		Modifier:    repos.Modifier,
+		Bundle:      repos.Bundle,
+		Unit:        repos.Unit,
+		Promotion:   repos.Promotion,
+		Voucher:     repos.Voucher,
+		TaxRate:     repos.TaxRate,
+		TxManager:   txManager,
+	}
+}
@@ -0,0 +1,5 @@
+// Package contract is the behavior every repository implementation must share.
+// The checks run against each backend with `go test ./repository/contract`;
+// PostgreSQL is only checked when TEST_DATABASE_URL is set: CI runs it against a
+// postgres service, locally use `make test-postgres` (needs docker).
+package contract
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"kasir-api/repository/sqlite"
+
+	"github.com/lib/pq"
+)
+
+// Errors shared by every repository implementation
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrProductNotFound       = errors.New("product not found")
+	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrPriceNotFound         = errors.New("price not found")
+	ErrVariantNotFound       = errors.New("variant not found")
+	ErrModifierGroupNotFound = errors.New("modifier group not found")
+	ErrModifierNotFound      = errors.New("modifier not found")
+	ErrInsufficientStock     = errors.New("insufficient stock")
+	ErrStockNotTracked       = errors.New("stock is not tracked for this product")
+	ErrUnitNotFound          = errors.New("unit not found")
+	ErrUnitInUse             = errors.New("unit is still used by a product")
+	ErrPromotionNotFound     = errors.New("promotion not found")
+	ErrVoucherNotFound       = errors.New("voucher not found")
+	ErrVoucherUsageLimit     = errors.New("voucher usage limit has been reached")
+	ErrVoucherCustomerLimit  = errors.New("voucher usage limit for this customer has been reached")
+	ErrTaxRateNotFound       = errors.New("tax rate not found")
+	ErrConflict              = errors.New("name already exists")
+)
+
+// ConflictError - nama bentrok dengan record lain (unique violation), dicocokkan dengan errors.Is(err, ErrConflict)
+type ConflictError struct {
+	Resource string      // "category" atau "product"
+	Name     string      // nama yang ditolak
+	Scope    string      // cakupan unik, misal "parent category"
+	Existing interface{} // record yang sudah ada, nil jika bentrok baru terdeteksi oleh constraint
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %q already exists in the same %s", e.Resource, e.Name, e.Scope)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// isUniqueViolation - unique violation dari PostgreSQL (SQLSTATE 23505) atau SQLite
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+	return sqlite.IsUniqueViolation(err)
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"kasir-api/entity"
+)
+
+// BundleRepository - in-memory implementation of BundleRepositoryInterface
+type BundleRepository struct {
+	access
+}
+
+// NewBundleRepository - constructor untuk in-memory BundleRepository
+func NewBundleRepository(store *Store) *BundleRepository {
+	return &BundleRepository{access: access{store: store}}
+}
+
+// GetComponents - komponen satu bundle, urut sesuai urutan input
+func (r *BundleRepository) GetComponents(bundleID int) ([]entity.BundleComponent, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.components(bundleID), nil
+}
+
+// GetComponentsByBundles - komponen banyak bundle sekaligus, dikelompokkan per bundle ID
+func (r *BundleRepository) GetComponentsByBundles(bundleIDs []int) (map[int][]entity.BundleComponent, error) {
+	r.rlock()
+	defer r.runlock()
+
+	grouped := make(map[int][]entity.BundleComponent)
+	for _, id := range bundleIDs {
+		if components := r.components(id); components != nil {
+			grouped[id] = components
+		}
+	}
+	return grouped, nil
+}
+
+// components returns copies of the components of a bundle with their names, like the
+// JOIN in the SQL repository. Caller must hold the store lock.
+func (r *BundleRepository) components(bundleID int) []entity.BundleComponent {
+	stored := r.store.bundleComponents[bundleID]
+	if len(stored) == 0 {
+		return nil
+	}
+
+	components := make([]entity.BundleComponent, len(stored))
+	for i, c := range stored {
+		c = cloneComponent(c)
+		p, ok := r.store.products[c.ProductID]
+		if !ok {
+			p = r.store.deletedProducts[c.ProductID]
+		}
+		c.Nama = p.Nama
+		if c.VariantID != nil {
+			c.Nama += " (" + r.store.variants[*c.VariantID].Nama + ")"
+		}
+		components[i] = c
+	}
+	return components
+}
+
+// SetComponents - ganti semua komponen bundle
+func (r *BundleRepository) SetComponents(bundleID int, components []entity.BundleComponent) error {
+	r.lock()
+	defer r.unlock()
+
+	if err := r.validate(bundleID, components); err != nil {
+		return err
+	}
+
+	if len(components) == 0 {
+		delete(r.store.bundleComponents, bundleID)
+		return nil
+	}
+	stored := make([]entity.BundleComponent, len(components))
+	for i, c := range components {
+		c = cloneComponent(c)
+		c.Nama = ""
+		stored[i] = c
+	}
+	r.store.bundleComponents[bundleID] = stored
+	return nil
+}
+
+// IsComponent - apakah produk (atau hanya varian tersebut jika variantID diisi) dipakai oleh bundle
+func (r *BundleRepository) IsComponent(productID int, variantID *int) (bool, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.store.isComponent(productID, variantID), nil
+}
+
+// validate mirrors the check, unique and foreign key constraints of bundle_components.
+// Caller must hold the store lock.
+func (r *BundleRepository) validate(bundleID int, components []entity.BundleComponent) error {
+	if _, ok := r.store.products[bundleID]; !ok {
+		return ErrInvalidProductFK
+	}
+
+	type key struct{ product, variant int }
+	seen := make(map[key]bool, len(components))
+	for _, c := range components {
+		if c.ProductID == bundleID || c.Quantity <= 0 {
+			return ErrInvalidComponent
+		}
+		if _, ok := r.store.products[c.ProductID]; !ok {
+			if _, ok := r.store.deletedProducts[c.ProductID]; !ok {
+				return ErrInvalidProductFK
+			}
+		}
+		k := key{product: c.ProductID}
+		if c.VariantID != nil {
+			if _, ok := r.store.variants[*c.VariantID]; !ok {
+				return ErrInvalidVariantFK
+			}
+			k.variant = *c.VariantID
+		}
+		if seen[k] {
+			return ErrDuplicateComponent
+		}
+		seen[k] = true
+	}
+	return nil
+}
+
+// cloneComponent copies the variant pointer so callers never share memory with the store
+func cloneComponent(c entity.BundleComponent) entity.BundleComponent {
+	if c.VariantID != nil {
+		id := *c.VariantID
+		c.VariantID = &id
+	}
+	return c
+}
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// CategoryRepository - in-memory implementation of CategoryRepositoryInterface
+type CategoryRepository struct {
+	access
+}
+
+// NewCategoryRepository - constructor untuk in-memory CategoryRepository
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{access: access{store: store}}
+}
+
+// GetAll - ambil semua kategori, urut berdasarkan ID
+func (r *CategoryRepository) GetAll() ([]entity.Category, error) {
+	r.rlock()
+	defer r.runlock()
+
+	var categories []entity.Category
+	for _, c := range r.store.categories {
+		categories = append(categories, cloneCategory(c))
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+
+	return categories, nil
+}
+
+// GetByID - ambil kategori berdasarkan ID
+func (r *CategoryRepository) GetByID(id int) (entity.Category, error) {
+	r.rlock()
+	defer r.runlock()
+
+	c, ok := r.store.categories[id]
+	if !ok {
+		return entity.Category{}, repository.ErrCategoryNotFound
+	}
+	return cloneCategory(c), nil
+}
+
+// Create - tambah kategori baru
+func (r *CategoryRepository) Create(category entity.Category) (entity.Category, error) {
+	r.lock()
+	defer r.unlock()
+
+	if err := r.validate(0, category); err != nil {
+		return entity.Category{}, err
+	}
+	if err := r.checkConflict(0, category); err != nil {
+		return entity.Category{}, err
+	}
+
+	category.ID = r.store.nextCategoryID
+	category.Path, category.Children = nil, nil
+	r.store.nextCategoryID++
+	r.store.categories[category.ID] = cloneCategory(category)
+
+	return category, nil
+}
+
+// Update - update kategori
+func (r *CategoryRepository) Update(id int, category entity.Category) (entity.Category, error) {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.categories[id]; !ok {
+		return entity.Category{}, repository.ErrCategoryNotFound
+	}
+	if err := r.validate(id, category); err != nil {
+		return entity.Category{}, err
+	}
+	if err := r.checkConflict(id, category); err != nil {
+		return entity.Category{}, err
+	}
+
+	category.ID = id
+	category.Path, category.Children = nil, nil
+	r.store.categories[id] = cloneCategory(category)
+
+	return category, nil
+}
+
+// Delete - hapus kategori, produk dan sub-kategori terkait kehilangan referensinya (ON DELETE SET NULL)
+func (r *CategoryRepository) Delete(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.categories[id]; !ok {
+		return repository.ErrCategoryNotFound
+	}
+	delete(r.store.categories, id)
+
+	for childID, c := range r.store.categories {
+		if c.ParentID != nil && *c.ParentID == id {
+			c.ParentID = nil
+			r.store.categories[childID] = c
+		}
+	}
+
+	for _, products := range []map[int]entity.Product{r.store.products, r.store.deletedProducts} {
+		for productID, p := range products {
+			if p.CategoryID != nil && *p.CategoryID == id {
+				p.CategoryID = nil
+				products[productID] = p
+			}
+		}
+	}
+
+	// modifier_groups.category_id ON DELETE CASCADE
+	r.store.deleteModifierGroups(func(g entity.ModifierGroup) bool {
+		return g.CategoryID != nil && *g.CategoryID == id
+	})
+
+	// promotions.category_id ON DELETE CASCADE
+	r.store.deletePromotions(func(p entity.Promotion) bool {
+		return p.CategoryID != nil && *p.CategoryID == id
+	})
+
+	// tax_rate_exemptions.category_id ON DELETE CASCADE
+	for rateID, t := range r.store.taxRates {
+		exempt := make([]int, 0, len(t.ExemptCategoryIDs))
+		for _, categoryID := range t.ExemptCategoryIDs {
+			if categoryID != id {
+				exempt = append(exempt, categoryID)
+			}
+		}
+		t.ExemptCategoryIDs = exempt
+		r.store.taxRates[rateID] = t
+	}
+
+	return nil
+}
+
+// GetPath - breadcrumb dari root sampai kategori id
+func (r *CategoryRepository) GetPath(id int) ([]entity.CategoryCrumb, error) {
+	r.rlock()
+	defer r.runlock()
+
+	c, ok := r.store.categories[id]
+	if !ok {
+		return nil, repository.ErrCategoryNotFound
+	}
+
+	path := []entity.CategoryCrumb{{ID: c.ID, Name: c.Name}}
+	for c.ParentID != nil && len(path) <= maxCategoryDepth {
+		if c, ok = r.store.categories[*c.ParentID]; !ok {
+			break
+		}
+		path = append([]entity.CategoryCrumb{{ID: c.ID, Name: c.Name}}, path...)
+	}
+	return path, nil
+}
+
+// MoveChildren - pindahkan sub-kategori langsung ke parent lain (nil = jadi root)
+func (r *CategoryRepository) MoveChildren(fromParentID int, toParentID *int) (int, error) {
+	r.lock()
+	defer r.unlock()
+
+	if toParentID != nil {
+		target, ok := r.store.categories[*toParentID]
+		if !ok {
+			return 0, ErrInvalidFK
+		}
+		// Target yang merupakan salah satu sub-kategori akan menjadi parent dirinya sendiri
+		if target.ParentID != nil && *target.ParentID == fromParentID {
+			return 0, ErrSelfParent
+		}
+	}
+
+	// Unique index (parent_id, LOWER(name)) dicek sebelum ada yang dipindah
+	for _, c := range r.store.categories {
+		if c.ParentID == nil || *c.ParentID != fromParentID {
+			continue
+		}
+		moving := c
+		moving.ParentID = toParentID
+		if err := r.checkConflict(c.ID, moving); err != nil {
+			return 0, err
+		}
+	}
+
+	moved := 0
+	for id, c := range r.store.categories {
+		if c.ParentID != nil && *c.ParentID == fromParentID {
+			c.ParentID = toParentID
+			r.store.categories[id] = cloneCategory(c)
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// validate mirrors the column, foreign key and CHECK constraints of the
+// categories table. Caller must hold the store lock.
+func (r *CategoryRepository) validate(id int, category entity.Category) error {
+	if utf8.RuneCountInString(category.Name) > 100 {
+		return ErrNameTooLong
+	}
+	if category.ParentID == nil {
+		return nil
+	}
+	if *category.ParentID == id {
+		return ErrSelfParent
+	}
+	if _, ok := r.store.categories[*category.ParentID]; !ok {
+		return ErrInvalidFK
+	}
+	return nil
+}
+
+// checkConflict mirrors the unique index on (parent_id, LOWER(name)).
+// Caller must hold the store lock.
+func (r *CategoryRepository) checkConflict(id int, category entity.Category) error {
+	for _, c := range r.store.categories {
+		if c.ID != id && sameNullableID(c.ParentID, category.ParentID) && strings.EqualFold(c.Name, category.Name) {
+			return &repository.ConflictError{Resource: "category", Name: category.Name, Scope: "parent category", Existing: cloneCategory(c)}
+		}
+	}
+	return nil
+}
+
+// sameNullableID compares nullable foreign keys, NULL equals NULL like COALESCE(x, 0)
+func sameNullableID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// cloneCategory copies pointer and slice fields so callers never share memory with the store
+func cloneCategory(c entity.Category) entity.Category {
+	if c.ParentID != nil {
+		id := *c.ParentID
+		c.ParentID = &id
+	}
+	c.Path = append([]entity.CategoryCrumb(nil), c.Path...)
+	c.Children = append([]entity.Category(nil), c.Children...)
+	return c
+}
+
+// subtree returns id and every descendant category. Caller must hold the store lock.
+func (s *Store) subtree(id int) map[int]bool {
+	ids := map[int]bool{id: true}
+	for added := true; added; {
+		added = false
+		for childID, c := range s.categories {
+			if c.ParentID != nil && ids[*c.ParentID] && !ids[childID] {
+				ids[childID] = true
+				added = true
+			}
+		}
+	}
+	return ids
+}
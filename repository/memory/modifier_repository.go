@@ -0,0 +1,258 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// ModifierRepository - in-memory implementation of ModifierRepositoryInterface
+type ModifierRepository struct {
+	access
+}
+
+// NewModifierRepository - constructor untuk in-memory ModifierRepository
+func NewModifierRepository(store *Store) *ModifierRepository {
+	return &ModifierRepository{access: access{store: store}}
+}
+
+// GetAllGroups - semua grup modifier beserta pilihannya
+func (r *ModifierRepository) GetAllGroups() ([]entity.ModifierGroup, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.groups(func(entity.ModifierGroup) bool { return true }), nil
+}
+
+// GetGroupsFor - grup yang menempel ke produk atau ke salah satu categoryIDs
+func (r *ModifierRepository) GetGroupsFor(productID int, categoryIDs []int) ([]entity.ModifierGroup, error) {
+	r.rlock()
+	defer r.runlock()
+
+	wanted := make(map[int]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		wanted[id] = true
+	}
+	return r.groups(func(g entity.ModifierGroup) bool {
+		return (g.ProductID != nil && *g.ProductID == productID) || (g.CategoryID != nil && wanted[*g.CategoryID])
+	}), nil
+}
+
+// groups returns copies of the groups matching fn with their modifiers, ordered by ID.
+// Caller must hold the store lock.
+func (r *ModifierRepository) groups(fn func(g entity.ModifierGroup) bool) []entity.ModifierGroup {
+	var groups []entity.ModifierGroup
+	for _, g := range r.store.modifierGroups {
+		if fn(g) {
+			groups = append(groups, r.withModifiers(g))
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups
+}
+
+// withModifiers copies g and attaches its modifiers ordered by ID. Caller must hold the store lock.
+func (r *ModifierRepository) withModifiers(g entity.ModifierGroup) entity.ModifierGroup {
+	g = cloneModifierGroup(g)
+	for _, m := range r.store.modifiers {
+		if m.GroupID == g.ID {
+			g.Modifiers = append(g.Modifiers, m)
+		}
+	}
+	sort.Slice(g.Modifiers, func(i, j int) bool { return g.Modifiers[i].ID < g.Modifiers[j].ID })
+	return g
+}
+
+// GetGroupByID - ambil grup modifier beserta pilihannya
+func (r *ModifierRepository) GetGroupByID(id int) (entity.ModifierGroup, error) {
+	r.rlock()
+	defer r.runlock()
+
+	g, ok := r.store.modifierGroups[id]
+	if !ok {
+		return entity.ModifierGroup{}, repository.ErrModifierGroupNotFound
+	}
+	return r.withModifiers(g), nil
+}
+
+// CreateGroup - tambah grup modifier tanpa pilihan, pilihan ditambah lewat CreateModifier
+func (r *ModifierRepository) CreateGroup(group entity.ModifierGroup) (entity.ModifierGroup, error) {
+	r.lock()
+	defer r.unlock()
+
+	if err := r.validateGroup(group); err != nil {
+		return entity.ModifierGroup{}, err
+	}
+
+	group.ID = r.store.nextGroupID
+	r.store.nextGroupID++
+	group = cloneModifierGroup(group)
+	r.store.modifierGroups[group.ID] = group
+	return cloneModifierGroup(group), nil
+}
+
+// UpdateGroup - update nama, tempat menempel dan batas pilihan grup
+func (r *ModifierRepository) UpdateGroup(id int, group entity.ModifierGroup) (entity.ModifierGroup, error) {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.modifierGroups[id]; !ok {
+		return entity.ModifierGroup{}, repository.ErrModifierGroupNotFound
+	}
+	if err := r.validateGroup(group); err != nil {
+		return entity.ModifierGroup{}, err
+	}
+
+	group.ID = id
+	r.store.modifierGroups[id] = cloneModifierGroup(group)
+	return r.withModifiers(group), nil
+}
+
+// DeleteGroup - hapus grup beserta pilihannya, baris penjualan lama tetap menyimpan salinannya
+func (r *ModifierRepository) DeleteGroup(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.modifierGroups[id]; !ok {
+		return repository.ErrModifierGroupNotFound
+	}
+	r.store.deleteModifierGroups(func(g entity.ModifierGroup) bool { return g.ID == id })
+	return nil
+}
+
+// GetModifierByID - ambil satu pilihan modifier
+func (r *ModifierRepository) GetModifierByID(id int) (entity.Modifier, error) {
+	r.rlock()
+	defer r.runlock()
+
+	m, ok := r.store.modifiers[id]
+	if !ok {
+		return entity.Modifier{}, repository.ErrModifierNotFound
+	}
+	return m, nil
+}
+
+// CreateModifier - tambah pilihan ke grup, nama unik per grup
+func (r *ModifierRepository) CreateModifier(modifier entity.Modifier) (entity.Modifier, error) {
+	r.lock()
+	defer r.unlock()
+
+	if err := r.validateModifier(modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+	if err := r.checkConflict(0, modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+
+	modifier.ID = r.store.nextModifierID
+	r.store.nextModifierID++
+	modifier.Harga.Currency = modifier.Harga.Cur()
+	r.store.modifiers[modifier.ID] = modifier
+	return modifier, nil
+}
+
+// UpdateModifier - update nama dan harga pilihan, group_id tidak bisa dipindah
+func (r *ModifierRepository) UpdateModifier(id int, modifier entity.Modifier) (entity.Modifier, error) {
+	r.lock()
+	defer r.unlock()
+
+	current, ok := r.store.modifiers[id]
+	if !ok {
+		return entity.Modifier{}, repository.ErrModifierNotFound
+	}
+	modifier.ID = id
+	modifier.GroupID = current.GroupID
+	if err := r.validateModifier(modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+	if err := r.checkConflict(id, modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+
+	modifier.Harga.Currency = modifier.Harga.Cur()
+	r.store.modifiers[id] = modifier
+	return modifier, nil
+}
+
+// DeleteModifier - hapus pilihan, baris penjualan lama tetap ada dengan modifier_id NULL
+func (r *ModifierRepository) DeleteModifier(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.modifiers[id]; !ok {
+		return repository.ErrModifierNotFound
+	}
+	r.store.deleteModifier(id)
+	return nil
+}
+
+// validateGroup mirrors the column, check and foreign key constraints of modifier_groups.
+// Caller must hold the store lock.
+func (r *ModifierRepository) validateGroup(group entity.ModifierGroup) error {
+	if utf8.RuneCountInString(group.Nama) > 100 {
+		return ErrNameTooLong
+	}
+	if (group.ProductID == nil) == (group.CategoryID == nil) {
+		return ErrInvalidModifierTarget
+	}
+	if group.MinSelect < 0 || (group.MaxSelect != 0 && group.MaxSelect < group.MinSelect) {
+		return ErrInvalidModifierRange
+	}
+	if group.ProductID != nil {
+		if _, ok := r.store.products[*group.ProductID]; !ok {
+			if _, ok := r.store.deletedProducts[*group.ProductID]; !ok {
+				return ErrInvalidProductFK
+			}
+		}
+	}
+	if group.CategoryID != nil {
+		if _, ok := r.store.categories[*group.CategoryID]; !ok {
+			return ErrInvalidFK
+		}
+	}
+	return nil
+}
+
+// validateModifier mirrors the column, check and foreign key constraints of modifiers.
+// Caller must hold the store lock.
+func (r *ModifierRepository) validateModifier(modifier entity.Modifier) error {
+	if utf8.RuneCountInString(modifier.Nama) > 100 {
+		return ErrNameTooLong
+	}
+	if modifier.Harga.IsNegative() {
+		return ErrNegativeHarga
+	}
+	if _, ok := r.store.modifierGroups[modifier.GroupID]; !ok {
+		return ErrInvalidModifierGroupFK
+	}
+	return nil
+}
+
+// checkConflict mirrors the unique index on (group_id, LOWER(nama)).
+// Caller must hold the store lock.
+func (r *ModifierRepository) checkConflict(id int, modifier entity.Modifier) error {
+	for _, m := range r.store.modifiers {
+		if m.ID != id && m.GroupID == modifier.GroupID && strings.EqualFold(m.Nama, modifier.Nama) {
+			return &repository.ConflictError{Resource: "modifier", Name: modifier.Nama, Scope: "group", Existing: m}
+		}
+	}
+	return nil
+}
+
+// cloneModifierGroup copies pointer fields and derives Required like the SQL repository
+func cloneModifierGroup(g entity.ModifierGroup) entity.ModifierGroup {
+	if g.ProductID != nil {
+		id := *g.ProductID
+		g.ProductID = &id
+	}
+	if g.CategoryID != nil {
+		id := *g.CategoryID
+		g.CategoryID = &id
+	}
+	g.Required = g.MinSelect > 0
+	g.Modifiers = []entity.Modifier{}
+	return g
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// PriceRepository - in-memory implementation of PriceRepositoryInterface
+type PriceRepository struct {
+	access
+}
+
+// NewPriceRepository - constructor untuk in-memory PriceRepository
+func NewPriceRepository(store *Store) *PriceRepository {
+	return &PriceRepository{access: access{store: store}}
+}
+
+// GetByProduct - semua harga produk, urut dari yang paling lama
+func (r *PriceRepository) GetByProduct(productID int) ([]entity.ProductPrice, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.byProduct(productID), nil
+}
+
+// byProduct returns copies of a product's prices ordered by effective_from.
+// Caller must hold the store lock.
+func (r *PriceRepository) byProduct(productID int) []entity.ProductPrice {
+	var prices []entity.ProductPrice
+	for _, p := range r.store.prices {
+		if p.ProductID == productID {
+			prices = append(prices, clonePrice(p))
+		}
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].EffectiveFrom.Before(prices[j].EffectiveFrom)
+	})
+	return prices
+}
+
+// GetByID - ambil harga berdasarkan ID
+func (r *PriceRepository) GetByID(id int) (entity.ProductPrice, error) {
+	r.rlock()
+	defer r.runlock()
+
+	p, ok := r.store.prices[id]
+	if !ok {
+		return entity.ProductPrice{}, repository.ErrPriceNotFound
+	}
+	return clonePrice(p), nil
+}
+
+// GetEffective - harga produk yang berlaku pada waktu at
+func (r *PriceRepository) GetEffective(productID int, at time.Time) (entity.ProductPrice, error) {
+	r.rlock()
+	defer r.runlock()
+
+	prices := r.byProduct(productID)
+	for i := len(prices) - 1; i >= 0; i-- {
+		if prices[i].CoversTime(at) {
+			return prices[i], nil
+		}
+	}
+	return entity.ProductPrice{}, repository.ErrPriceNotFound
+}
+
+// CurrentPrices - harga yang berlaku pada waktu at untuk setiap produk yang punya riwayat harga
+func (r *PriceRepository) CurrentPrices(at time.Time) (map[int]entity.Money, error) {
+	r.rlock()
+	defer r.runlock()
+
+	prices := make(map[int]entity.Money)
+	for _, p := range r.store.prices {
+		if p.CoversTime(at) {
+			prices[p.ProductID] = p.Harga
+		}
+	}
+	return prices, nil
+}
+
+// Create - tambah harga baru
+func (r *PriceRepository) Create(price entity.ProductPrice) (entity.ProductPrice, error) {
+	r.lock()
+	defer r.unlock()
+
+	price.Harga.Currency = price.Harga.Cur()
+	price.EffectiveFrom = price.EffectiveFrom.UTC().Truncate(time.Microsecond)
+	price.EffectiveTo = utcTime(price.EffectiveTo)
+	if price.CreatedAt.IsZero() {
+		price.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	if err := r.validate(price); err != nil {
+		return entity.ProductPrice{}, err
+	}
+	for _, p := range r.store.prices {
+		if p.ProductID == price.ProductID && p.EffectiveFrom.Equal(price.EffectiveFrom) {
+			return entity.ProductPrice{}, ErrDuplicatePriceStart
+		}
+	}
+
+	price.ID = r.store.nextPriceID
+	r.store.nextPriceID++
+	r.store.prices[price.ID] = clonePrice(price)
+	return price, nil
+}
+
+// Update - ubah harga dan akhir rentang, awal rentang tidak bisa diubah
+func (r *PriceRepository) Update(price entity.ProductPrice) error {
+	r.lock()
+	defer r.unlock()
+
+	stored, ok := r.store.prices[price.ID]
+	if !ok {
+		return repository.ErrPriceNotFound
+	}
+	stored.Harga = entity.NewMoney(price.Harga.Amount, price.Harga.Cur())
+	stored.EffectiveTo = utcTime(price.EffectiveTo)
+	if err := r.validate(stored); err != nil {
+		return err
+	}
+	r.store.prices[price.ID] = stored
+	return nil
+}
+
+// Delete - hapus harga
+func (r *PriceRepository) Delete(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.prices[id]; !ok {
+		return repository.ErrPriceNotFound
+	}
+	delete(r.store.prices, id)
+	return nil
+}
+
+// validate mirrors the column, check and foreign key constraints of product_prices.
+// Caller must hold the store lock.
+func (r *PriceRepository) validate(price entity.ProductPrice) error {
+	if price.Harga.IsNegative() {
+		return ErrNegativeHarga
+	}
+	if price.EffectiveTo != nil && !price.EffectiveTo.After(price.EffectiveFrom) {
+		return ErrInvalidPriceRange
+	}
+	if _, ok := r.store.products[price.ProductID]; !ok {
+		if _, ok := r.store.deletedProducts[price.ProductID]; !ok {
+			return ErrInvalidProductFK
+		}
+	}
+	return nil
+}
+
+// clonePrice copies pointer fields so callers never share memory with the store
+func clonePrice(p entity.ProductPrice) entity.ProductPrice {
+	p.EffectiveTo = utcTime(p.EffectiveTo)
+	return p
+}
+
+// utcTime copies t in UTC with microsecond precision like a TIMESTAMP column
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC().Truncate(time.Microsecond)
+	return &utc
+}
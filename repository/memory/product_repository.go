@@ -0,0 +1,373 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// ProductRepository - in-memory implementation of ProductRepositoryInterface
+type ProductRepository struct {
+	access
+}
+
+// NewProductRepository - constructor untuk in-memory ProductRepository
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{access: access{store: store}}
+}
+
+// GetAll - ambil semua produk sesuai filter, urut berdasarkan ID
+func (r *ProductRepository) GetAll(filter entity.ProductFilter) ([]entity.Product, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.list(filter), nil
+}
+
+// list returns matching products ordered by ID. Caller must hold the store lock.
+func (r *ProductRepository) list(filter entity.ProductFilter) []entity.Product {
+	categories := map[int]bool{filter.CategoryID: true}
+	if filter.CategoryID != 0 && filter.IncludeSubcategories {
+		categories = r.store.subtree(filter.CategoryID)
+	}
+
+	var products []entity.Product
+	for _, p := range r.store.products {
+		if filter.Uncategorized && p.CategoryID != nil {
+			continue
+		}
+		if filter.CategoryID != 0 && (p.CategoryID == nil || !categories[*p.CategoryID]) {
+			continue
+		}
+		products = append(products, cloneProduct(p))
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].ID < products[j].ID
+	})
+
+	return products
+}
+
+// GetByID - ambil produk berdasarkan ID
+func (r *ProductRepository) GetByID(id int) (entity.Product, error) {
+	r.rlock()
+	defer r.runlock()
+
+	p, ok := r.store.products[id]
+	if !ok {
+		return entity.Product{}, repository.ErrProductNotFound
+	}
+	return cloneProduct(p), nil
+}
+
+// Create - tambah produk baru
+func (r *ProductRepository) Create(product entity.Product) (entity.Product, error) {
+	r.lock()
+	defer r.unlock()
+
+	if err := r.validate(product); err != nil {
+		return entity.Product{}, err
+	}
+	if err := r.checkConflict(0, product); err != nil {
+		return entity.Product{}, err
+	}
+
+	product.ID = r.store.nextProductID
+	product.Category = nil
+	product.Image = nil // gambar hanya lewat SetImage
+	normalizeCurrency(&product)
+	r.store.nextProductID++
+	r.store.products[product.ID] = cloneProduct(product)
+
+	return product, nil
+}
+
+// Update - update produk
+func (r *ProductRepository) Update(id int, product entity.Product) (entity.Product, error) {
+	r.lock()
+	defer r.unlock()
+
+	current, ok := r.store.products[id]
+	if !ok {
+		return entity.Product{}, repository.ErrProductNotFound
+	}
+	if err := r.validate(product); err != nil {
+		return entity.Product{}, err
+	}
+	if err := r.checkConflict(id, product); err != nil {
+		return entity.Product{}, err
+	}
+
+	product.ID = id
+	product.Category = nil
+	product.Image = cloneProduct(current).Image // gambar hanya lewat SetImage
+	normalizeCurrency(&product)
+	r.store.products[id] = cloneProduct(product)
+
+	return product, nil
+}
+
+// Delete - hapus produk
+func (r *ProductRepository) Delete(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.products[id]; !ok {
+		return repository.ErrProductNotFound
+	}
+	// bundle_components.product_id ON DELETE RESTRICT
+	if r.store.isComponent(id, nil) {
+		return ErrComponentRestrict
+	}
+	delete(r.store.products, id)
+
+	// bundle_components.bundle_id dan product_units.product_id ON DELETE CASCADE
+	delete(r.store.bundleComponents, id)
+	delete(r.store.productUnits, id)
+
+	// product_prices.product_id ON DELETE CASCADE
+	for priceID, p := range r.store.prices {
+		if p.ProductID == id {
+			delete(r.store.prices, priceID)
+		}
+	}
+
+	// product_variants.product_id ON DELETE CASCADE
+	for variantID, v := range r.store.variants {
+		if v.ProductID == id {
+			r.store.deleteVariant(variantID)
+		}
+	}
+
+	// modifier_groups.product_id ON DELETE CASCADE
+	r.store.deleteModifierGroups(func(g entity.ModifierGroup) bool {
+		return g.ProductID != nil && *g.ProductID == id
+	})
+
+	// promotions.product_id ON DELETE CASCADE
+	r.store.deletePromotions(func(p entity.Promotion) bool {
+		return p.ProductID != nil && *p.ProductID == id
+	})
+
+	// transaction_details.product_id ON DELETE SET NULL
+	r.store.updateDetails(func(d *entity.TransactionDetail) bool {
+		if d.ProductID != nil && *d.ProductID == id {
+			d.ProductID = nil
+			return true
+		}
+		return false
+	})
+
+	return nil
+}
+
+// ReassignCategory - pindahkan semua produk aktif dari satu kategori ke kategori lain (nil = tanpa kategori)
+func (r *ProductRepository) ReassignCategory(fromCategoryID int, toCategoryID *int) (int, error) {
+	r.lock()
+	defer r.unlock()
+
+	if toCategoryID != nil {
+		if _, ok := r.store.categories[*toCategoryID]; !ok {
+			return 0, ErrInvalidFK
+		}
+	}
+
+	// Unique index (category_id, LOWER(nama)) dicek sebelum ada yang dipindah
+	for _, p := range r.store.products {
+		if p.CategoryID == nil || *p.CategoryID != fromCategoryID {
+			continue
+		}
+		moving := p
+		moving.CategoryID = toCategoryID
+		if err := r.checkConflict(p.ID, moving); err != nil {
+			return 0, err
+		}
+	}
+
+	moved := 0
+	for id, p := range r.store.products {
+		if p.CategoryID != nil && *p.CategoryID == fromCategoryID {
+			p.CategoryID = toCategoryID
+			r.store.products[id] = cloneProduct(p)
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// SoftDeleteByCategory - tandai semua produk aktif dalam kategori sebagai terhapus
+func (r *ProductRepository) SoftDeleteByCategory(categoryID int) (int, error) {
+	r.lock()
+	defer r.unlock()
+
+	deleted := 0
+	for id, p := range r.store.products {
+		if p.CategoryID != nil && *p.CategoryID == categoryID {
+			r.store.deletedProducts[id] = p
+			delete(r.store.products, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// AdjustStock - tambah atau kurangi stok produk, stok tidak boleh minus
+func (r *ProductRepository) AdjustStock(id int, delta entity.Quantity) (entity.Product, error) {
+	r.lock()
+	defer r.unlock()
+
+	p, ok := r.store.products[id]
+	if !ok {
+		return entity.Product{}, repository.ErrProductNotFound
+	}
+	if p.Stock == nil {
+		return entity.Product{}, repository.ErrStockNotTracked
+	}
+	if *p.Stock+delta < 0 {
+		return entity.Product{}, repository.ErrInsufficientStock
+	}
+	stock := *p.Stock + delta
+	p.Stock = &stock
+	r.store.products[id] = p
+	return cloneProduct(p), nil
+}
+
+// SetImage - ganti gambar produk, nil menghapus gambar
+func (r *ProductRepository) SetImage(id int, image *entity.ProductImage) error {
+	r.lock()
+	defer r.unlock()
+
+	p, ok := r.store.products[id]
+	if !ok {
+		return repository.ErrProductNotFound
+	}
+	p.Image = nil
+	if image != nil {
+		stored := entity.ProductImage{Key: image.Key, ThumbnailKey: image.ThumbnailKey}
+		p.Image = &stored
+	}
+	r.store.products[id] = p
+	return nil
+}
+
+// validate mirrors the column and foreign key constraints of the products table.
+// Caller must hold the store lock.
+func (r *ProductRepository) validate(product entity.Product) error {
+	if utf8.RuneCountInString(product.Nama) > 100 {
+		return ErrNameTooLong
+	}
+	if product.Harga.IsNegative() || (product.HargaBeli != nil && product.HargaBeli.IsNegative()) {
+		return ErrNegativeHarga
+	}
+	if t := product.Type; t != "" && t != entity.ProductTypeSingle && t != entity.ProductTypeBundle {
+		return ErrInvalidProductType
+	}
+	if product.Stock != nil && *product.Stock < 0 {
+		return ErrNegativeStock
+	}
+	for _, unit := range []string{product.Unit, product.PurchaseUnit} {
+		if _, ok := r.store.units[unit]; unit != "" && !ok {
+			return ErrInvalidUnitFK
+		}
+	}
+	if product.CategoryID == nil {
+		return nil
+	}
+	if _, ok := r.store.categories[*product.CategoryID]; !ok {
+		return ErrInvalidFK
+	}
+	return nil
+}
+
+// checkConflict mirrors the partial unique index on (category_id, LOWER(nama))
+// of active products. Caller must hold the store lock.
+func (r *ProductRepository) checkConflict(id int, product entity.Product) error {
+	for _, p := range r.store.products {
+		if p.ID != id && sameNullableID(p.CategoryID, product.CategoryID) && strings.EqualFold(p.Nama, product.Nama) {
+			return &repository.ConflictError{Resource: "product", Name: product.Nama, Scope: "category", Existing: cloneProduct(p)}
+		}
+	}
+	return nil
+}
+
+// GetAllWithCategory - ambil semua produk sesuai filter beserta kategorinya
+func (r *ProductRepository) GetAllWithCategory(filter entity.ProductFilter) ([]entity.Product, error) {
+	r.rlock()
+	defer r.runlock()
+
+	products := r.list(filter)
+	for i := range products {
+		products[i].Category = r.categoryOf(products[i])
+	}
+	return products, nil
+}
+
+// GetByIDWithCategory - ambil produk berdasarkan ID beserta kategorinya
+func (r *ProductRepository) GetByIDWithCategory(id int) (entity.Product, error) {
+	r.rlock()
+	defer r.runlock()
+
+	p, ok := r.store.products[id]
+	if !ok {
+		return entity.Product{}, repository.ErrProductNotFound
+	}
+	p = cloneProduct(p)
+	p.Category = r.categoryOf(p)
+	return p, nil
+}
+
+// categoryOf returns a copy of the product's category, like a LEFT JOIN.
+// Caller must hold the store lock.
+func (r *ProductRepository) categoryOf(p entity.Product) *entity.Category {
+	if p.CategoryID == nil {
+		return nil
+	}
+	c, ok := r.store.categories[*p.CategoryID]
+	if !ok {
+		return nil
+	}
+	c = cloneCategory(c)
+	return &c
+}
+
+// cloneProduct copies pointer fields so callers never share memory with the store
+func cloneProduct(p entity.Product) entity.Product {
+	if p.CategoryID != nil {
+		id := *p.CategoryID
+		p.CategoryID = &id
+	}
+	if p.HargaBeli != nil {
+		cost := *p.HargaBeli
+		p.HargaBeli = &cost
+	}
+	if p.Stock != nil {
+		stock := *p.Stock
+		p.Stock = &stock
+	}
+	if p.Image != nil {
+		image := *p.Image
+		p.Image = &image
+	}
+	// Varian, komponen bundle dan satuan lain disimpan di tabelnya sendiri
+	p.Variants, p.Components, p.Units = nil, nil, nil
+	p.ClearHargaBeli = false
+	return p
+}
+
+// normalizeCurrency fills the currency and type like the database defaults, cost follows harga
+func normalizeCurrency(p *entity.Product) {
+	if p.Type == "" {
+		p.Type = entity.ProductTypeSingle
+	}
+	if p.Unit == "" {
+		p.Unit = entity.DefaultUnit
+	}
+	p.Harga.Currency = p.Harga.Cur()
+	if p.HargaBeli != nil {
+		cost := entity.NewMoney(p.HargaBeli.Amount, p.Harga.Currency)
+		p.HargaBeli = &cost
+	}
+}
@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// PromotionRepository - in-memory implementation of PromotionRepositoryInterface
+type PromotionRepository struct {
+	access
+}
+
+// NewPromotionRepository - constructor untuk in-memory PromotionRepository
+func NewPromotionRepository(store *Store) *PromotionRepository {
+	return &PromotionRepository{access: access{store: store}}
+}
+
+// GetAll - semua promosi, urut berdasarkan ID
+func (r *PromotionRepository) GetAll() ([]entity.Promotion, error) {
+	r.rlock()
+	defer r.runlock()
+
+	var promotions []entity.Promotion
+	for _, p := range r.store.promotions {
+		promotions = append(promotions, clonePromotion(p))
+	}
+	sort.Slice(promotions, func(i, j int) bool {
+		return promotions[i].ID < promotions[j].ID
+	})
+	return promotions, nil
+}
+
+// GetActive - promosi yang berlaku pada waktu at menurut starts_at dan ends_at, urut
+// berdasarkan priority (tertinggi dulu) lalu ID. Hari berlaku dicek oleh service.
+func (r *PromotionRepository) GetActive(at time.Time) ([]entity.Promotion, error) {
+	r.rlock()
+	defer r.runlock()
+
+	var promotions []entity.Promotion
+	for _, p := range r.store.promotions {
+		if p.StartsAt != nil && at.Before(*p.StartsAt) {
+			continue
+		}
+		if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+			continue
+		}
+		promotions = append(promotions, clonePromotion(p))
+	}
+	sort.Slice(promotions, func(i, j int) bool {
+		if promotions[i].Priority != promotions[j].Priority {
+			return promotions[i].Priority > promotions[j].Priority
+		}
+		return promotions[i].ID < promotions[j].ID
+	})
+	return promotions, nil
+}
+
+// GetByID - ambil promosi berdasarkan ID
+func (r *PromotionRepository) GetByID(id int) (entity.Promotion, error) {
+	r.rlock()
+	defer r.runlock()
+
+	p, ok := r.store.promotions[id]
+	if !ok {
+		return entity.Promotion{}, repository.ErrPromotionNotFound
+	}
+	return clonePromotion(p), nil
+}
+
+// Create - tambah promosi baru
+func (r *PromotionRepository) Create(promotion entity.Promotion) (entity.Promotion, error) {
+	r.lock()
+	defer r.unlock()
+
+	promotion = normalizePromotion(promotion)
+	if err := r.validate(promotion); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion.ID = r.store.nextPromoID
+	r.store.nextPromoID++
+	r.store.promotions[promotion.ID] = promotion
+	return clonePromotion(promotion), nil
+}
+
+// Update - ubah promosi
+func (r *PromotionRepository) Update(id int, promotion entity.Promotion) (entity.Promotion, error) {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.promotions[id]; !ok {
+		return entity.Promotion{}, repository.ErrPromotionNotFound
+	}
+	promotion = normalizePromotion(promotion)
+	if err := r.validate(promotion); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	promotion.ID = id
+	r.store.promotions[id] = promotion
+	return clonePromotion(promotion), nil
+}
+
+// Delete - hapus promosi, transaksi lama tetap menyimpan nama dan potongannya
+func (r *PromotionRepository) Delete(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.promotions[id]; !ok {
+		return repository.ErrPromotionNotFound
+	}
+	r.store.deletePromotions(func(p entity.Promotion) bool { return p.ID == id })
+	return nil
+}
+
+// validate mirrors the column, check and foreign key constraints of promotions.
+// Caller must hold the store lock.
+func (r *PromotionRepository) validate(p entity.Promotion) error {
+	if utf8.RuneCountInString(p.Nama) > 100 {
+		return ErrNameTooLong
+	}
+	switch p.Type {
+	case entity.PromotionPercentage, entity.PromotionFixed, entity.PromotionBOGO, entity.PromotionThreshold:
+	default:
+		return ErrInvalidPromotion
+	}
+	switch {
+	case p.Scope != entity.PromotionScopeProduct && p.Scope != entity.PromotionScopeCategory && p.Scope != entity.PromotionScopeCart,
+		(p.Scope == entity.PromotionScopeProduct) != (p.ProductID != nil),
+		(p.Scope == entity.PromotionScopeCategory) != (p.CategoryID != nil),
+		p.Percent < 0 || p.Percent > 10000,
+		p.Amount != nil && p.Amount.Amount <= 0,
+		p.MinSubtotal != nil && p.MinSubtotal.IsNegative(),
+		p.BuyQuantity < 0 || p.GetQuantity < 0:
+		return ErrInvalidPromotion
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return ErrInvalidPromotionRange
+	}
+	if p.ProductID != nil {
+		if _, ok := r.store.products[*p.ProductID]; !ok {
+			if _, ok := r.store.deletedProducts[*p.ProductID]; !ok {
+				return ErrInvalidProductFK
+			}
+		}
+	}
+	if p.CategoryID != nil {
+		if _, ok := r.store.categories[*p.CategoryID]; !ok {
+			return ErrInvalidFK
+		}
+	}
+	return nil
+}
+
+// normalizePromotion stores the promotion like the promotions table does: one currency
+// for amount and min_subtotal, UTC timestamps and days as a sorted set
+func normalizePromotion(p entity.Promotion) entity.Promotion {
+	p = clonePromotion(p)
+	currency := entity.DefaultCurrency
+	if p.Amount != nil {
+		currency = p.Amount.Cur()
+	} else if p.MinSubtotal != nil {
+		currency = p.MinSubtotal.Cur()
+	}
+	if p.Amount != nil {
+		p.Amount.Currency = currency
+	}
+	if p.MinSubtotal != nil {
+		p.MinSubtotal.Currency = currency
+	}
+	seen := make(map[int]bool)
+	var days []int
+	for _, day := range p.Days {
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Ints(days)
+	p.Days = days
+	return p
+}
+
+// clonePromotion copies pointer fields so callers never share memory with the store
+func clonePromotion(p entity.Promotion) entity.Promotion {
+	if p.ProductID != nil {
+		id := *p.ProductID
+		p.ProductID = &id
+	}
+	if p.CategoryID != nil {
+		id := *p.CategoryID
+		p.CategoryID = &id
+	}
+	if p.Amount != nil {
+		amount := *p.Amount
+		p.Amount = &amount
+	}
+	if p.MinSubtotal != nil {
+		min := *p.MinSubtotal
+		p.MinSubtotal = &min
+	}
+	p.StartsAt = utcTime(p.StartsAt)
+	p.EndsAt = utcTime(p.EndsAt)
+	if p.Days != nil {
+		p.Days = append([]int(nil), p.Days...)
+	}
+	return p
+}
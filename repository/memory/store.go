@@ -0,0 +1,392 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+
+	"kasir-api/entity"
+)
+
+// Errors mirroring the constraints of the PostgreSQL schema
+var (
+	ErrNameTooLong   = errors.New("value too long for type character varying(100)")
+	ErrNegativeHarga = errors.New("harga must not be negative")
+	ErrInvalidFK     = errors.New("category does not exist (foreign key violation)")
+	ErrSelfParent    = errors.New("category cannot be its own parent (check constraint violation)")
+
+	ErrInvalidQuantity  = errors.New("quantity must be positive")
+	ErrInvalidProductFK = errors.New("product does not exist (foreign key violation)")
+
+	ErrDuplicatePriceStart = errors.New("price with the same effective_from already exists (unique violation)")
+	ErrInvalidPriceRange   = errors.New("effective_to must be after effective_from (check constraint violation)")
+
+	ErrSKUTooLong       = errors.New("value too long for type character varying(64)")
+	ErrNegativeStock    = errors.New("stock must not be negative (check constraint violation)")
+	ErrInvalidVariantFK = errors.New("variant does not exist (foreign key violation)")
+
+	ErrInvalidModifierTarget  = errors.New("modifier group must belong to exactly one product or category (check constraint violation)")
+	ErrInvalidModifierRange   = errors.New("min_select must not be negative and max_select must be 0 or at least min_select (check constraint violation)")
+	ErrInvalidModifierGroupFK = errors.New("modifier group does not exist (foreign key violation)")
+	ErrInvalidModifierFK      = errors.New("modifier does not exist (foreign key violation)")
+
+	ErrInvalidProductType = errors.New("type must be single or bundle (check constraint violation)")
+	ErrComponentRestrict  = errors.New("product or variant is still a bundle component (foreign key violation)")
+	ErrInvalidComponent   = errors.New("bundle component must be another product with a positive quantity (check constraint violation)")
+	ErrDuplicateComponent = errors.New("bundle already contains this component (unique violation)")
+
+	ErrInvalidUnitFK = errors.New("unit does not exist (foreign key violation)")
+	ErrInvalidUnit   = errors.New("unit code must be lower case and at most 10 characters (check constraint violation)")
+	ErrInvalidFactor = errors.New("unit factor must be positive (check constraint violation)")
+	ErrDuplicateUnit = errors.New("product already has this unit (unique violation)")
+
+	ErrInvalidPromotion      = errors.New("promotion type, scope, target or values are invalid (check constraint violation)")
+	ErrInvalidPromotionRange = errors.New("ends_at must be after starts_at (check constraint violation)")
+	ErrInvalidPromotionFK    = errors.New("promotion does not exist (foreign key violation)")
+	ErrInvalidDiscount       = errors.New("discount must be between 0 and the line subtotal (check constraint violation)")
+
+	ErrInvalidVoucher       = errors.New("voucher code, type or values are invalid (check constraint violation)")
+	ErrInvalidVoucherFK     = errors.New("voucher does not exist (foreign key violation)")
+	ErrInvalidVoucherCode   = errors.New("value too long for type character varying(32)")
+	ErrInvalidVoucherRange  = errors.New("expires_at must be after starts_at (check constraint violation)")
+	ErrInvalidTransactionFK = errors.New("transaction does not exist (foreign key violation)")
+
+	ErrInvalidTaxRate     = errors.New("tax rate must be between 0 and 100 and compound only for exclusive taxes (check constraint violation)")
+	ErrInvalidTaxRateFK   = errors.New("tax rate does not exist (foreign key violation)")
+	ErrDuplicateExemption = errors.New("tax rate already exempts this category (unique violation)")
+)
+
+// defaultUnits - satuan yang diisi oleh migrasi 013
+var defaultUnits = []entity.Unit{
+	{Code: "pcs", Nama: "Pieces"},
+	{Code: "dus", Nama: "Dus"},
+	{Code: "pak", Nama: "Pak"},
+	{Code: "kg", Nama: "Kilogram", AllowDecimal: true},
+	{Code: "g", Nama: "Gram", AllowDecimal: true},
+	{Code: "l", Nama: "Liter", AllowDecimal: true},
+	{Code: "ml", Nama: "Mililiter", AllowDecimal: true},
+}
+
+// Store holds all in-memory tables behind a single lock so that
+// cross-table rules (ON DELETE SET NULL, foreign keys) stay consistent
+type Store struct {
+	mu sync.RWMutex
+
+	categories      map[int]entity.Category
+	products        map[int]entity.Product
+	deletedProducts map[int]entity.Product // soft delete, tidak terlihat dari repository
+	transactions    map[int]entity.Transaction
+	prices          map[int]entity.ProductPrice
+	variants        map[int]entity.ProductVariant
+	modifierGroups  map[int]entity.ModifierGroup // tanpa Modifiers, pilihan disimpan di modifiers
+	modifiers       map[int]entity.Modifier
+	// bundleComponents - komponen per bundle ID, slice tidak pernah diubah di tempat
+	bundleComponents map[int][]entity.BundleComponent
+	units            map[string]entity.Unit
+	// productUnits - satuan lain per product ID, slice tidak pernah diubah di tempat
+	productUnits map[int][]entity.UnitConversion
+	// promotions - Days tidak pernah diubah di tempat
+	promotions  map[int]entity.Promotion
+	vouchers    map[int]entity.Voucher
+	redemptions []entity.VoucherRedemption // urut berdasarkan ID, tidak pernah diubah di tempat
+	// taxRates - ExemptCategoryIDs tidak pernah diubah di tempat
+	taxRates       map[int]entity.TaxRate
+	nextCategoryID int
+	nextProductID  int
+	nextTxID       int
+	nextTxDetailID int
+	nextPriceID    int
+	nextVariantID  int
+	nextGroupID    int
+	nextModifierID int
+	nextPromoID    int
+	nextVoucherID  int
+	nextRedeemID   int
+	nextTaxRateID  int
+}
+
+// maxCategoryDepth - batas kedalaman breadcrumb, sama dengan batas CTE rekursif di SQL
+const maxCategoryDepth = 100
+
+// NewStore creates an empty in-memory store, ID sequences start at 1 like SERIAL.
+// Satuan bawaan sudah terisi seperti setelah migrasi.
+func NewStore() *Store {
+	units := make(map[string]entity.Unit, len(defaultUnits))
+	for _, u := range defaultUnits {
+		units[u.Code] = u
+	}
+	return &Store{
+		units:            units,
+		taxRates:         make(map[int]entity.TaxRate),
+		productUnits:     make(map[int][]entity.UnitConversion),
+		categories:       make(map[int]entity.Category),
+		products:         make(map[int]entity.Product),
+		deletedProducts:  make(map[int]entity.Product),
+		transactions:     make(map[int]entity.Transaction),
+		prices:           make(map[int]entity.ProductPrice),
+		variants:         make(map[int]entity.ProductVariant),
+		modifierGroups:   make(map[int]entity.ModifierGroup),
+		modifiers:        make(map[int]entity.Modifier),
+		bundleComponents: make(map[int][]entity.BundleComponent),
+		promotions:       make(map[int]entity.Promotion),
+		vouchers:         make(map[int]entity.Voucher),
+		nextCategoryID:   1,
+		nextProductID:    1,
+		nextTxID:         1,
+		nextTxDetailID:   1,
+		nextPriceID:      1,
+		nextVariantID:    1,
+		nextGroupID:      1,
+		nextModifierID:   1,
+		nextPromoID:      1,
+		nextVoucherID:    1,
+		nextRedeemID:     1,
+		nextTaxRateID:    1,
+	}
+}
+
+// snapshot copies every table so a failed transaction can be undone.
+// Caller must hold the write lock.
+func (s *Store) snapshot() *Store {
+	snap := &Store{
+		categories:       make(map[int]entity.Category, len(s.categories)),
+		products:         make(map[int]entity.Product, len(s.products)),
+		deletedProducts:  make(map[int]entity.Product, len(s.deletedProducts)),
+		transactions:     make(map[int]entity.Transaction, len(s.transactions)),
+		prices:           make(map[int]entity.ProductPrice, len(s.prices)),
+		variants:         make(map[int]entity.ProductVariant, len(s.variants)),
+		modifierGroups:   make(map[int]entity.ModifierGroup, len(s.modifierGroups)),
+		modifiers:        make(map[int]entity.Modifier, len(s.modifiers)),
+		bundleComponents: make(map[int][]entity.BundleComponent, len(s.bundleComponents)),
+		units:            make(map[string]entity.Unit, len(s.units)),
+		productUnits:     make(map[int][]entity.UnitConversion, len(s.productUnits)),
+		promotions:       make(map[int]entity.Promotion, len(s.promotions)),
+		vouchers:         make(map[int]entity.Voucher, len(s.vouchers)),
+		redemptions:      s.redemptions,
+		taxRates:         make(map[int]entity.TaxRate, len(s.taxRates)),
+		nextCategoryID:   s.nextCategoryID,
+		nextProductID:    s.nextProductID,
+		nextTxID:         s.nextTxID,
+		nextTxDetailID:   s.nextTxDetailID,
+		nextPriceID:      s.nextPriceID,
+		nextVariantID:    s.nextVariantID,
+		nextGroupID:      s.nextGroupID,
+		nextModifierID:   s.nextModifierID,
+		nextPromoID:      s.nextPromoID,
+		nextVoucherID:    s.nextVoucherID,
+		nextRedeemID:     s.nextRedeemID,
+		nextTaxRateID:    s.nextTaxRateID,
+	}
+	for id, c := range s.categories {
+		snap.categories[id] = c
+	}
+	for id, p := range s.products {
+		snap.products[id] = p
+	}
+	for id, p := range s.deletedProducts {
+		snap.deletedProducts[id] = p
+	}
+	// Detail transaksi tidak pernah diubah di tempat (copy-on-write), cukup salin map
+	for id, t := range s.transactions {
+		snap.transactions[id] = t
+	}
+	for id, p := range s.prices {
+		snap.prices[id] = p
+	}
+	// Options varian tidak pernah diubah di tempat, cukup salin map
+	for id, v := range s.variants {
+		snap.variants[id] = v
+	}
+	for id, g := range s.modifierGroups {
+		snap.modifierGroups[id] = g
+	}
+	for id, m := range s.modifiers {
+		snap.modifiers[id] = m
+	}
+	for id, components := range s.bundleComponents {
+		snap.bundleComponents[id] = components
+	}
+	for code, u := range s.units {
+		snap.units[code] = u
+	}
+	for id, conversions := range s.productUnits {
+		snap.productUnits[id] = conversions
+	}
+	for id, p := range s.promotions {
+		snap.promotions[id] = p
+	}
+	for id, v := range s.vouchers {
+		snap.vouchers[id] = v
+	}
+	for id, t := range s.taxRates {
+		snap.taxRates[id] = t
+	}
+	return snap
+}
+
+// restore puts the tables back to a snapshot. Caller must hold the write lock.
+func (s *Store) restore(snap *Store) {
+	s.categories = snap.categories
+	s.products = snap.products
+	s.deletedProducts = snap.deletedProducts
+	s.transactions = snap.transactions
+	s.prices = snap.prices
+	s.variants = snap.variants
+	s.modifierGroups = snap.modifierGroups
+	s.modifiers = snap.modifiers
+	s.bundleComponents = snap.bundleComponents
+	s.units = snap.units
+	s.productUnits = snap.productUnits
+	s.promotions = snap.promotions
+	s.vouchers = snap.vouchers
+	s.redemptions = snap.redemptions
+	s.taxRates = snap.taxRates
+	s.nextCategoryID = snap.nextCategoryID
+	s.nextProductID = snap.nextProductID
+	s.nextTxID = snap.nextTxID
+	s.nextTxDetailID = snap.nextTxDetailID
+	s.nextPriceID = snap.nextPriceID
+	s.nextVariantID = snap.nextVariantID
+	s.nextGroupID = snap.nextGroupID
+	s.nextModifierID = snap.nextModifierID
+	s.nextPromoID = snap.nextPromoID
+	s.nextVoucherID = snap.nextVoucherID
+	s.nextRedeemID = snap.nextRedeemID
+	s.nextTaxRateID = snap.nextTaxRateID
+}
+
+// updateDetails applies fn to every sale line copy-on-write, fn reports whether
+// it changed the line. Caller must hold the write lock.
+func (s *Store) updateDetails(fn func(d *entity.TransactionDetail) bool) {
+	for id, t := range s.transactions {
+		clone := cloneTransaction(t)
+		changed := false
+		for i := range clone.Details {
+			if fn(&clone.Details[i]) {
+				changed = true
+			}
+		}
+		if changed {
+			s.transactions[id] = clone
+		}
+	}
+}
+
+// deleteVariant removes a variant, sale lines keep their history with variant_id NULL.
+// Caller must hold the write lock.
+func (s *Store) deleteVariant(id int) {
+	delete(s.variants, id)
+	s.updateDetails(func(d *entity.TransactionDetail) bool {
+		if d.VariantID != nil && *d.VariantID == id {
+			d.VariantID = nil
+			return true
+		}
+		return false
+	})
+}
+
+// deleteModifierGroups removes the modifier groups matching fn together with their
+// modifiers (ON DELETE CASCADE). Caller must hold the write lock.
+func (s *Store) deleteModifierGroups(fn func(g entity.ModifierGroup) bool) {
+	for id, g := range s.modifierGroups {
+		if !fn(g) {
+			continue
+		}
+		delete(s.modifierGroups, id)
+		for modifierID, m := range s.modifiers {
+			if m.GroupID == id {
+				s.deleteModifier(modifierID)
+			}
+		}
+	}
+}
+
+// deleteModifier removes a modifier, sale lines keep their copy with modifier_id NULL.
+// Caller must hold the write lock.
+func (s *Store) deleteModifier(id int) {
+	delete(s.modifiers, id)
+	s.updateDetails(func(d *entity.TransactionDetail) bool {
+		changed := false
+		for i, m := range d.Modifiers {
+			if m.ModifierID != nil && *m.ModifierID == id {
+				d.Modifiers[i].ModifierID = nil
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+// deletePromotions removes the promotions matching fn, transactions keep their discount
+// lines with promotion_id NULL (ON DELETE SET NULL). Caller must hold the write lock.
+func (s *Store) deletePromotions(fn func(p entity.Promotion) bool) {
+	for id, p := range s.promotions {
+		if !fn(p) {
+			continue
+		}
+		delete(s.promotions, id)
+		for txID, t := range s.transactions {
+			clone := cloneTransaction(t)
+			changed := false
+			for i, d := range clone.Discounts {
+				if d.PromotionID != nil && *d.PromotionID == id {
+					clone.Discounts[i].PromotionID = nil
+					changed = true
+				}
+			}
+			if changed {
+				s.transactions[txID] = clone
+			}
+		}
+	}
+}
+
+// isComponent reports whether a bundle uses the product, or only that variant of it
+// when variantID is set. Caller must hold the store lock.
+func (s *Store) isComponent(productID int, variantID *int) bool {
+	for _, components := range s.bundleComponents {
+		for _, c := range components {
+			if c.ProductID != productID {
+				continue
+			}
+			if variantID == nil || (c.VariantID != nil && *c.VariantID == *variantID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// access guards table access. Repositories handed out by TxManager already
+// run under the store write lock, so they must not lock again.
+type access struct {
+	store    *Store
+	inTx     bool
+	readOnly bool // di dalam WithinReadTx, store hanya dikunci untuk dibaca
+}
+
+func (a access) rlock() {
+	if !a.inTx {
+		a.store.mu.RLock()
+	}
+}
+
+func (a access) runlock() {
+	if !a.inTx {
+		a.store.mu.RUnlock()
+	}
+}
+
+func (a access) lock() {
+	if a.readOnly {
+		panic("memory: write inside WithinReadTx")
+	}
+	if !a.inTx {
+		a.store.mu.Lock()
+	}
+}
+
+func (a access) unlock() {
+	if !a.inTx {
+		a.store.mu.Unlock()
+	}
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// TaxRateRepository - in-memory implementation of TaxRateRepositoryInterface
+type TaxRateRepository struct {
+	access
+}
+
+// NewTaxRateRepository - constructor untuk in-memory TaxRateRepository
+func NewTaxRateRepository(store *Store) *TaxRateRepository {
+	return &TaxRateRepository{access: access{store: store}}
+}
+
+// GetAll - semua tarif pajak dalam urutan hitung (position, lalu ID)
+func (r *TaxRateRepository) GetAll() ([]entity.TaxRate, error) {
+	r.rlock()
+	defer r.runlock()
+
+	var rates []entity.TaxRate
+	for _, t := range r.store.taxRates {
+		rates = append(rates, cloneTaxRate(t))
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Position != rates[j].Position {
+			return rates[i].Position < rates[j].Position
+		}
+		return rates[i].ID < rates[j].ID
+	})
+	return rates, nil
+}
+
+// GetByID - ambil tarif pajak beserta kategori yang dibebaskan
+func (r *TaxRateRepository) GetByID(id int) (entity.TaxRate, error) {
+	r.rlock()
+	defer r.runlock()
+
+	t, ok := r.store.taxRates[id]
+	if !ok {
+		return entity.TaxRate{}, repository.ErrTaxRateNotFound
+	}
+	return cloneTaxRate(t), nil
+}
+
+// Create - tambah tarif pajak, nama unik
+func (r *TaxRateRepository) Create(rate entity.TaxRate) (entity.TaxRate, error) {
+	r.lock()
+	defer r.unlock()
+
+	rate = cloneTaxRate(rate)
+	if err := r.validate(rate); err != nil {
+		return entity.TaxRate{}, err
+	}
+	if existing, ok := r.byNama(rate.Nama); ok {
+		return entity.TaxRate{}, &repository.ConflictError{Resource: "tax rate", Name: rate.Nama, Scope: "store", Existing: cloneTaxRate(existing)}
+	}
+
+	rate.ID = r.store.nextTaxRateID
+	r.store.nextTaxRateID++
+	r.store.taxRates[rate.ID] = rate
+	return cloneTaxRate(rate), nil
+}
+
+// Update - ubah tarif pajak dan ganti daftar kategori yang dibebaskan
+func (r *TaxRateRepository) Update(id int, rate entity.TaxRate) (entity.TaxRate, error) {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.taxRates[id]; !ok {
+		return entity.TaxRate{}, repository.ErrTaxRateNotFound
+	}
+	rate = cloneTaxRate(rate)
+	rate.ID = id
+	if err := r.validate(rate); err != nil {
+		return entity.TaxRate{}, err
+	}
+	if existing, ok := r.byNama(rate.Nama); ok && existing.ID != id {
+		return entity.TaxRate{}, &repository.ConflictError{Resource: "tax rate", Name: rate.Nama, Scope: "store", Existing: cloneTaxRate(existing)}
+	}
+
+	r.store.taxRates[id] = rate
+	return cloneTaxRate(rate), nil
+}
+
+// Delete - hapus tarif pajak, pajak di transaksi lama tetap ada dengan tax_rate_id NULL
+func (r *TaxRateRepository) Delete(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.taxRates[id]; !ok {
+		return repository.ErrTaxRateNotFound
+	}
+	delete(r.store.taxRates, id)
+
+	// transaction_taxes.tax_rate_id ON DELETE SET NULL
+	for txID, t := range r.store.transactions {
+		clone := cloneTransaction(t)
+		changed := false
+		for i, tax := range clone.Taxes {
+			if tax.TaxRateID != nil && *tax.TaxRateID == id {
+				clone.Taxes[i].TaxRateID = nil
+				changed = true
+			}
+		}
+		if changed {
+			r.store.transactions[txID] = clone
+		}
+	}
+	return nil
+}
+
+// byNama finds a tax rate by its exact name. Caller must hold the store lock.
+func (r *TaxRateRepository) byNama(nama string) (entity.TaxRate, bool) {
+	for _, t := range r.store.taxRates {
+		if t.Nama == nama {
+			return t, true
+		}
+	}
+	return entity.TaxRate{}, false
+}
+
+// validate mirrors the CHECK, foreign key and primary key constraints of
+// tax_rates and tax_rate_exemptions. Caller must hold the store lock.
+func (r *TaxRateRepository) validate(t entity.TaxRate) error {
+	if utf8.RuneCountInString(t.Nama) > 100 {
+		return ErrNameTooLong
+	}
+	if t.Rate <= 0 || t.Rate > 10000 || (t.Inclusive && t.Compound) {
+		return ErrInvalidTaxRate
+	}
+	seen := make(map[int]bool, len(t.ExemptCategoryIDs))
+	for _, categoryID := range t.ExemptCategoryIDs {
+		if _, ok := r.store.categories[categoryID]; !ok {
+			return ErrInvalidFK
+		}
+		if seen[categoryID] {
+			return ErrDuplicateExemption
+		}
+		seen[categoryID] = true
+	}
+	return nil
+}
+
+// cloneTaxRate copies the exemption list sorted by category ID like the SQL query,
+// never nil so it encodes as []
+func cloneTaxRate(t entity.TaxRate) entity.TaxRate {
+	exempt := make([]int, len(t.ExemptCategoryIDs))
+	copy(exempt, t.ExemptCategoryIDs)
+	sort.Ints(exempt)
+	t.ExemptCategoryIDs = exempt
+	return t
+}
@@ -0,0 +1,389 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// TransactionRepository - in-memory implementation of TransactionRepositoryInterface
+type TransactionRepository struct {
+	access
+}
+
+// NewTransactionRepository - constructor untuk in-memory TransactionRepository
+func NewTransactionRepository(store *Store) *TransactionRepository {
+	return &TransactionRepository{access: access{store: store}}
+}
+
+// Create - simpan transaksi beserta detailnya
+func (r *TransactionRepository) Create(transaction entity.Transaction) (entity.Transaction, error) {
+	r.lock()
+	defer r.unlock()
+
+	if transaction.TotalAmount.IsNegative() || transaction.Subtotal.IsNegative() || transaction.DiscountAmount.IsNegative() || transaction.TaxAmount.IsNegative() {
+		return entity.Transaction{}, ErrNegativeHarga
+	}
+	for _, tax := range transaction.Taxes {
+		if tax.TaxableAmount.IsNegative() || tax.Amount.IsNegative() {
+			return entity.Transaction{}, ErrNegativeHarga
+		}
+		if tax.Rate <= 0 {
+			return entity.Transaction{}, ErrInvalidTaxRate
+		}
+		if utf8.RuneCountInString(tax.Nama) > 100 {
+			return entity.Transaction{}, ErrNameTooLong
+		}
+		if tax.TaxRateID != nil {
+			if _, ok := r.store.taxRates[*tax.TaxRateID]; !ok {
+				return entity.Transaction{}, ErrInvalidTaxRateFK
+			}
+		}
+	}
+	for _, d := range transaction.Discounts {
+		if d.Amount.IsNegative() {
+			return entity.Transaction{}, ErrNegativeHarga
+		}
+		if utf8.RuneCountInString(d.Nama) > 100 {
+			return entity.Transaction{}, ErrNameTooLong
+		}
+		if d.PromotionID != nil {
+			if _, ok := r.store.promotions[*d.PromotionID]; !ok {
+				return entity.Transaction{}, ErrInvalidPromotionFK
+			}
+		}
+		if d.VoucherID != nil {
+			if _, ok := r.store.vouchers[*d.VoucherID]; !ok {
+				return entity.Transaction{}, ErrInvalidVoucherFK
+			}
+		}
+	}
+	for _, d := range transaction.Details {
+		if d.Quantity <= 0 || d.BaseQuantity < 0 {
+			return entity.Transaction{}, ErrInvalidQuantity
+		}
+		if d.Harga.IsNegative() || d.Subtotal.IsNegative() || (d.HargaBeli != nil && d.HargaBeli.IsNegative()) {
+			return entity.Transaction{}, ErrNegativeHarga
+		}
+		if d.Discount.IsNegative() || d.Discount.Amount > d.Subtotal.Amount {
+			return entity.Transaction{}, ErrInvalidDiscount
+		}
+		if d.TaxIncluded.IsNegative() {
+			return entity.Transaction{}, ErrNegativeHarga
+		}
+		if d.VariantID != nil {
+			if _, ok := r.store.variants[*d.VariantID]; !ok {
+				return entity.Transaction{}, ErrInvalidVariantFK
+			}
+		}
+		for _, m := range d.Modifiers {
+			if m.Harga.IsNegative() {
+				return entity.Transaction{}, ErrNegativeHarga
+			}
+			if utf8.RuneCountInString(m.GroupNama) > 100 || utf8.RuneCountInString(m.Nama) > 100 {
+				return entity.Transaction{}, ErrNameTooLong
+			}
+			if m.ModifierID != nil {
+				if _, ok := r.store.modifiers[*m.ModifierID]; !ok {
+					return entity.Transaction{}, ErrInvalidModifierFK
+				}
+			}
+		}
+		if d.ProductID != nil {
+			if _, ok := r.store.products[*d.ProductID]; !ok {
+				if _, ok := r.store.deletedProducts[*d.ProductID]; !ok {
+					return entity.Transaction{}, ErrInvalidProductFK
+				}
+			}
+		}
+	}
+
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	currency := transaction.TotalAmount.Cur()
+	if transaction.Subtotal.IsZero() && transaction.DiscountAmount.IsZero() {
+		transaction.Subtotal = entity.NewMoney(transaction.TotalAmount.Amount-transaction.TaxAmount.Amount, currency)
+	}
+	transaction.TotalAmount.Currency = currency
+	transaction.Subtotal.Currency = currency
+	transaction.DiscountAmount.Currency = currency
+	transaction.TaxAmount.Currency = currency
+	transaction.ID = r.store.nextTxID
+	r.store.nextTxID++
+
+	transaction = cloneTransaction(transaction)
+	for i := range transaction.Details {
+		d := &transaction.Details[i]
+		d.ID = r.store.nextTxDetailID
+		d.TransactionID = transaction.ID
+		r.store.nextTxDetailID++
+		if d.Unit == "" {
+			d.Unit = entity.DefaultUnit
+		}
+		if d.BaseQuantity == 0 {
+			d.BaseQuantity = d.Quantity
+		}
+		d.Harga.Currency = currency
+		d.Subtotal.Currency = currency
+		d.Discount.Currency = currency
+		d.TaxIncluded.Currency = currency
+		if d.HargaBeli != nil {
+			d.HargaBeli.Currency = currency
+		}
+		for j := range d.Modifiers {
+			d.Modifiers[j].Harga.Currency = currency
+		}
+	}
+	for i := range transaction.Discounts {
+		transaction.Discounts[i].Amount.Currency = currency
+	}
+	for i := range transaction.Taxes {
+		transaction.Taxes[i].TaxableAmount.Currency = currency
+		transaction.Taxes[i].Amount.Currency = currency
+	}
+	r.store.transactions[transaction.ID] = transaction
+
+	return cloneTransaction(transaction), nil
+}
+
+// GetByID - ambil transaksi beserta detailnya
+func (r *TransactionRepository) GetByID(id int) (entity.Transaction, error) {
+	r.rlock()
+	defer r.runlock()
+
+	t, ok := r.store.transactions[id]
+	if !ok {
+		return entity.Transaction{}, repository.ErrTransactionNotFound
+	}
+	return cloneTransaction(t), nil
+}
+
+// MarginReport - pendapatan, harga pokok dan pendapatan tanpa harga beli per kelompok
+func (r *TransactionRepository) MarginReport(filter entity.MarginFilter) ([]entity.MarginRow, error) {
+	if filter.GroupBy != entity.MarginByProduct && filter.GroupBy != entity.MarginByCategory {
+		return nil, fmt.Errorf("unknown margin grouping %q", filter.GroupBy)
+	}
+
+	r.rlock()
+	defer r.runlock()
+
+	groups := make(map[marginKey]*entity.MarginRow)
+	var order []marginKey
+
+	for _, t := range r.store.transactions {
+		if (!filter.From.IsZero() && t.CreatedAt.Before(filter.From)) || (!filter.To.IsZero() && !t.CreatedAt.Before(filter.To)) {
+			continue
+		}
+		currency := t.TotalAmount.Cur()
+
+		for _, d := range t.Details {
+			key, name := r.marginGroup(filter.GroupBy, d)
+			key.currency = currency
+
+			row, ok := groups[key]
+			if !ok {
+				row = &entity.MarginRow{
+					Name:            name,
+					Revenue:         entity.NewMoney(0, currency),
+					Cost:            entity.NewMoney(0, currency),
+					UncostedRevenue: entity.NewMoney(0, currency),
+				}
+				if key.id != 0 {
+					id := key.id
+					row.ID = &id
+				}
+				groups[key] = row
+				order = append(order, key)
+			}
+
+			row.Quantity += d.BaseQuantity
+			// pendapatan bersih setelah diskon, tanpa pajak inclusive
+			revenue := d.Subtotal.Amount - d.Discount.Amount - d.TaxIncluded.Amount
+			row.Revenue.Amount += revenue
+			if d.HargaBeli == nil {
+				row.UncostedRevenue.Amount += revenue
+			} else {
+				// harga beli per satuan jual × quantity (seperseribu unit), dibulatkan per baris seperti SQL
+				row.Cost.Amount += (d.HargaBeli.Amount*int64(d.Quantity) + 500) / 1000
+			}
+		}
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		return order[i].id < order[j].id || (order[i].id == order[j].id && order[i].name < order[j].name)
+	})
+	report := make([]entity.MarginRow, 0, len(order))
+	for _, key := range order {
+		report = append(report, *groups[key])
+	}
+	return report, nil
+}
+
+// TaxReport - dasar pengenaan dan jumlah pajak per tarif dan mata uang, urut seperti SQL
+func (r *TransactionRepository) TaxReport(filter entity.TaxFilter) ([]entity.TaxRow, error) {
+	r.rlock()
+	defer r.runlock()
+
+	type taxKey struct {
+		nama      string
+		rate      entity.Percent
+		inclusive bool
+		currency  string
+	}
+	groups := make(map[taxKey]*entity.TaxRow)
+	seen := make(map[taxKey]map[int]bool)
+	var order []taxKey
+
+	for _, t := range r.store.transactions {
+		if (!filter.From.IsZero() && t.CreatedAt.Before(filter.From)) || (!filter.To.IsZero() && !t.CreatedAt.Before(filter.To)) {
+			continue
+		}
+		currency := t.TotalAmount.Cur()
+		for _, tax := range t.Taxes {
+			key := taxKey{tax.Nama, tax.Rate, tax.Inclusive, currency}
+			row, ok := groups[key]
+			if !ok {
+				row = &entity.TaxRow{
+					Nama:          tax.Nama,
+					Rate:          tax.Rate,
+					Inclusive:     tax.Inclusive,
+					TaxableAmount: entity.NewMoney(0, currency),
+					Amount:        entity.NewMoney(0, currency),
+				}
+				groups[key] = row
+				seen[key] = make(map[int]bool)
+				order = append(order, key)
+			}
+			if !seen[key][t.ID] {
+				seen[key][t.ID] = true
+				row.Transactions++
+			}
+			row.TaxableAmount.Amount += tax.TaxableAmount.Amount
+			row.Amount.Amount += tax.Amount.Amount
+		}
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if a.nama != b.nama {
+			return a.nama < b.nama
+		}
+		if a.rate != b.rate {
+			return a.rate < b.rate
+		}
+		if a.inclusive != b.inclusive {
+			return !a.inclusive
+		}
+		return a.currency < b.currency
+	})
+	var report []entity.TaxRow
+	for _, key := range order {
+		report = append(report, *groups[key])
+	}
+	return report, nil
+}
+
+// marginKey identifies one row of the margin report
+type marginKey struct {
+	id       int    // product_id / category_id, 0 = NULL
+	name     string // nama produk saat penjualan untuk produk yang sudah dihapus
+	currency string
+}
+
+// marginGroup returns the grouping key of a sale line, mirroring the SQL GROUP BY.
+// Caller must hold the store lock.
+func (r *TransactionRepository) marginGroup(groupBy string, d entity.TransactionDetail) (key marginKey, name string) {
+	var product *entity.Product
+	if d.ProductID != nil {
+		if p, ok := r.store.products[*d.ProductID]; ok {
+			product = &p
+		} else if p, ok := r.store.deletedProducts[*d.ProductID]; ok {
+			product = &p
+		}
+	}
+
+	if groupBy == entity.MarginByCategory {
+		if product != nil && product.CategoryID != nil {
+			if c, ok := r.store.categories[*product.CategoryID]; ok {
+				key.id = c.ID
+				return key, c.Name
+			}
+		}
+		return key, ""
+	}
+
+	if product != nil {
+		key.id = product.ID
+		return key, product.Nama
+	}
+	// Produk sudah dihapus: kelompokkan berdasarkan nama saat penjualan
+	key.name = d.NamaProduk
+	return key, d.NamaProduk
+}
+
+// cloneTransaction deep-copies details so the store is never modified through a caller
+func cloneTransaction(t entity.Transaction) entity.Transaction {
+	details := make([]entity.TransactionDetail, len(t.Details))
+	for i, d := range t.Details {
+		if d.ProductID != nil {
+			id := *d.ProductID
+			d.ProductID = &id
+		}
+		if d.VariantID != nil {
+			id := *d.VariantID
+			d.VariantID = &id
+		}
+		if d.HargaBeli != nil {
+			cost := *d.HargaBeli
+			d.HargaBeli = &cost
+		}
+		if d.Modifiers != nil {
+			modifiers := make([]entity.TransactionDetailModifier, len(d.Modifiers))
+			for j, m := range d.Modifiers {
+				if m.ModifierID != nil {
+					id := *m.ModifierID
+					m.ModifierID = &id
+				}
+				modifiers[j] = m
+			}
+			d.Modifiers = modifiers
+		}
+		details[i] = d
+	}
+	t.Details = details
+	if len(details) == 0 {
+		t.Details = nil
+	}
+	if t.Discounts != nil {
+		discounts := make([]entity.TransactionDiscount, len(t.Discounts))
+		for i, d := range t.Discounts {
+			if d.PromotionID != nil {
+				id := *d.PromotionID
+				d.PromotionID = &id
+			}
+			if d.VoucherID != nil {
+				id := *d.VoucherID
+				d.VoucherID = &id
+			}
+			discounts[i] = d
+		}
+		t.Discounts = discounts
+	}
+	if t.Taxes != nil {
+		taxes := make([]entity.TransactionTax, len(t.Taxes))
+		for i, tax := range t.Taxes {
+			if tax.TaxRateID != nil {
+				id := *tax.TaxRateID
+				tax.TaxRateID = &id
+			}
+			taxes[i] = tax
+		}
+		t.Taxes = taxes
+	}
+	return t
+}
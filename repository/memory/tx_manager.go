@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+
+	"kasir-api/repository"
+)
+
+// NewRepositories - constructor untuk semua repository in-memory di atas store
+func NewRepositories(store *Store) repository.Repositories {
+	return repository.Repositories{
+		Category:    NewCategoryRepository(store),
+		Product:     NewProductRepository(store),
+		Transaction: NewTransactionRepository(store),
+		Price:       NewPriceRepository(store),
+		Variant:     NewVariantRepository(store),
+		Modifier:    NewModifierRepository(store),
+		Bundle:      NewBundleRepository(store),
+		Unit:        NewUnitRepository(store),
+		Promotion:   NewPromotionRepository(store),
+		Voucher:     NewVoucherRepository(store),
+		TaxRate:     NewTaxRateRepository(store),
+	}
+}
+
+// TxManager - unit of work in-memory. A transaction holds the store write
+// lock for its whole duration and restores a snapshot on error or panic.
+type TxManager struct {
+	store *Store
+}
+
+// NewTxManager - constructor untuk in-memory TxManager
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+// WithinTx - jalankan fn secara atomik terhadap store
+func (m *TxManager) WithinTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	snap := m.store.snapshot()
+	committed := false
+	defer func() {
+		if !committed {
+			m.store.restore(snap)
+		}
+	}()
+
+	if err := fn(txRepositories(access{store: m.store, inTx: true})); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
+
+// WithinReadTx - jalankan fn dengan read lock tanpa snapshot, pembaca lain tidak
+// terblokir. Repository yang menulis di dalam fn akan panic.
+func (m *TxManager) WithinReadTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return fn(txRepositories(access{store: m.store, inTx: true, readOnly: true}))
+}
+
+// txRepositories - semua repository yang memakai lock milik transaksi tx
+func txRepositories(tx access) repository.Repositories {
+	return repository.Repositories{
+		Category:    &CategoryRepository{access: tx},
+		Product:     &ProductRepository{access: tx},
+		Transaction: &TransactionRepository{access: tx},
+		Price:       &PriceRepository{access: tx},
+		Variant:     &VariantRepository{access: tx},
+		Modifier:    &ModifierRepository{access: tx},
+		Bundle:      &BundleRepository{access: tx},
+		Unit:        &UnitRepository{access: tx},
+		Promotion:   &PromotionRepository{access: tx},
+		Voucher:     &VoucherRepository{access: tx},
+		TaxRate:     &TaxRateRepository{access: tx},
+	}
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// UnitRepository - in-memory implementation of UnitRepositoryInterface
+type UnitRepository struct {
+	access
+}
+
+// NewUnitRepository - constructor untuk in-memory UnitRepository
+func NewUnitRepository(store *Store) *UnitRepository {
+	return &UnitRepository{access: access{store: store}}
+}
+
+// GetAll - semua satuan, urut berdasarkan kode
+func (r *UnitRepository) GetAll() ([]entity.Unit, error) {
+	r.rlock()
+	defer r.runlock()
+
+	units := make([]entity.Unit, 0, len(r.store.units))
+	for _, u := range r.store.units {
+		units = append(units, u)
+	}
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].Code < units[j].Code
+	})
+	return units, nil
+}
+
+// GetByCode - ambil satuan berdasarkan kode
+func (r *UnitRepository) GetByCode(code string) (entity.Unit, error) {
+	r.rlock()
+	defer r.runlock()
+
+	u, ok := r.store.units[code]
+	if !ok {
+		return entity.Unit{}, repository.ErrUnitNotFound
+	}
+	return u, nil
+}
+
+// Create - tambah satuan, kode unik
+func (r *UnitRepository) Create(unit entity.Unit) (entity.Unit, error) {
+	r.lock()
+	defer r.unlock()
+
+	if unit.Code != strings.ToLower(unit.Code) || len(unit.Code) > 10 {
+		return entity.Unit{}, ErrInvalidUnit
+	}
+	if len([]rune(unit.Nama)) > 50 {
+		return entity.Unit{}, ErrNameTooLong
+	}
+	if existing, ok := r.store.units[unit.Code]; ok {
+		return entity.Unit{}, &repository.ConflictError{Resource: "unit", Name: unit.Code, Scope: "store", Existing: existing}
+	}
+	r.store.units[unit.Code] = unit
+	return unit, nil
+}
+
+// Update - ubah nama dan izin desimal satuan
+func (r *UnitRepository) Update(code string, unit entity.Unit) (entity.Unit, error) {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.units[code]; !ok {
+		return entity.Unit{}, repository.ErrUnitNotFound
+	}
+	if len([]rune(unit.Nama)) > 50 {
+		return entity.Unit{}, ErrNameTooLong
+	}
+	unit.Code = code
+	r.store.units[code] = unit
+	return unit, nil
+}
+
+// Delete - hapus satuan yang tidak dipakai produk mana pun
+func (r *UnitRepository) Delete(code string) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.units[code]; !ok {
+		return repository.ErrUnitNotFound
+	}
+	// products.unit, products.purchase_unit dan product_units.unit ON DELETE RESTRICT,
+	// termasuk produk yang di-soft delete
+	for _, products := range []map[int]entity.Product{r.store.products, r.store.deletedProducts} {
+		for _, p := range products {
+			if p.Unit == code || p.PurchaseUnit == code {
+				return repository.ErrUnitInUse
+			}
+		}
+	}
+	for _, conversions := range r.store.productUnits {
+		for _, c := range conversions {
+			if c.Unit == code {
+				return repository.ErrUnitInUse
+			}
+		}
+	}
+	delete(r.store.units, code)
+	return nil
+}
+
+// GetConversions - satuan lain dari satu produk, urut berdasarkan faktor
+func (r *UnitRepository) GetConversions(productID int) ([]entity.UnitConversion, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.conversions(productID), nil
+}
+
+// GetConversionsByProducts - satuan lain dari banyak produk sekaligus, dikelompokkan per product ID
+func (r *UnitRepository) GetConversionsByProducts(productIDs []int) (map[int][]entity.UnitConversion, error) {
+	r.rlock()
+	defer r.runlock()
+
+	grouped := make(map[int][]entity.UnitConversion)
+	for _, id := range productIDs {
+		if conversions := r.conversions(id); conversions != nil {
+			grouped[id] = conversions
+		}
+	}
+	return grouped, nil
+}
+
+// conversions returns a sorted copy of the product's conversions like ORDER BY factor, unit.
+// Caller must hold the store lock.
+func (r *UnitRepository) conversions(productID int) []entity.UnitConversion {
+	stored := r.store.productUnits[productID]
+	if len(stored) == 0 {
+		return nil
+	}
+	conversions := append([]entity.UnitConversion(nil), stored...)
+	sort.Slice(conversions, func(i, j int) bool {
+		a, b := conversions[i], conversions[j]
+		return a.Factor < b.Factor || (a.Factor == b.Factor && a.Unit < b.Unit)
+	})
+	return conversions
+}
+
+// SetConversions - ganti semua satuan lain dari produk
+func (r *UnitRepository) SetConversions(productID int, conversions []entity.UnitConversion) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.products[productID]; !ok {
+		return ErrInvalidProductFK
+	}
+	seen := make(map[string]bool, len(conversions))
+	for _, c := range conversions {
+		if _, ok := r.store.units[c.Unit]; !ok {
+			return ErrInvalidUnitFK
+		}
+		if c.Factor <= 0 {
+			return ErrInvalidFactor
+		}
+		if seen[c.Unit] {
+			return ErrDuplicateUnit
+		}
+		seen[c.Unit] = true
+	}
+
+	if len(conversions) == 0 {
+		delete(r.store.productUnits, productID)
+		return nil
+	}
+	r.store.productUnits[productID] = append([]entity.UnitConversion(nil), conversions...)
+	return nil
+}
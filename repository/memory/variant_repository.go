@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// VariantRepository - in-memory implementation of VariantRepositoryInterface
+type VariantRepository struct {
+	access
+}
+
+// NewVariantRepository - constructor untuk in-memory VariantRepository
+func NewVariantRepository(store *Store) *VariantRepository {
+	return &VariantRepository{access: access{store: store}}
+}
+
+// GetByProduct - semua varian produk, urut berdasarkan ID
+func (r *VariantRepository) GetByProduct(productID int) ([]entity.ProductVariant, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.byProducts([]int{productID})[productID], nil
+}
+
+// GetByProducts - varian dari banyak produk sekaligus, dikelompokkan per product ID
+func (r *VariantRepository) GetByProducts(productIDs []int) (map[int][]entity.ProductVariant, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.byProducts(productIDs), nil
+}
+
+// byProducts groups copies of the variants of productIDs ordered by ID.
+// Caller must hold the store lock.
+func (r *VariantRepository) byProducts(productIDs []int) map[int][]entity.ProductVariant {
+	wanted := make(map[int]bool, len(productIDs))
+	for _, id := range productIDs {
+		wanted[id] = true
+	}
+
+	grouped := make(map[int][]entity.ProductVariant)
+	for _, v := range r.store.variants {
+		if wanted[v.ProductID] {
+			grouped[v.ProductID] = append(grouped[v.ProductID], cloneVariant(v))
+		}
+	}
+	for _, variants := range grouped {
+		sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+	}
+	return grouped
+}
+
+// GetByID - ambil varian berdasarkan ID
+func (r *VariantRepository) GetByID(id int) (entity.ProductVariant, error) {
+	r.rlock()
+	defer r.runlock()
+
+	v, ok := r.store.variants[id]
+	if !ok {
+		return entity.ProductVariant{}, repository.ErrVariantNotFound
+	}
+	return cloneVariant(v), nil
+}
+
+// Create - tambah varian
+func (r *VariantRepository) Create(variant entity.ProductVariant) (entity.ProductVariant, error) {
+	r.lock()
+	defer r.unlock()
+
+	if err := r.validate(variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+	if err := r.checkConflict(0, variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	variant.ID = r.store.nextVariantID
+	r.store.nextVariantID++
+	variant = cloneVariant(variant)
+	r.store.variants[variant.ID] = variant
+	return cloneVariant(variant), nil
+}
+
+// Update - update varian, product_id tidak bisa dipindah
+func (r *VariantRepository) Update(id int, variant entity.ProductVariant) (entity.ProductVariant, error) {
+	r.lock()
+	defer r.unlock()
+
+	current, ok := r.store.variants[id]
+	if !ok {
+		return entity.ProductVariant{}, repository.ErrVariantNotFound
+	}
+	variant.ID = id
+	variant.ProductID = current.ProductID
+	if err := r.validate(variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+	if err := r.checkConflict(id, variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	variant = cloneVariant(variant)
+	r.store.variants[id] = variant
+	return cloneVariant(variant), nil
+}
+
+// Delete - hapus varian, detail transaksi lama tetap ada dengan variant_id NULL
+func (r *VariantRepository) Delete(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	v, ok := r.store.variants[id]
+	if !ok {
+		return repository.ErrVariantNotFound
+	}
+	// bundle_components.variant_id ON DELETE RESTRICT
+	if r.store.isComponent(v.ProductID, &id) {
+		return ErrComponentRestrict
+	}
+	r.store.deleteVariant(id)
+	return nil
+}
+
+// AdjustStock - tambah atau kurangi stok, stok tidak boleh minus
+func (r *VariantRepository) AdjustStock(id int, delta entity.Quantity) (entity.ProductVariant, error) {
+	r.lock()
+	defer r.unlock()
+
+	v, ok := r.store.variants[id]
+	if !ok {
+		return entity.ProductVariant{}, repository.ErrVariantNotFound
+	}
+	if *v.Stock+delta < 0 {
+		return entity.ProductVariant{}, repository.ErrInsufficientStock
+	}
+	stock := *v.Stock + delta
+	v.Stock = &stock
+	r.store.variants[id] = v
+	return cloneVariant(v), nil
+}
+
+// validate mirrors the column, check and foreign key constraints of product_variants.
+// Caller must hold the store lock.
+func (r *VariantRepository) validate(variant entity.ProductVariant) error {
+	if utf8.RuneCountInString(variant.SKU) > 64 {
+		return ErrSKUTooLong
+	}
+	if utf8.RuneCountInString(variant.Nama) > 100 {
+		return ErrNameTooLong
+	}
+	if variant.Harga.IsNegative() || (variant.HargaBeli != nil && variant.HargaBeli.IsNegative()) {
+		return ErrNegativeHarga
+	}
+	if variant.Stock != nil && *variant.Stock < 0 {
+		return ErrNegativeStock
+	}
+	if _, ok := r.store.products[variant.ProductID]; !ok {
+		if _, ok := r.store.deletedProducts[variant.ProductID]; !ok {
+			return ErrInvalidProductFK
+		}
+	}
+	return nil
+}
+
+// checkConflict mirrors the unique indexes on LOWER(sku) and (product_id, LOWER(nama)).
+// Caller must hold the store lock.
+func (r *VariantRepository) checkConflict(id int, variant entity.ProductVariant) error {
+	ids := make([]int, 0, len(r.store.variants))
+	for vid := range r.store.variants {
+		ids = append(ids, vid)
+	}
+	sort.Ints(ids)
+
+	for _, vid := range ids {
+		v := r.store.variants[vid]
+		if vid == id {
+			continue
+		}
+		if strings.EqualFold(v.SKU, variant.SKU) {
+			return &repository.ConflictError{Resource: "variant SKU", Name: variant.SKU, Scope: "store", Existing: cloneVariant(v)}
+		}
+		if v.ProductID == variant.ProductID && strings.EqualFold(v.Nama, variant.Nama) {
+			return &repository.ConflictError{Resource: "variant", Name: variant.Nama, Scope: "product", Existing: cloneVariant(v)}
+		}
+	}
+	return nil
+}
+
+// cloneVariant copies the options map and stock, and fills defaults like the database
+func cloneVariant(v entity.ProductVariant) entity.ProductVariant {
+	options := make(map[string]string, len(v.Options))
+	for k, val := range v.Options {
+		options[k] = val
+	}
+	v.Options = options
+	v.Harga.Currency = v.Harga.Cur()
+	if v.HargaBeli != nil {
+		cost := entity.NewMoney(v.HargaBeli.Amount, v.Harga.Currency)
+		v.HargaBeli = &cost
+	}
+	var stock entity.Quantity
+	if v.Stock != nil {
+		stock = *v.Stock
+	}
+	v.Stock = &stock
+	v.ClearHargaBeli = false
+	return v
+}
@@ -0,0 +1,277 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// VoucherRepository - in-memory implementation of VoucherRepositoryInterface
+type VoucherRepository struct {
+	access
+}
+
+// NewVoucherRepository - constructor untuk in-memory VoucherRepository
+func NewVoucherRepository(store *Store) *VoucherRepository {
+	return &VoucherRepository{access: access{store: store}}
+}
+
+// GetAll - semua voucher, urut berdasarkan ID
+func (r *VoucherRepository) GetAll() ([]entity.Voucher, error) {
+	r.rlock()
+	defer r.runlock()
+
+	var vouchers []entity.Voucher
+	for _, v := range r.store.vouchers {
+		vouchers = append(vouchers, cloneVoucher(v))
+	}
+	sort.Slice(vouchers, func(i, j int) bool {
+		return vouchers[i].ID < vouchers[j].ID
+	})
+	return vouchers, nil
+}
+
+// GetByID - ambil voucher berdasarkan ID
+func (r *VoucherRepository) GetByID(id int) (entity.Voucher, error) {
+	r.rlock()
+	defer r.runlock()
+
+	v, ok := r.store.vouchers[id]
+	if !ok {
+		return entity.Voucher{}, repository.ErrVoucherNotFound
+	}
+	return cloneVoucher(v), nil
+}
+
+// GetByCode - ambil voucher berdasarkan kode (huruf besar)
+func (r *VoucherRepository) GetByCode(code string) (entity.Voucher, error) {
+	r.rlock()
+	defer r.runlock()
+
+	if v, ok := r.byCode(code); ok {
+		return cloneVoucher(v), nil
+	}
+	return entity.Voucher{}, repository.ErrVoucherNotFound
+}
+
+// byCode finds a voucher by its exact code. Caller must hold the store lock.
+func (r *VoucherRepository) byCode(code string) (entity.Voucher, bool) {
+	for _, v := range r.store.vouchers {
+		if v.Code == code {
+			return v, true
+		}
+	}
+	return entity.Voucher{}, false
+}
+
+// Create - tambah voucher, kode unik
+func (r *VoucherRepository) Create(voucher entity.Voucher) (entity.Voucher, error) {
+	r.lock()
+	defer r.unlock()
+
+	voucher = normalizeVoucher(voucher)
+	voucher.UsedCount = 0
+	if err := validateVoucher(voucher); err != nil {
+		return entity.Voucher{}, err
+	}
+	if existing, ok := r.byCode(voucher.Code); ok {
+		return entity.Voucher{}, &repository.ConflictError{Resource: "voucher", Name: voucher.Code, Scope: "store", Existing: cloneVoucher(existing)}
+	}
+
+	voucher.ID = r.store.nextVoucherID
+	r.store.nextVoucherID++
+	r.store.vouchers[voucher.ID] = voucher
+	return cloneVoucher(voucher), nil
+}
+
+// Update - ubah voucher, used_count tidak berubah
+func (r *VoucherRepository) Update(id int, voucher entity.Voucher) (entity.Voucher, error) {
+	r.lock()
+	defer r.unlock()
+
+	current, ok := r.store.vouchers[id]
+	if !ok {
+		return entity.Voucher{}, repository.ErrVoucherNotFound
+	}
+	voucher = normalizeVoucher(voucher)
+	voucher.ID, voucher.UsedCount = id, current.UsedCount
+	if err := validateVoucher(voucher); err != nil {
+		return entity.Voucher{}, err
+	}
+	if existing, ok := r.byCode(voucher.Code); ok && existing.ID != id {
+		return entity.Voucher{}, &repository.ConflictError{Resource: "voucher", Name: voucher.Code, Scope: "store", Existing: cloneVoucher(existing)}
+	}
+
+	r.store.vouchers[id] = voucher
+	return cloneVoucher(voucher), nil
+}
+
+// Delete - hapus voucher beserta riwayat pemakaiannya, diskon di transaksi lama tetap ada
+func (r *VoucherRepository) Delete(id int) error {
+	r.lock()
+	defer r.unlock()
+
+	if _, ok := r.store.vouchers[id]; !ok {
+		return repository.ErrVoucherNotFound
+	}
+	delete(r.store.vouchers, id)
+
+	// voucher_redemptions.voucher_id ON DELETE CASCADE
+	var redemptions []entity.VoucherRedemption
+	for _, redemption := range r.store.redemptions {
+		if redemption.VoucherID != id {
+			redemptions = append(redemptions, redemption)
+		}
+	}
+	r.store.redemptions = redemptions
+
+	// transaction_discounts.voucher_id ON DELETE SET NULL
+	for txID, t := range r.store.transactions {
+		clone := cloneTransaction(t)
+		changed := false
+		for i, d := range clone.Discounts {
+			if d.VoucherID != nil && *d.VoucherID == id {
+				clone.Discounts[i].VoucherID = nil
+				changed = true
+			}
+		}
+		if changed {
+			r.store.transactions[txID] = clone
+		}
+	}
+	return nil
+}
+
+// CountRedemptions - jumlah pemakaian voucher oleh customer
+func (r *VoucherRepository) CountRedemptions(voucherID int, customer string) (int, error) {
+	r.rlock()
+	defer r.runlock()
+
+	return r.countRedemptions(voucherID, customer), nil
+}
+
+// countRedemptions counts the redemptions of one customer. Caller must hold the store lock.
+func (r *VoucherRepository) countRedemptions(voucherID int, customer string) int {
+	count := 0
+	for _, redemption := range r.store.redemptions {
+		if redemption.VoucherID == voucherID && redemption.Customer == customer {
+			count++
+		}
+	}
+	return count
+}
+
+// Redeem - catat pemakaian voucher dengan cek kuota total dan kuota customer secara atomik
+func (r *VoucherRepository) Redeem(redemption entity.VoucherRedemption) (entity.VoucherRedemption, error) {
+	r.lock()
+	defer r.unlock()
+
+	v, ok := r.store.vouchers[redemption.VoucherID]
+	if !ok {
+		return entity.VoucherRedemption{}, repository.ErrVoucherNotFound
+	}
+	if v.UsageLimit != nil && v.UsedCount >= *v.UsageLimit {
+		return entity.VoucherRedemption{}, repository.ErrVoucherUsageLimit
+	}
+	if v.PerCustomerLimit != nil {
+		if redemption.Customer == "" || r.countRedemptions(v.ID, redemption.Customer) >= *v.PerCustomerLimit {
+			return entity.VoucherRedemption{}, repository.ErrVoucherCustomerLimit
+		}
+	}
+	if _, ok := r.store.transactions[redemption.TransactionID]; !ok {
+		return entity.VoucherRedemption{}, ErrInvalidTransactionFK
+	}
+	if redemption.Amount.IsNegative() {
+		return entity.VoucherRedemption{}, ErrNegativeHarga
+	}
+	if utf8.RuneCountInString(redemption.Customer) > 100 {
+		return entity.VoucherRedemption{}, ErrNameTooLong
+	}
+
+	v.UsedCount++
+	r.store.vouchers[v.ID] = v
+
+	if redemption.CreatedAt.IsZero() {
+		redemption.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	redemption.Amount = entity.NewMoney(redemption.Amount.Amount, voucherCurrency(v))
+	redemption.ID = r.store.nextRedeemID
+	r.store.nextRedeemID++
+	redemptions := make([]entity.VoucherRedemption, len(r.store.redemptions), len(r.store.redemptions)+1)
+	copy(redemptions, r.store.redemptions)
+	r.store.redemptions = append(redemptions, redemption)
+	return redemption, nil
+}
+
+// validateVoucher mirrors the column and check constraints of vouchers
+func validateVoucher(v entity.Voucher) error {
+	if utf8.RuneCountInString(v.Nama) > 100 {
+		return ErrNameTooLong
+	}
+	if utf8.RuneCountInString(v.Code) > 32 {
+		return ErrInvalidVoucherCode
+	}
+	switch {
+	case v.Code != strings.ToUpper(v.Code),
+		v.Type != entity.VoucherPercentage && v.Type != entity.VoucherFixed,
+		(v.Type == entity.VoucherPercentage) != (v.Percent != 0),
+		(v.Type == entity.VoucherFixed) != (v.Amount != nil),
+		v.Percent < 0 || v.Percent > 10000,
+		v.Amount != nil && v.Amount.Amount <= 0,
+		v.MaxDiscount != nil && v.MaxDiscount.Amount <= 0,
+		v.MinSubtotal != nil && v.MinSubtotal.IsNegative(),
+		v.UsageLimit != nil && (*v.UsageLimit <= 0 || v.UsedCount > *v.UsageLimit),
+		v.PerCustomerLimit != nil && *v.PerCustomerLimit <= 0:
+		return ErrInvalidVoucher
+	}
+	if v.StartsAt != nil && v.ExpiresAt != nil && !v.ExpiresAt.After(*v.StartsAt) {
+		return ErrInvalidVoucherRange
+	}
+	return nil
+}
+
+// normalizeVoucher stores the voucher like the vouchers table does: one currency for
+// every amount and UTC timestamps
+func normalizeVoucher(v entity.Voucher) entity.Voucher {
+	v = cloneVoucher(v)
+	currency := voucherCurrency(v)
+	for _, m := range []*entity.Money{v.Amount, v.MaxDiscount, v.MinSubtotal} {
+		if m != nil {
+			m.Currency = currency
+		}
+	}
+	return v
+}
+
+// voucherCurrency - mata uang nilai nominal pertama voucher, default IDR
+func voucherCurrency(v entity.Voucher) string {
+	for _, m := range []*entity.Money{v.Amount, v.MaxDiscount, v.MinSubtotal} {
+		if m != nil {
+			return m.Cur()
+		}
+	}
+	return entity.DefaultCurrency
+}
+
+// cloneVoucher copies pointer fields so callers never share memory with the store
+func cloneVoucher(v entity.Voucher) entity.Voucher {
+	for _, m := range []**entity.Money{&v.Amount, &v.MaxDiscount, &v.MinSubtotal} {
+		if *m != nil {
+			copied := **m
+			*m = &copied
+		}
+	}
+	for _, n := range []**int{&v.UsageLimit, &v.PerCustomerLimit} {
+		if *n != nil {
+			copied := **n
+			*n = &copied
+		}
+	}
+	v.StartsAt = utcTime(v.StartsAt)
+	v.ExpiresAt = utcTime(v.ExpiresAt)
+	return v
+}
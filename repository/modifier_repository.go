@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/entity"
+	"strings"
+)
+
+// ModifierRepositoryInterface - interface untuk modifier group dan modifier repository
+type ModifierRepositoryInterface interface {
+	GetAllGroups() ([]entity.ModifierGroup, error)
+	GetGroupsFor(productID int, categoryIDs []int) ([]entity.ModifierGroup, error)
+	GetGroupByID(id int) (entity.ModifierGroup, error)
+	CreateGroup(group entity.ModifierGroup) (entity.ModifierGroup, error)
+	UpdateGroup(id int, group entity.ModifierGroup) (entity.ModifierGroup, error)
+	DeleteGroup(id int) error
+	GetModifierByID(id int) (entity.Modifier, error)
+	CreateModifier(modifier entity.Modifier) (entity.Modifier, error)
+	UpdateModifier(id int, modifier entity.Modifier) (entity.Modifier, error)
+	DeleteModifier(id int) error
+}
+
+// ModifierRepository - struct untuk modifier repository
+type ModifierRepository struct {
+	db DBTX
+}
+
+// NewModifierRepository - constructor untuk ModifierRepository
+func NewModifierRepository(db DBTX) *ModifierRepository {
+	return &ModifierRepository{db: db}
+}
+
+const (
+	modifierGroupColumns = "id, nama, product_id, category_id, min_select, max_select"
+	modifierColumns      = "id, group_id, nama, harga, currency"
+)
+
+// scanModifierGroup - scan satu baris modifierGroupColumns, tanpa modifiers
+func scanModifierGroup(row interface{ Scan(...interface{}) error }) (entity.ModifierGroup, error) {
+	var g entity.ModifierGroup
+	var productID, categoryID sql.NullInt64
+	err := row.Scan(&g.ID, &g.Nama, &productID, &categoryID, &g.MinSelect, &g.MaxSelect)
+	if err != nil {
+		return entity.ModifierGroup{}, err
+	}
+	g.ProductID = nullableInt(productID)
+	g.CategoryID = nullableInt(categoryID)
+	g.Required = g.MinSelect > 0
+	g.Modifiers = []entity.Modifier{}
+	return g, nil
+}
+
+// scanModifier - scan satu baris modifierColumns
+func scanModifier(row interface{ Scan(...interface{}) error }) (entity.Modifier, error) {
+	var m entity.Modifier
+	err := row.Scan(&m.ID, &m.GroupID, &m.Nama, &m.Harga.Amount, &m.Harga.Currency)
+	return m, err
+}
+
+// queryGroups - jalankan query yang mengembalikan modifierGroupColumns, lalu muat modifiers-nya
+func (r *ModifierRepository) queryGroups(query string, args ...interface{}) ([]entity.ModifierGroup, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []entity.ModifierGroup
+	for rows.Next() {
+		g, err := scanModifierGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := r.loadModifiers(groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// loadModifiers - isi Modifiers setiap grup dengan satu query, urut berdasarkan ID
+func (r *ModifierRepository) loadModifiers(groups []entity.ModifierGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	index := make(map[int]int, len(groups))
+	placeholders := make([]string, len(groups))
+	args := make([]interface{}, len(groups))
+	for i, g := range groups {
+		index[g.ID] = i
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = g.ID
+	}
+
+	rows, err := r.db.Query(
+		"SELECT "+modifierColumns+" FROM modifiers WHERE group_id IN ("+strings.Join(placeholders, ", ")+") ORDER BY id",
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanModifier(rows)
+		if err != nil {
+			return err
+		}
+		g := &groups[index[m.GroupID]]
+		g.Modifiers = append(g.Modifiers, m)
+	}
+	return rows.Err()
+}
+
+// GetAllGroups - semua grup modifier beserta pilihannya
+func (r *ModifierRepository) GetAllGroups() ([]entity.ModifierGroup, error) {
+	return r.queryGroups("SELECT " + modifierGroupColumns + " FROM modifier_groups ORDER BY id")
+}
+
+// GetGroupsFor - grup yang menempel ke produk atau ke salah satu categoryIDs
+func (r *ModifierRepository) GetGroupsFor(productID int, categoryIDs []int) ([]entity.ModifierGroup, error) {
+	conditions := []string{"product_id = $1"}
+	args := []interface{}{productID}
+	if len(categoryIDs) > 0 {
+		placeholders := make([]string, len(categoryIDs))
+		for i, id := range categoryIDs {
+			args = append(args, id)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, "category_id IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	return r.queryGroups(
+		"SELECT "+modifierGroupColumns+" FROM modifier_groups WHERE "+strings.Join(conditions, " OR ")+" ORDER BY id",
+		args...,
+	)
+}
+
+// GetGroupByID - ambil grup modifier beserta pilihannya
+func (r *ModifierRepository) GetGroupByID(id int) (entity.ModifierGroup, error) {
+	groups, err := r.queryGroups("SELECT "+modifierGroupColumns+" FROM modifier_groups WHERE id = $1", id)
+	if err != nil {
+		return entity.ModifierGroup{}, err
+	}
+	if len(groups) == 0 {
+		return entity.ModifierGroup{}, ErrModifierGroupNotFound
+	}
+	return groups[0], nil
+}
+
+// CreateGroup - tambah grup modifier tanpa pilihan, pilihan ditambah lewat CreateModifier
+func (r *ModifierRepository) CreateGroup(group entity.ModifierGroup) (entity.ModifierGroup, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO modifier_groups (nama, product_id, category_id, min_select, max_select)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		group.Nama, group.ProductID, group.CategoryID, group.MinSelect, group.MaxSelect,
+	).Scan(&group.ID)
+	if err != nil {
+		return entity.ModifierGroup{}, err
+	}
+
+	group.Required = group.MinSelect > 0
+	group.Modifiers = []entity.Modifier{}
+	return group, nil
+}
+
+// UpdateGroup - update nama, tempat menempel dan batas pilihan grup
+func (r *ModifierRepository) UpdateGroup(id int, group entity.ModifierGroup) (entity.ModifierGroup, error) {
+	result, err := r.db.Exec(`
+		UPDATE modifier_groups SET nama = $1, product_id = $2, category_id = $3, min_select = $4, max_select = $5,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6`,
+		group.Nama, group.ProductID, group.CategoryID, group.MinSelect, group.MaxSelect, id,
+	)
+	if err != nil {
+		return entity.ModifierGroup{}, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return entity.ModifierGroup{}, err
+	}
+	if rows == 0 {
+		return entity.ModifierGroup{}, ErrModifierGroupNotFound
+	}
+	return r.GetGroupByID(id)
+}
+
+// DeleteGroup - hapus grup beserta pilihannya, baris penjualan lama tetap menyimpan salinannya
+func (r *ModifierRepository) DeleteGroup(id int) error {
+	result, err := r.db.Exec("DELETE FROM modifier_groups WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrModifierGroupNotFound
+	}
+	return nil
+}
+
+// GetModifierByID - ambil satu pilihan modifier
+func (r *ModifierRepository) GetModifierByID(id int) (entity.Modifier, error) {
+	m, err := scanModifier(r.db.QueryRow("SELECT "+modifierColumns+" FROM modifiers WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return entity.Modifier{}, ErrModifierNotFound
+	}
+	return m, err
+}
+
+// CreateModifier - tambah pilihan ke grup, nama unik per grup
+func (r *ModifierRepository) CreateModifier(modifier entity.Modifier) (entity.Modifier, error) {
+	if err := r.checkConflict(0, modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+
+	err := r.db.QueryRow(
+		"INSERT INTO modifiers (group_id, nama, harga, currency) VALUES ($1, $2, $3, $4) RETURNING id",
+		modifier.GroupID, modifier.Nama, modifier.Harga.Amount, modifier.Harga.Cur(),
+	).Scan(&modifier.ID)
+	if isUniqueViolation(err) {
+		return entity.Modifier{}, &ConflictError{Resource: "modifier", Name: modifier.Nama, Scope: "group"}
+	}
+	if err != nil {
+		return entity.Modifier{}, err
+	}
+
+	modifier.Harga.Currency = modifier.Harga.Cur()
+	return modifier, nil
+}
+
+// UpdateModifier - update nama dan harga pilihan, group_id tidak bisa dipindah
+func (r *ModifierRepository) UpdateModifier(id int, modifier entity.Modifier) (entity.Modifier, error) {
+	current, err := r.GetModifierByID(id)
+	if err != nil {
+		return entity.Modifier{}, err
+	}
+	modifier.ID = id
+	modifier.GroupID = current.GroupID
+	if err := r.checkConflict(id, modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+
+	_, err = r.db.Exec(
+		"UPDATE modifiers SET nama = $1, harga = $2, currency = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4",
+		modifier.Nama, modifier.Harga.Amount, modifier.Harga.Cur(), id,
+	)
+	if isUniqueViolation(err) {
+		return entity.Modifier{}, &ConflictError{Resource: "modifier", Name: modifier.Nama, Scope: "group"}
+	}
+	if err != nil {
+		return entity.Modifier{}, err
+	}
+
+	modifier.Harga.Currency = modifier.Harga.Cur()
+	return modifier, nil
+}
+
+// DeleteModifier - hapus pilihan, baris penjualan lama tetap ada dengan modifier_id NULL
+func (r *ModifierRepository) DeleteModifier(id int) error {
+	result, err := r.db.Exec("DELETE FROM modifiers WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrModifierNotFound
+	}
+	return nil
+}
+
+// checkConflict - cari pilihan lain dengan nama yang sama dalam grup yang sama
+func (r *ModifierRepository) checkConflict(id int, modifier entity.Modifier) error {
+	existing, err := scanModifier(r.db.QueryRow(`
+		SELECT `+modifierColumns+` FROM modifiers
+		WHERE id <> $1 AND group_id = $2 AND LOWER(nama) = LOWER($3)
+		ORDER BY id LIMIT 1`,
+		id, modifier.GroupID, modifier.Nama,
+	))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &ConflictError{Resource: "modifier", Name: modifier.Nama, Scope: "group", Existing: existing}
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/entity"
+	"time"
+)
+
+// PriceRepositoryInterface - interface untuk riwayat harga produk.
+// Menjaga rentang tetap bersambung adalah tugas service.
+type PriceRepositoryInterface interface {
+	GetByProduct(productID int) ([]entity.ProductPrice, error)
+	GetByID(id int) (entity.ProductPrice, error)
+	GetEffective(productID int, at time.Time) (entity.ProductPrice, error)
+	CurrentPrices(at time.Time) (map[int]entity.Money, error)
+	Create(price entity.ProductPrice) (entity.ProductPrice, error)
+	Update(price entity.ProductPrice) error
+	Delete(id int) error
+}
+
+// PriceRepository - struct untuk price repository
+type PriceRepository struct {
+	db DBTX
+}
+
+// NewPriceRepository - constructor untuk PriceRepository
+func NewPriceRepository(db DBTX) *PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+const priceColumns = "id, product_id, harga, currency, effective_from, effective_to, created_at"
+
+// scanPrice - scan satu baris priceColumns
+func scanPrice(row interface{ Scan(...interface{}) error }) (entity.ProductPrice, error) {
+	var p entity.ProductPrice
+	var effectiveTo sql.NullTime
+	err := row.Scan(&p.ID, &p.ProductID, &p.Harga.Amount, &p.Harga.Currency, &p.EffectiveFrom, &effectiveTo, &p.CreatedAt)
+	if err != nil {
+		return entity.ProductPrice{}, err
+	}
+	p.EffectiveFrom = p.EffectiveFrom.UTC()
+	p.CreatedAt = p.CreatedAt.UTC()
+	if effectiveTo.Valid {
+		to := effectiveTo.Time.UTC()
+		p.EffectiveTo = &to
+	}
+	return p, nil
+}
+
+// GetByProduct - semua harga produk, urut dari yang paling lama
+func (r *PriceRepository) GetByProduct(productID int) ([]entity.ProductPrice, error) {
+	rows, err := r.db.Query("SELECT "+priceColumns+" FROM product_prices WHERE product_id = $1 ORDER BY effective_from", productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []entity.ProductPrice
+	for rows.Next() {
+		p, err := scanPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// GetByID - ambil harga berdasarkan ID
+func (r *PriceRepository) GetByID(id int) (entity.ProductPrice, error) {
+	p, err := scanPrice(r.db.QueryRow("SELECT "+priceColumns+" FROM product_prices WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return entity.ProductPrice{}, ErrPriceNotFound
+	}
+	return p, err
+}
+
+// GetEffective - harga produk yang berlaku pada waktu at
+func (r *PriceRepository) GetEffective(productID int, at time.Time) (entity.ProductPrice, error) {
+	p, err := scanPrice(r.db.QueryRow(`
+		SELECT `+priceColumns+` FROM product_prices
+		WHERE product_id = $1 AND effective_from <= $2 AND (effective_to IS NULL OR effective_to > $2)
+		ORDER BY effective_from DESC LIMIT 1`, productID, at.UTC()))
+	if err == sql.ErrNoRows {
+		return entity.ProductPrice{}, ErrPriceNotFound
+	}
+	return p, err
+}
+
+// CurrentPrices - harga yang berlaku pada waktu at untuk setiap produk yang punya riwayat harga
+func (r *PriceRepository) CurrentPrices(at time.Time) (map[int]entity.Money, error) {
+	rows, err := r.db.Query(`
+		SELECT product_id, harga, currency FROM product_prices
+		WHERE effective_from <= $1 AND (effective_to IS NULL OR effective_to > $1)`, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := make(map[int]entity.Money)
+	for rows.Next() {
+		var productID int
+		var harga entity.Money
+		if err := rows.Scan(&productID, &harga.Amount, &harga.Currency); err != nil {
+			return nil, err
+		}
+		prices[productID] = harga
+	}
+	return prices, rows.Err()
+}
+
+// Create - tambah harga baru
+func (r *PriceRepository) Create(price entity.ProductPrice) (entity.ProductPrice, error) {
+	price.Harga.Currency = price.Harga.Cur()
+	price.EffectiveFrom = price.EffectiveFrom.UTC().Truncate(time.Microsecond)
+	price.EffectiveTo = utcTime(price.EffectiveTo)
+	if price.CreatedAt.IsZero() {
+		price.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+
+	err := r.db.QueryRow(`
+		INSERT INTO product_prices (product_id, harga, currency, effective_from, effective_to, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		price.ProductID, price.Harga.Amount, price.Harga.Currency, price.EffectiveFrom, nullableTime(price.EffectiveTo), price.CreatedAt,
+	).Scan(&price.ID)
+	if err != nil {
+		return entity.ProductPrice{}, err
+	}
+	return price, nil
+}
+
+// Update - ubah harga dan akhir rentang, awal rentang tidak bisa diubah
+func (r *PriceRepository) Update(price entity.ProductPrice) error {
+	result, err := r.db.Exec(
+		"UPDATE product_prices SET harga = $1, currency = $2, effective_to = $3 WHERE id = $4",
+		price.Harga.Amount, price.Harga.Cur(), nullableTime(utcTime(price.EffectiveTo)), price.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPriceNotFound
+	}
+	return nil
+}
+
+// Delete - hapus harga
+func (r *PriceRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM product_prices WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrPriceNotFound
+	}
+	return nil
+}
+
+// utcTime - salinan waktu dalam UTC dengan presisi mikrodetik seperti kolom TIMESTAMP
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC().Truncate(time.Microsecond)
+	return &utc
+}
+
+// nullableTime - nil menjadi NULL
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
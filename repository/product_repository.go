@@ -2,32 +2,157 @@ package repository
 
 import (
 	"database/sql"
-	"errors"
+	"fmt"
 	"kasir-api/entity"
+	"strings"
 )
 
 // ProductRepositoryInterface - interface untuk product repository
 type ProductRepositoryInterface interface {
-	GetAll() ([]entity.Product, error)
+	GetAll(filter entity.ProductFilter) ([]entity.Product, error)
 	GetByID(id int) (entity.Product, error)
+	GetAllWithCategory(filter entity.ProductFilter) ([]entity.Product, error)
+	GetByIDWithCategory(id int) (entity.Product, error)
 	Create(product entity.Product) (entity.Product, error)
 	Update(id int, product entity.Product) (entity.Product, error)
 	Delete(id int) error
+	ReassignCategory(fromCategoryID int, toCategoryID *int) (int, error)
+	SoftDeleteByCategory(categoryID int) (int, error)
+	AdjustStock(id int, delta entity.Quantity) (entity.Product, error)
+	SetImage(id int, image *entity.ProductImage) error
 }
 
 // ProductRepository - struct untuk product repository
 type ProductRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 // NewProductRepository - constructor untuk ProductRepository
-func NewProductRepository(db *sql.DB) *ProductRepository {
+func NewProductRepository(db DBTX) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-// GetAll - ambil semua produk
-func (r *ProductRepository) GetAll() ([]entity.Product, error) {
-	rows, err := r.db.Query("SELECT id, nama, harga, category_id FROM products")
+// productFilterClause - bangun WHERE clause dari filter, alias = prefix kolom tabel products.
+// Produk yang sudah di-soft delete tidak pernah ikut.
+func productFilterClause(filter entity.ProductFilter, alias string) (string, []interface{}) {
+	conditions := []string{alias + "deleted_at IS NULL"}
+	var args []interface{}
+
+	if filter.Uncategorized {
+		conditions = append(conditions, alias+"category_id IS NULL")
+	}
+	if filter.CategoryID != 0 {
+		args = append(args, filter.CategoryID)
+		if filter.IncludeSubcategories {
+			conditions = append(conditions, fmt.Sprintf("%scategory_id IN (%s)", alias, fmt.Sprintf(subtreeQuery, len(args))))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("%scategory_id = $%d", alias, len(args)))
+		}
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// subtreeQuery - ID kategori beserta semua turunannya (recursive CTE).
+// UNION membuang duplikat sehingga rekursi tetap berhenti walau data berisi siklus.
+const subtreeQuery = `
+	WITH RECURSIVE subtree (id) AS (
+		SELECT id FROM categories WHERE id = $%d
+		UNION
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree`
+
+// productType - jenis produk yang disimpan, kosong berarti produk biasa
+func productType(p entity.Product) string {
+	if p.Type == "" {
+		return entity.ProductTypeSingle
+	}
+	return p.Type
+}
+
+// productUnit - satuan dasar yang disimpan, kosong berarti entity.DefaultUnit
+func productUnit(p entity.Product) string {
+	if p.Unit == "" {
+		return entity.DefaultUnit
+	}
+	return p.Unit
+}
+
+// nullableString - string kosong menjadi NULL
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// normalizeCurrency - isi mata uang, jenis dan satuan produk seperti yang tersimpan, harga beli mengikuti harga jual
+func normalizeCurrency(p *entity.Product) {
+	p.Type = productType(*p)
+	p.Unit = productUnit(*p)
+	p.Harga.Currency = p.Harga.Cur()
+	if p.HargaBeli != nil {
+		cost := entity.NewMoney(p.HargaBeli.Amount, p.Harga.Currency)
+		p.HargaBeli = &cost
+	}
+}
+
+// nullableMoney - kolom uang nullable ke *Money dengan mata uang currency (nil untuk NULL)
+func nullableMoney(v sql.NullInt64, currency string) *entity.Money {
+	if !v.Valid {
+		return nil
+	}
+	m := entity.NewMoney(v.Int64, currency)
+	return &m
+}
+
+// moneyAmount - minor unit untuk kolom uang nullable, nil menjadi NULL
+func moneyAmount(m *entity.Money) interface{} {
+	if m == nil {
+		return nil
+	}
+	return m.Amount
+}
+
+// nullableQuantity - kolom jumlah nullable (seperseribu unit) ke *Quantity (nil untuk NULL)
+func nullableQuantity(v sql.NullInt64) *entity.Quantity {
+	if !v.Valid {
+		return nil
+	}
+	q := entity.Quantity(v.Int64)
+	return &q
+}
+
+// quantityValue - seperseribu unit untuk kolom jumlah nullable, nil menjadi NULL
+func quantityValue(q *entity.Quantity) interface{} {
+	if q == nil {
+		return nil
+	}
+	return int64(*q)
+}
+
+// nullableImage - kolom gambar nullable ke *ProductImage (nil jika belum ada gambar)
+func nullableImage(key, thumbnailKey sql.NullString) *entity.ProductImage {
+	if !key.Valid {
+		return nil
+	}
+	return &entity.ProductImage{Key: key.String, ThumbnailKey: thumbnailKey.String}
+}
+
+// nullableInt - konversi kolom nullable ke *int (nil untuk NULL)
+func nullableInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
+
+// GetAll - ambil semua produk sesuai filter
+func (r *ProductRepository) GetAll(filter entity.ProductFilter) ([]entity.Product, error) {
+	where, args := productFilterClause(filter, "")
+	rows, err := r.db.Query("SELECT id, nama, type, harga, currency, harga_beli, stock, unit, purchase_unit, image_key, thumbnail_key, category_id FROM products"+where+" ORDER BY id", args...)
 	if err != nil {
 		return nil, err
 	}
@@ -36,10 +161,19 @@ func (r *ProductRepository) GetAll() ([]entity.Product, error) {
 	var products []entity.Product
 	for rows.Next() {
 		var p entity.Product
-		err := rows.Scan(&p.ID, &p.Nama, &p.Harga, &p.CategoryID)
+		var categoryID, hargaBeli, stock sql.NullInt64
+		var unit, purchaseUnit, imageKey, thumbnailKey sql.NullString
+		err := rows.Scan(&p.ID, &p.Nama, &p.Type, &p.Harga.Amount, &p.Harga.Currency, &hargaBeli, &stock, &unit, &purchaseUnit, &imageKey, &thumbnailKey, &categoryID)
 		if err != nil {
 			return nil, err
 		}
+		// category_id NULL untuk produk tanpa kategori (ON DELETE SET NULL)
+		p.CategoryID = nullableInt(categoryID)
+		p.HargaBeli = nullableMoney(hargaBeli, p.Harga.Currency)
+		p.Stock = nullableQuantity(stock)
+		p.Unit, p.PurchaseUnit = unit.String, purchaseUnit.String
+		p.Image = nullableImage(imageKey, thumbnailKey)
+		normalizeCurrency(&p)
 		products = append(products, p)
 	}
 
@@ -49,42 +183,136 @@ func (r *ProductRepository) GetAll() ([]entity.Product, error) {
 // GetByID - ambil produk berdasarkan ID
 func (r *ProductRepository) GetByID(id int) (entity.Product, error) {
 	var p entity.Product
+	var categoryID, hargaBeli, stock sql.NullInt64
+	var unit, purchaseUnit, imageKey, thumbnailKey sql.NullString
 	err := r.db.QueryRow(
-		"SELECT id, nama, harga, category_id FROM products WHERE id = $1", id,
-	).Scan(&p.ID, &p.Nama, &p.Harga, &p.CategoryID)
+		"SELECT id, nama, type, harga, currency, harga_beli, stock, unit, purchase_unit, image_key, thumbnail_key, category_id FROM products WHERE id = $1 AND deleted_at IS NULL", id,
+	).Scan(&p.ID, &p.Nama, &p.Type, &p.Harga.Amount, &p.Harga.Currency, &hargaBeli, &stock, &unit, &purchaseUnit, &imageKey, &thumbnailKey, &categoryID)
 	
 	if err == sql.ErrNoRows {
-		return entity.Product{}, errors.New("product not found")
+		return entity.Product{}, ErrProductNotFound
 	}
 	if err != nil {
 		return entity.Product{}, err
 	}
+	p.CategoryID = nullableInt(categoryID)
+	p.HargaBeli = nullableMoney(hargaBeli, p.Harga.Currency)
+	p.Stock = nullableQuantity(stock)
+	p.Unit, p.PurchaseUnit = unit.String, purchaseUnit.String
+	p.Image = nullableImage(imageKey, thumbnailKey)
+	normalizeCurrency(&p)
 	
 	return p, nil
 }
 
-// Create - tambah produk baru
+// productWithCategoryQuery - SELECT produk dengan LEFT JOIN kategori dalam satu query
+const productWithCategoryQuery = `
+	SELECT p.id, p.nama, p.type, p.harga, p.currency, p.harga_beli, p.stock, p.unit, p.purchase_unit, p.image_key, p.thumbnail_key, p.category_id, c.id, c.name, c.description, c.parent_id
+	FROM products p
+	LEFT JOIN categories c ON c.id = p.category_id`
+
+// scanProductWithCategory - scan satu baris hasil productWithCategoryQuery
+func scanProductWithCategory(row interface{ Scan(dest ...interface{}) error }) (entity.Product, error) {
+	var p entity.Product
+	var categoryID, joinedID, parentID, hargaBeli, stock sql.NullInt64
+	var name, description, unit, purchaseUnit, imageKey, thumbnailKey sql.NullString
+	err := row.Scan(&p.ID, &p.Nama, &p.Type, &p.Harga.Amount, &p.Harga.Currency, &hargaBeli, &stock, &unit, &purchaseUnit, &imageKey, &thumbnailKey, &categoryID, &joinedID, &name, &description, &parentID)
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	p.CategoryID = nullableInt(categoryID)
+	p.HargaBeli = nullableMoney(hargaBeli, p.Harga.Currency)
+	p.Stock = nullableQuantity(stock)
+	p.Unit, p.PurchaseUnit = unit.String, purchaseUnit.String
+	p.Image = nullableImage(imageKey, thumbnailKey)
+	normalizeCurrency(&p)
+	if joinedID.Valid {
+		p.Category = &entity.Category{
+			ID:          int(joinedID.Int64),
+			Name:        name.String,
+			Description: description.String,
+			ParentID:    nullableInt(parentID),
+		}
+	}
+	return p, nil
+}
+
+// GetAllWithCategory - ambil semua produk sesuai filter beserta kategorinya (JOIN, tanpa N+1 query)
+func (r *ProductRepository) GetAllWithCategory(filter entity.ProductFilter) ([]entity.Product, error) {
+	where, args := productFilterClause(filter, "p.")
+	rows, err := r.db.Query(productWithCategoryQuery+where+" ORDER BY p.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []entity.Product
+	for rows.Next() {
+		p, err := scanProductWithCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+
+	return products, rows.Err()
+}
+
+// GetByIDWithCategory - ambil produk berdasarkan ID beserta kategorinya (JOIN)
+func (r *ProductRepository) GetByIDWithCategory(id int) (entity.Product, error) {
+	p, err := scanProductWithCategory(
+		r.db.QueryRow(productWithCategoryQuery+" WHERE p.id = $1 AND p.deleted_at IS NULL", id),
+	)
+	if err == sql.ErrNoRows {
+		return entity.Product{}, ErrProductNotFound
+	}
+	if err != nil {
+		return entity.Product{}, err
+	}
+
+	return p, nil
+}
+
+// Create - tambah produk baru, nama harus unik (case-insensitive) dalam kategori yang sama
 func (r *ProductRepository) Create(product entity.Product) (entity.Product, error) {
+	if err := r.checkConflict(0, product); err != nil {
+		return entity.Product{}, err
+	}
+
 	var id int
 	err := r.db.QueryRow(
-		"INSERT INTO products (nama, harga, category_id) VALUES ($1, $2, $3) RETURNING id",
-		product.Nama, product.Harga, product.CategoryID,
+		"INSERT INTO products (nama, type, harga, currency, harga_beli, stock, unit, purchase_unit, category_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id",
+		product.Nama, productType(product), product.Harga.Amount, product.Harga.Cur(), moneyAmount(product.HargaBeli), quantityValue(product.Stock),
+		productUnit(product), nullableString(product.PurchaseUnit), product.CategoryID,
 	).Scan(&id)
 	
+	if isUniqueViolation(err) {
+		return entity.Product{}, productConflict(product, nil)
+	}
 	if err != nil {
 		return entity.Product{}, err
 	}
 	
 	product.ID = id
+	normalizeCurrency(&product)
 	return product, nil
 }
 
-// Update - update produk
+// Update - update produk, nama harus unik (case-insensitive) dalam kategori yang sama
 func (r *ProductRepository) Update(id int, product entity.Product) (entity.Product, error) {
+	if err := r.checkConflict(id, product); err != nil {
+		return entity.Product{}, err
+	}
+
 	result, err := r.db.Exec(
-		"UPDATE products SET nama = $1, harga = $2, category_id = $3 WHERE id = $4",
-		product.Nama, product.Harga, product.CategoryID, id,
+		"UPDATE products SET nama = $1, type = $2, harga = $3, currency = $4, harga_beli = $5, stock = $6, unit = $7, purchase_unit = $8, category_id = $9, updated_at = CURRENT_TIMESTAMP WHERE id = $10 AND deleted_at IS NULL",
+		product.Nama, productType(product), product.Harga.Amount, product.Harga.Cur(), moneyAmount(product.HargaBeli), quantityValue(product.Stock),
+		productUnit(product), nullableString(product.PurchaseUnit), product.CategoryID, id,
 	)
+	if isUniqueViolation(err) {
+		return entity.Product{}, productConflict(product, nil)
+	}
 	if err != nil {
 		return entity.Product{}, err
 	}
@@ -94,16 +322,17 @@ func (r *ProductRepository) Update(id int, product entity.Product) (entity.Produ
 		return entity.Product{}, err
 	}
 	if rowsAffected == 0 {
-		return entity.Product{}, errors.New("product not found")
+		return entity.Product{}, ErrProductNotFound
 	}
 
 	product.ID = id
+	normalizeCurrency(&product)
 	return product, nil
 }
 
 // Delete - hapus produk
 func (r *ProductRepository) Delete(id int) error {
-	result, err := r.db.Exec("DELETE FROM products WHERE id = $1", id)
+	result, err := r.db.Exec("DELETE FROM products WHERE id = $1 AND deleted_at IS NULL", id)
 	if err != nil {
 		return err
 	}
@@ -113,8 +342,147 @@ func (r *ProductRepository) Delete(id int) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("product not found")
+		return ErrProductNotFound
 	}
 
 	return nil
 }
+
+// ReassignCategory - pindahkan semua produk aktif dari satu kategori ke kategori lain (nil = tanpa kategori)
+func (r *ProductRepository) ReassignCategory(fromCategoryID int, toCategoryID *int) (int, error) {
+	if err := r.checkReassignConflict(fromCategoryID, toCategoryID); err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Exec(
+		"UPDATE products SET category_id = $1, updated_at = CURRENT_TIMESTAMP WHERE category_id = $2 AND deleted_at IS NULL",
+		toCategoryID, fromCategoryID,
+	)
+	if isUniqueViolation(err) {
+		return 0, productConflict(entity.Product{CategoryID: toCategoryID}, nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	return int(rowsAffected), err
+}
+
+// SoftDeleteByCategory - tandai semua produk aktif dalam kategori sebagai terhapus
+func (r *ProductRepository) SoftDeleteByCategory(categoryID int) (int, error) {
+	result, err := r.db.Exec(
+		"UPDATE products SET deleted_at = CURRENT_TIMESTAMP WHERE category_id = $1 AND deleted_at IS NULL",
+		categoryID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	return int(rowsAffected), err
+}
+
+// AdjustStock - tambah atau kurangi stok produk (dalam satuan dasar) secara atomik. Stok tidak boleh minus:
+// ErrInsufficientStock dan stok tidak berubah. ErrStockNotTracked jika stok NULL.
+func (r *ProductRepository) AdjustStock(id int, delta entity.Quantity) (entity.Product, error) {
+	var stock int64
+	err := r.db.QueryRow(`
+		UPDATE products SET stock = stock + $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND deleted_at IS NULL AND stock IS NOT NULL AND stock + $1 >= 0
+		RETURNING stock`, int64(delta), id,
+	).Scan(&stock)
+	if err != nil && err != sql.ErrNoRows {
+		return entity.Product{}, err
+	}
+
+	p, getErr := r.GetByID(id)
+	if getErr != nil {
+		return entity.Product{}, getErr
+	}
+	if err == nil {
+		return p, nil
+	}
+	if p.Stock == nil {
+		return entity.Product{}, ErrStockNotTracked
+	}
+	return entity.Product{}, ErrInsufficientStock
+}
+
+// SetImage - ganti gambar produk, nil menghapus gambar. File di storage diurus oleh service.
+func (r *ProductRepository) SetImage(id int, image *entity.ProductImage) error {
+	var key, thumbnailKey interface{}
+	if image != nil {
+		key, thumbnailKey = image.Key, nullableString(image.ThumbnailKey)
+	}
+	result, err := r.db.Exec(
+		"UPDATE products SET image_key = $1, thumbnail_key = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 AND deleted_at IS NULL",
+		key, thumbnailKey, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// checkConflict - cari produk aktif lain dengan nama sama dalam kategori yang sama (id = produk yang dikecualikan)
+func (r *ProductRepository) checkConflict(id int, product entity.Product) error {
+	var existing entity.Product
+	var categoryID sql.NullInt64
+	err := r.db.QueryRow(`
+		SELECT id, nama, type, harga, currency, category_id FROM products
+		WHERE COALESCE(category_id, 0) = COALESCE($1, 0) AND LOWER(nama) = LOWER($2) AND id <> $3 AND deleted_at IS NULL
+		LIMIT 1`,
+		product.CategoryID, product.Nama, id,
+	).Scan(&existing.ID, &existing.Nama, &existing.Type, &existing.Harga.Amount, &existing.Harga.Currency, &categoryID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing.CategoryID = nullableInt(categoryID)
+	return productConflict(product, &existing)
+}
+
+// checkReassignConflict - produk aktif di fromCategoryID yang namanya sudah dipakai produk aktif
+// di kategori tujuan
+func (r *ProductRepository) checkReassignConflict(fromCategoryID int, toCategoryID *int) error {
+	var moving, existing entity.Product
+	var categoryID sql.NullInt64
+	err := r.db.QueryRow(`
+		SELECT p.nama, q.id, q.nama, q.type, q.harga, q.currency, q.category_id
+		FROM products p
+		JOIN products q ON LOWER(q.nama) = LOWER(p.nama) AND COALESCE(q.category_id, 0) = COALESCE($2, 0)
+			AND q.id <> p.id AND q.deleted_at IS NULL
+		WHERE p.category_id = $1 AND p.deleted_at IS NULL
+		LIMIT 1`,
+		fromCategoryID, toCategoryID,
+	).Scan(&moving.Nama, &existing.ID, &existing.Nama, &existing.Type, &existing.Harga.Amount, &existing.Harga.Currency, &categoryID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	moving.CategoryID = toCategoryID
+	existing.CategoryID = nullableInt(categoryID)
+	return productConflict(moving, &existing)
+}
+
+// productConflict - ConflictError untuk nama produk, existing nil jika tidak diketahui
+func productConflict(product entity.Product, existing *entity.Product) *ConflictError {
+	err := &ConflictError{Resource: "product", Name: product.Nama, Scope: "category"}
+	if existing != nil {
+		err.Existing = *existing
+	}
+	return err
+}
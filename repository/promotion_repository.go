@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/entity"
+	"time"
+)
+
+// PromotionRepositoryInterface - interface untuk promotion repository
+type PromotionRepositoryInterface interface {
+	GetAll() ([]entity.Promotion, error)
+	GetActive(at time.Time) ([]entity.Promotion, error)
+	GetByID(id int) (entity.Promotion, error)
+	Create(promotion entity.Promotion) (entity.Promotion, error)
+	Update(id int, promotion entity.Promotion) (entity.Promotion, error)
+	Delete(id int) error
+}
+
+// PromotionRepository - struct untuk promotion repository
+type PromotionRepository struct {
+	db DBTX
+}
+
+// NewPromotionRepository - constructor untuk PromotionRepository
+func NewPromotionRepository(db DBTX) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `id, nama, type, scope, product_id, category_id, percent, amount, min_subtotal, currency,
+	buy_quantity, get_quantity, starts_at, ends_at, days, priority, stackable`
+
+// scanPromotion - scan satu baris promotionColumns
+func scanPromotion(row interface{ Scan(...interface{}) error }) (entity.Promotion, error) {
+	var p entity.Promotion
+	var productID, categoryID, percent, amount, minSubtotal, buy, get sql.NullInt64
+	var startsAt, endsAt sql.NullTime
+	var currency string
+	var days int
+	err := row.Scan(&p.ID, &p.Nama, &p.Type, &p.Scope, &productID, &categoryID, &percent, &amount, &minSubtotal, &currency,
+		&buy, &get, &startsAt, &endsAt, &days, &p.Priority, &p.Stackable)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	p.ProductID = nullableInt(productID)
+	p.CategoryID = nullableInt(categoryID)
+	p.Percent = entity.Percent(percent.Int64)
+	p.Amount = nullableMoney(amount, currency)
+	p.MinSubtotal = nullableMoney(minSubtotal, currency)
+	p.BuyQuantity, p.GetQuantity = int(buy.Int64), int(get.Int64)
+	if startsAt.Valid {
+		p.StartsAt = utcTime(&startsAt.Time)
+	}
+	if endsAt.Valid {
+		p.EndsAt = utcTime(&endsAt.Time)
+	}
+	p.Days = weekdays(days)
+	return p, nil
+}
+
+// promotionValues - nilai kolom promosi setelah nama, urut seperti promotionColumns
+func promotionValues(p entity.Promotion) []interface{} {
+	return []interface{}{
+		p.Type, p.Scope, p.ProductID, p.CategoryID, nullablePositive(int64(p.Percent)),
+		moneyAmount(p.Amount), moneyAmount(p.MinSubtotal), promotionCurrency(p),
+		nullablePositive(int64(p.BuyQuantity)), nullablePositive(int64(p.GetQuantity)),
+		nullableTime(utcTime(p.StartsAt)), nullableTime(utcTime(p.EndsAt)), weekdayMask(p.Days), p.Priority, p.Stackable,
+	}
+}
+
+// promotionCurrency - mata uang amount dan min_subtotal, default IDR
+func promotionCurrency(p entity.Promotion) string {
+	if p.Amount != nil {
+		return p.Amount.Cur()
+	}
+	if p.MinSubtotal != nil {
+		return p.MinSubtotal.Cur()
+	}
+	return entity.DefaultCurrency
+}
+
+// nullablePositive - 0 menjadi NULL untuk kolom opsional yang harus positif
+func nullablePositive(n int64) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+// weekdayMask - hari 0 (Minggu) sampai 6 (Sabtu) menjadi bitmask kolom days
+func weekdayMask(days []int) int {
+	mask := 0
+	for _, day := range days {
+		mask |= 1 << day
+	}
+	return mask
+}
+
+// weekdays - kebalikan weekdayMask, urut dari Minggu; nil untuk setiap hari
+func weekdays(mask int) []int {
+	var days []int
+	for day := 0; day < 7; day++ {
+		if mask&(1<<day) != 0 {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// query - jalankan query yang mengembalikan promotionColumns
+func (r *PromotionRepository) query(query string, args ...interface{}) ([]entity.Promotion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []entity.Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+// GetAll - semua promosi, urut berdasarkan ID
+func (r *PromotionRepository) GetAll() ([]entity.Promotion, error) {
+	return r.query("SELECT " + promotionColumns + " FROM promotions ORDER BY id")
+}
+
+// GetActive - promosi yang berlaku pada waktu at menurut starts_at dan ends_at, urut
+// berdasarkan priority (tertinggi dulu) lalu ID. Hari berlaku dicek oleh service.
+func (r *PromotionRepository) GetActive(at time.Time) ([]entity.Promotion, error) {
+	return r.query(`
+		SELECT `+promotionColumns+` FROM promotions
+		WHERE (starts_at IS NULL OR starts_at <= $1) AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY priority DESC, id`, at.UTC())
+}
+
+// GetByID - ambil promosi berdasarkan ID
+func (r *PromotionRepository) GetByID(id int) (entity.Promotion, error) {
+	p, err := scanPromotion(r.db.QueryRow("SELECT "+promotionColumns+" FROM promotions WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return entity.Promotion{}, ErrPromotionNotFound
+	}
+	return p, err
+}
+
+// Create - tambah promosi baru
+func (r *PromotionRepository) Create(promotion entity.Promotion) (entity.Promotion, error) {
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO promotions (nama, type, scope, product_id, category_id, percent, amount, min_subtotal, currency,
+			buy_quantity, get_quantity, starts_at, ends_at, days, priority, stackable)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING id`,
+		append([]interface{}{promotion.Nama}, promotionValues(promotion)...)...,
+	).Scan(&id)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	return r.GetByID(id)
+}
+
+// Update - ubah promosi
+func (r *PromotionRepository) Update(id int, promotion entity.Promotion) (entity.Promotion, error) {
+	args := append([]interface{}{promotion.Nama}, promotionValues(promotion)...)
+	result, err := r.db.Exec(`
+		UPDATE promotions SET nama = $1, type = $2, scope = $3, product_id = $4, category_id = $5, percent = $6,
+			amount = $7, min_subtotal = $8, currency = $9, buy_quantity = $10, get_quantity = $11,
+			starts_at = $12, ends_at = $13, days = $14, priority = $15, stackable = $16, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $17`,
+		append(args, id)...,
+	)
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return entity.Promotion{}, err
+	}
+	if rowsAffected == 0 {
+		return entity.Promotion{}, ErrPromotionNotFound
+	}
+	return r.GetByID(id)
+}
+
+// Delete - hapus promosi, transaksi lama tetap menyimpan nama dan potongannya
+func (r *PromotionRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM promotions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrPromotionNotFound
+	}
+	return nil
+}
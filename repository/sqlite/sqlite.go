@@ -0,0 +1,188 @@
+// Package sqlite lets the PostgreSQL repositories run on SQLite for
+// single-terminal stores. It registers a database/sql driver that wraps
+// modernc.org/sqlite (pure Go, no cgo) and rewrites PostgreSQL "$1"
+// placeholders to SQLite "?1", so the same SQL works on both databases.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	msqlite "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// DriverName is the database/sql driver name registered by this package
+const DriverName = "kasir-sqlite"
+
+func init() {
+	sql.Register(DriverName, &Driver{inner: &msqlite.Driver{}})
+}
+
+// IsURL reports whether a DATABASE_URL points at SQLite
+func IsURL(databaseURL string) bool {
+	return strings.HasPrefix(databaseURL, "sqlite:") || strings.HasPrefix(databaseURL, "file:")
+}
+
+// IsUniqueViolation reports whether err is an SQLite UNIQUE constraint failure
+func IsUniqueViolation(err error) bool {
+	var e *msqlite.Error
+	return errors.As(err, &e) && e.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// Open opens an SQLite database from a URL like sqlite://data/kasir.db,
+// sqlite:///var/lib/kasir.db or sqlite::memory:
+func Open(databaseURL string) (*sql.DB, error) {
+	path, query := parseURL(databaseURL)
+	if path != ":memory:" {
+		if dir := filepath.Dir(path); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Foreign key wajib aktif agar ON DELETE SET NULL / CASCADE berjalan
+	pragmas := []string{"_pragma=foreign_keys(1)", "_pragma=busy_timeout(5000)"}
+	if path != ":memory:" {
+		pragmas = append(pragmas, "_pragma=journal_mode(WAL)")
+	}
+	if query != "" {
+		pragmas = append(pragmas, query)
+	}
+
+	db, err := sql.Open(DriverName, "file:"+path+"?"+strings.Join(pragmas, "&"))
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite hanya mengizinkan satu writer, dan :memory: berbeda per koneksi.
+	// Satu koneksi cukup untuk satu kasir dan menghindari "database is locked".
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// IsSQLite reports whether db was opened through this package
+func IsSQLite(db *sql.DB) bool {
+	_, ok := db.Driver().(*Driver)
+	return ok
+}
+
+// parseURL splits an SQLite URL into file path and extra query parameters
+func parseURL(databaseURL string) (path, query string) {
+	path = databaseURL
+	for _, prefix := range []string{"sqlite://", "sqlite:", "file://", "file:"} {
+		if strings.HasPrefix(path, prefix) {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	if idx := strings.Index(path, "?"); idx != -1 {
+		path, query = path[:idx], path[idx+1:]
+	}
+	return path, query
+}
+
+// Rebind converts PostgreSQL placeholders ($1, $2, ...) into SQLite numbered
+// placeholders (?1, ?2, ...), leaving string literals and identifiers untouched
+func Rebind(query string) string {
+	if !strings.Contains(query, "$") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '$' && i+1 < len(query) && query[i+1] >= '0' && query[i+1] <= '9':
+			ch = '?'
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// Driver wraps the modernc SQLite driver and rebinds every query
+type Driver struct {
+	inner driver.Driver
+}
+
+// Open opens a new connection
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	c, err := d.inner.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: c}, nil
+}
+
+// conn rebinds queries before handing them to the wrapped connection
+type conn struct {
+	driver.Conn
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(Rebind(query))
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, Rebind(query))
+	}
+	return c.Prepare(query)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, Rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, Rebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/entity"
+)
+
+// TaxRateRepositoryInterface - interface untuk tax rate repository
+type TaxRateRepositoryInterface interface {
+	GetAll() ([]entity.TaxRate, error)
+	GetByID(id int) (entity.TaxRate, error)
+	Create(rate entity.TaxRate) (entity.TaxRate, error)
+	Update(id int, rate entity.TaxRate) (entity.TaxRate, error)
+	Delete(id int) error
+}
+
+// TaxRateRepository - struct untuk tax rate repository
+type TaxRateRepository struct {
+	db DBTX
+}
+
+// NewTaxRateRepository - constructor untuk TaxRateRepository
+func NewTaxRateRepository(db DBTX) *TaxRateRepository {
+	return &TaxRateRepository{db: db}
+}
+
+const taxRateColumns = "id, nama, rate, inclusive, compound, position"
+
+// GetAll - semua tarif pajak dalam urutan hitung (position, lalu ID)
+func (r *TaxRateRepository) GetAll() ([]entity.TaxRate, error) {
+	rows, err := r.db.Query("SELECT " + taxRateColumns + " FROM tax_rates ORDER BY position, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []entity.TaxRate
+	index := make(map[int]int)
+	for rows.Next() {
+		var t entity.TaxRate
+		if err := rows.Scan(&t.ID, &t.Nama, &t.Rate, &t.Inclusive, &t.Compound, &t.Position); err != nil {
+			return nil, err
+		}
+		t.ExemptCategoryIDs = []int{}
+		index[t.ID] = len(rates)
+		rates = append(rates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	exemptions, err := r.db.Query("SELECT tax_rate_id, category_id FROM tax_rate_exemptions ORDER BY category_id")
+	if err != nil {
+		return nil, err
+	}
+	defer exemptions.Close()
+	for exemptions.Next() {
+		var rateID, categoryID int
+		if err := exemptions.Scan(&rateID, &categoryID); err != nil {
+			return nil, err
+		}
+		if i, ok := index[rateID]; ok {
+			rates[i].ExemptCategoryIDs = append(rates[i].ExemptCategoryIDs, categoryID)
+		}
+	}
+	return rates, exemptions.Err()
+}
+
+// GetByID - ambil tarif pajak beserta kategori yang dibebaskan
+func (r *TaxRateRepository) GetByID(id int) (entity.TaxRate, error) {
+	var t entity.TaxRate
+	err := r.db.QueryRow("SELECT "+taxRateColumns+" FROM tax_rates WHERE id = $1", id).
+		Scan(&t.ID, &t.Nama, &t.Rate, &t.Inclusive, &t.Compound, &t.Position)
+	if err == sql.ErrNoRows {
+		return entity.TaxRate{}, ErrTaxRateNotFound
+	}
+	if err != nil {
+		return entity.TaxRate{}, err
+	}
+
+	rows, err := r.db.Query("SELECT category_id FROM tax_rate_exemptions WHERE tax_rate_id = $1 ORDER BY category_id", id)
+	if err != nil {
+		return entity.TaxRate{}, err
+	}
+	defer rows.Close()
+	t.ExemptCategoryIDs = []int{}
+	for rows.Next() {
+		var categoryID int
+		if err := rows.Scan(&categoryID); err != nil {
+			return entity.TaxRate{}, err
+		}
+		t.ExemptCategoryIDs = append(t.ExemptCategoryIDs, categoryID)
+	}
+	return t, rows.Err()
+}
+
+// Create - tambah tarif pajak, nama unik. Jalankan di dalam TxManager agar atomik.
+func (r *TaxRateRepository) Create(rate entity.TaxRate) (entity.TaxRate, error) {
+	if err := r.checkNama(0, rate.Nama); err != nil {
+		return entity.TaxRate{}, err
+	}
+
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO tax_rates (nama, rate, inclusive, compound, position)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		rate.Nama, int64(rate.Rate), rate.Inclusive, rate.Compound, rate.Position,
+	).Scan(&id)
+	if isUniqueViolation(err) {
+		return entity.TaxRate{}, &ConflictError{Resource: "tax rate", Name: rate.Nama, Scope: "store"}
+	}
+	if err != nil {
+		return entity.TaxRate{}, err
+	}
+	if err := r.replaceExemptions(id, rate.ExemptCategoryIDs); err != nil {
+		return entity.TaxRate{}, err
+	}
+	return r.GetByID(id)
+}
+
+// Update - ubah tarif pajak dan ganti daftar kategori yang dibebaskan. Transaksi lama
+// tidak berubah. Jalankan di dalam TxManager agar atomik.
+func (r *TaxRateRepository) Update(id int, rate entity.TaxRate) (entity.TaxRate, error) {
+	if err := r.checkNama(id, rate.Nama); err != nil {
+		return entity.TaxRate{}, err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE tax_rates SET nama = $1, rate = $2, inclusive = $3, compound = $4, position = $5,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6`,
+		rate.Nama, int64(rate.Rate), rate.Inclusive, rate.Compound, rate.Position, id,
+	)
+	if isUniqueViolation(err) {
+		return entity.TaxRate{}, &ConflictError{Resource: "tax rate", Name: rate.Nama, Scope: "store"}
+	}
+	if err != nil {
+		return entity.TaxRate{}, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return entity.TaxRate{}, err
+	}
+	if rowsAffected == 0 {
+		return entity.TaxRate{}, ErrTaxRateNotFound
+	}
+	if err := r.replaceExemptions(id, rate.ExemptCategoryIDs); err != nil {
+		return entity.TaxRate{}, err
+	}
+	return r.GetByID(id)
+}
+
+// Delete - hapus tarif pajak, pajak di transaksi lama tetap ada
+func (r *TaxRateRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM tax_rates WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrTaxRateNotFound
+	}
+	return nil
+}
+
+// checkNama - ConflictError beserta record yang bentrok jika nama sudah dipakai tarif lain
+func (r *TaxRateRepository) checkNama(id int, nama string) error {
+	var existingID int
+	err := r.db.QueryRow("SELECT id FROM tax_rates WHERE nama = $1 AND id <> $2", nama, id).Scan(&existingID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := r.GetByID(existingID)
+	if err != nil {
+		return err
+	}
+	return &ConflictError{Resource: "tax rate", Name: nama, Scope: "store", Existing: existing}
+}
+
+// replaceExemptions - ganti semua kategori yang dibebaskan dari tarif id
+func (r *TaxRateRepository) replaceExemptions(id int, categoryIDs []int) error {
+	if _, err := r.db.Exec("DELETE FROM tax_rate_exemptions WHERE tax_rate_id = $1", id); err != nil {
+		return err
+	}
+	for _, categoryID := range categoryIDs {
+		_, err := r.db.Exec("INSERT INTO tax_rate_exemptions (tax_rate_id, category_id) VALUES ($1, $2)", id, categoryID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/entity"
+	"strings"
+	"time"
+)
+
+// TransactionRepositoryInterface - interface untuk transaction repository
+type TransactionRepositoryInterface interface {
+	Create(transaction entity.Transaction) (entity.Transaction, error)
+	GetByID(id int) (entity.Transaction, error)
+	MarginReport(filter entity.MarginFilter) ([]entity.MarginRow, error)
+	TaxReport(filter entity.TaxFilter) ([]entity.TaxRow, error)
+}
+
+// TransactionRepository - struct untuk transaction repository
+type TransactionRepository struct {
+	db DBTX
+}
+
+// NewTransactionRepository - constructor untuk TransactionRepository
+func NewTransactionRepository(db DBTX) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+// Create - simpan transaksi beserta detailnya. Jalankan di dalam TxManager agar atomik.
+func (r *TransactionRepository) Create(transaction entity.Transaction) (entity.Transaction, error) {
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	normalizeTotals(&transaction)
+
+	err := r.db.QueryRow(`
+		INSERT INTO transactions (subtotal, discount_amount, tax_amount, total_amount, currency, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		transaction.Subtotal.Amount, transaction.DiscountAmount.Amount, transaction.TaxAmount.Amount,
+		transaction.TotalAmount.Amount, transaction.TotalAmount.Currency, transaction.CreatedAt,
+	).Scan(&transaction.ID)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	for _, d := range transaction.Discounts {
+		_, err := r.db.Exec(
+			"INSERT INTO transaction_discounts (transaction_id, promotion_id, voucher_id, nama, amount) VALUES ($1, $2, $3, $4, $5)",
+			transaction.ID, d.PromotionID, d.VoucherID, d.Nama, d.Amount.Amount,
+		)
+		if err != nil {
+			return entity.Transaction{}, err
+		}
+	}
+
+	for _, tax := range transaction.Taxes {
+		_, err := r.db.Exec(`
+			INSERT INTO transaction_taxes (transaction_id, tax_rate_id, nama, rate, inclusive, taxable_amount, amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			transaction.ID, tax.TaxRateID, tax.Nama, int64(tax.Rate), tax.Inclusive, tax.TaxableAmount.Amount, tax.Amount.Amount,
+		)
+		if err != nil {
+			return entity.Transaction{}, err
+		}
+	}
+
+	for i := range transaction.Details {
+		d := &transaction.Details[i]
+		d.TransactionID = transaction.ID
+		err := r.db.QueryRow(`
+			INSERT INTO transaction_details (transaction_id, product_id, variant_id, nama_produk, harga, harga_beli, quantity, unit, base_quantity, subtotal, discount, tax_included)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`,
+			d.TransactionID, d.ProductID, d.VariantID, d.NamaProduk, d.Harga.Amount, moneyAmount(d.HargaBeli),
+			int64(d.Quantity), detailUnit(*d), int64(detailBaseQuantity(*d)), d.Subtotal.Amount, d.Discount.Amount, d.TaxIncluded.Amount,
+		).Scan(&d.ID)
+		if err != nil {
+			return entity.Transaction{}, err
+		}
+		for _, m := range d.Modifiers {
+			_, err := r.db.Exec(`
+				INSERT INTO transaction_detail_modifiers (transaction_detail_id, modifier_id, group_nama, nama, harga)
+				VALUES ($1, $2, $3, $4, $5)`,
+				d.ID, m.ModifierID, m.GroupNama, m.Nama, m.Harga.Amount,
+			)
+			if err != nil {
+				return entity.Transaction{}, err
+			}
+		}
+		d.Unit, d.BaseQuantity = detailUnit(*d), detailBaseQuantity(*d)
+		setDetailCurrency(d, transaction.TotalAmount.Currency)
+	}
+
+	return transaction, nil
+}
+
+// GetByID - ambil transaksi beserta detailnya
+func (r *TransactionRepository) GetByID(id int) (entity.Transaction, error) {
+	var t entity.Transaction
+	err := r.db.QueryRow(
+		"SELECT id, subtotal, discount_amount, tax_amount, total_amount, currency, created_at FROM transactions WHERE id = $1", id,
+	).Scan(&t.ID, &t.Subtotal.Amount, &t.DiscountAmount.Amount, &t.TaxAmount.Amount, &t.TotalAmount.Amount, &t.TotalAmount.Currency, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return entity.Transaction{}, ErrTransactionNotFound
+	}
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, product_id, variant_id, nama_produk, harga, harga_beli, quantity, unit, base_quantity, subtotal, discount, tax_included
+		FROM transaction_details WHERE transaction_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return entity.Transaction{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := entity.TransactionDetail{TransactionID: t.ID}
+		var productID, variantID, hargaBeli sql.NullInt64
+		err := rows.Scan(&d.ID, &productID, &variantID, &d.NamaProduk, &d.Harga.Amount, &hargaBeli, &d.Quantity, &d.Unit, &d.BaseQuantity, &d.Subtotal.Amount, &d.Discount.Amount, &d.TaxIncluded.Amount)
+		if err != nil {
+			return entity.Transaction{}, err
+		}
+		d.ProductID = nullableInt(productID)
+		d.VariantID = nullableInt(variantID)
+		d.HargaBeli = nullableMoney(hargaBeli, t.TotalAmount.Currency)
+		t.Details = append(t.Details, d)
+	}
+	if err := rows.Err(); err != nil {
+		return entity.Transaction{}, err
+	}
+	rows.Close()
+
+	if err := r.loadDetailModifiers(&t); err != nil {
+		return entity.Transaction{}, err
+	}
+	if err := r.loadDiscounts(&t); err != nil {
+		return entity.Transaction{}, err
+	}
+	if err := r.loadTaxes(&t); err != nil {
+		return entity.Transaction{}, err
+	}
+	t.Subtotal.Currency = t.TotalAmount.Currency
+	t.DiscountAmount.Currency = t.TotalAmount.Currency
+	t.TaxAmount.Currency = t.TotalAmount.Currency
+	for i := range t.Details {
+		setDetailCurrency(&t.Details[i], t.TotalAmount.Currency)
+	}
+	return t, nil
+}
+
+// loadDiscounts - isi Discounts transaksi, urut seperti saat disimpan
+func (r *TransactionRepository) loadDiscounts(t *entity.Transaction) error {
+	rows, err := r.db.Query(
+		"SELECT promotion_id, voucher_id, nama, amount FROM transaction_discounts WHERE transaction_id = $1 ORDER BY id", t.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var promotionID, voucherID sql.NullInt64
+		d := entity.TransactionDiscount{Amount: entity.NewMoney(0, t.TotalAmount.Currency)}
+		if err := rows.Scan(&promotionID, &voucherID, &d.Nama, &d.Amount.Amount); err != nil {
+			return err
+		}
+		d.PromotionID = nullableInt(promotionID)
+		d.VoucherID = nullableInt(voucherID)
+		t.Discounts = append(t.Discounts, d)
+	}
+	return rows.Err()
+}
+
+// loadTaxes - isi Taxes transaksi, urut seperti saat disimpan
+func (r *TransactionRepository) loadTaxes(t *entity.Transaction) error {
+	rows, err := r.db.Query(
+		"SELECT tax_rate_id, nama, rate, inclusive, taxable_amount, amount FROM transaction_taxes WHERE transaction_id = $1 ORDER BY id", t.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taxRateID sql.NullInt64
+		tax := entity.TransactionTax{
+			TaxableAmount: entity.NewMoney(0, t.TotalAmount.Currency),
+			Amount:        entity.NewMoney(0, t.TotalAmount.Currency),
+		}
+		if err := rows.Scan(&taxRateID, &tax.Nama, &tax.Rate, &tax.Inclusive, &tax.TaxableAmount.Amount, &tax.Amount.Amount); err != nil {
+			return err
+		}
+		tax.TaxRateID = nullableInt(taxRateID)
+		t.Taxes = append(t.Taxes, tax)
+	}
+	return rows.Err()
+}
+
+// loadDetailModifiers - isi Modifiers setiap detail transaksi dengan satu query
+func (r *TransactionRepository) loadDetailModifiers(t *entity.Transaction) error {
+	rows, err := r.db.Query(`
+		SELECT m.transaction_detail_id, m.modifier_id, m.group_nama, m.nama, m.harga
+		FROM transaction_detail_modifiers m
+		JOIN transaction_details d ON d.id = m.transaction_detail_id
+		WHERE d.transaction_id = $1 ORDER BY m.id`, t.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	index := make(map[int]int, len(t.Details))
+	for i, d := range t.Details {
+		index[d.ID] = i
+	}
+	for rows.Next() {
+		var detailID int
+		var modifierID sql.NullInt64
+		var m entity.TransactionDetailModifier
+		if err := rows.Scan(&detailID, &modifierID, &m.GroupNama, &m.Nama, &m.Harga.Amount); err != nil {
+			return err
+		}
+		m.ModifierID = nullableInt(modifierID)
+		d := &t.Details[index[detailID]]
+		d.Modifiers = append(d.Modifiers, m)
+	}
+	return rows.Err()
+}
+
+// normalizeTotals - samakan mata uang total transaksi, diskon dan pajaknya. Transaksi tanpa
+// subtotal dan diskon memakai total_amount - tax_amount sebagai subtotal, seperti isi migrasi 015.
+func normalizeTotals(t *entity.Transaction) {
+	currency := t.TotalAmount.Cur()
+	if t.Subtotal.IsZero() && t.DiscountAmount.IsZero() {
+		t.Subtotal = entity.NewMoney(t.TotalAmount.Amount-t.TaxAmount.Amount, currency)
+	}
+	t.TotalAmount.Currency = currency
+	t.Subtotal.Currency = currency
+	t.DiscountAmount.Currency = currency
+	t.TaxAmount.Currency = currency
+	for i := range t.Discounts {
+		t.Discounts[i].Amount.Currency = currency
+	}
+	for i := range t.Taxes {
+		t.Taxes[i].TaxableAmount.Currency = currency
+		t.Taxes[i].Amount.Currency = currency
+	}
+}
+
+// setDetailCurrency - detail transaksi memakai mata uang transaksinya
+func setDetailCurrency(d *entity.TransactionDetail, currency string) {
+	d.Harga.Currency = currency
+	d.Subtotal.Currency = currency
+	d.Discount.Currency = currency
+	d.TaxIncluded.Currency = currency
+	for i := range d.Modifiers {
+		d.Modifiers[i].Harga.Currency = currency
+	}
+	if d.HargaBeli != nil {
+		cost := entity.NewMoney(d.HargaBeli.Amount, currency)
+		d.HargaBeli = &cost
+	}
+}
+
+// detailUnit - satuan jual baris yang disimpan, kosong berarti entity.DefaultUnit
+func detailUnit(d entity.TransactionDetail) string {
+	if d.Unit == "" {
+		return entity.DefaultUnit
+	}
+	return d.Unit
+}
+
+// detailBaseQuantity - jumlah dalam satuan dasar, kosong berarti sama dengan quantity
+func detailBaseQuantity(d entity.TransactionDetail) entity.Quantity {
+	if d.BaseQuantity == 0 {
+		return d.Quantity
+	}
+	return d.BaseQuantity
+}
+
+// marginQueries - agregasi penjualan per produk atau per kategori, %s = WHERE clause.
+// Pendapatan dihitung bersih setelah diskon dan tanpa pajak inclusive.
+// Harga pokok = harga beli per satuan jual × quantity (seperseribu unit), dibulatkan per baris.
+// Produk yang sudah dihapus (product_id NULL) dikelompokkan berdasarkan nama saat penjualan.
+var marginQueries = map[string]string{
+	entity.MarginByProduct: `
+		SELECT d.product_id, COALESCE(MAX(p.nama), MAX(d.nama_produk)), t.currency,
+			SUM(d.base_quantity), SUM(d.subtotal - d.discount - d.tax_included),
+			COALESCE(SUM((d.harga_beli * d.quantity + 500) / 1000), 0),
+			COALESCE(SUM(CASE WHEN d.harga_beli IS NULL THEN d.subtotal - d.discount - d.tax_included ELSE 0 END), 0)
+		FROM transaction_details d
+		JOIN transactions t ON t.id = d.transaction_id
+		LEFT JOIN products p ON p.id = d.product_id
+		%s
+		GROUP BY d.product_id, CASE WHEN d.product_id IS NULL THEN d.nama_produk END, t.currency`,
+	entity.MarginByCategory: `
+		SELECT c.id, COALESCE(MAX(c.name), ''), t.currency,
+			SUM(d.base_quantity), SUM(d.subtotal - d.discount - d.tax_included),
+			COALESCE(SUM((d.harga_beli * d.quantity + 500) / 1000), 0),
+			COALESCE(SUM(CASE WHEN d.harga_beli IS NULL THEN d.subtotal - d.discount - d.tax_included ELSE 0 END), 0)
+		FROM transaction_details d
+		JOIN transactions t ON t.id = d.transaction_id
+		LEFT JOIN products p ON p.id = d.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		%s
+		GROUP BY c.id, t.currency`,
+}
+
+// MarginReport - pendapatan, harga pokok dan pendapatan tanpa harga beli per kelompok.
+// GrossMargin dan MarginPercent dihitung oleh service.
+func (r *TransactionRepository) MarginReport(filter entity.MarginFilter) ([]entity.MarginRow, error) {
+	query, ok := marginQueries[filter.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown margin grouping %q", filter.GroupBy)
+	}
+
+	var conditions []string
+	var args []interface{}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("t.created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("t.created_at < $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(query, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []entity.MarginRow
+	for rows.Next() {
+		var row entity.MarginRow
+		var id sql.NullInt64
+		var currency string
+		err := rows.Scan(&id, &row.Name, &currency, &row.Quantity,
+			&row.Revenue.Amount, &row.Cost.Amount, &row.UncostedRevenue.Amount)
+		if err != nil {
+			return nil, err
+		}
+		row.ID = nullableInt(id)
+		row.Revenue.Currency = currency
+		row.Cost.Currency = currency
+		row.UncostedRevenue.Currency = currency
+		report = append(report, row)
+	}
+
+	return report, rows.Err()
+}
+
+// TaxReport - dasar pengenaan dan jumlah pajak per tarif (nama, persen, inclusive) dan mata uang.
+// Tarif yang sudah dihapus tetap muncul karena dikelompokkan berdasarkan salinan di transaksi.
+func (r *TransactionRepository) TaxReport(filter entity.TaxFilter) ([]entity.TaxRow, error) {
+	var conditions []string
+	var args []interface{}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From.UTC())
+		conditions = append(conditions, fmt.Sprintf("t.created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To.UTC())
+		conditions = append(conditions, fmt.Sprintf("t.created_at < $%d", len(args)))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT x.nama, x.rate, x.inclusive, t.currency, COUNT(DISTINCT x.transaction_id),
+			SUM(x.taxable_amount), SUM(x.amount)
+		FROM transaction_taxes x
+		JOIN transactions t ON t.id = x.transaction_id
+		%s
+		GROUP BY x.nama, x.rate, x.inclusive, t.currency
+		ORDER BY x.nama, x.rate, x.inclusive, t.currency`, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []entity.TaxRow
+	for rows.Next() {
+		var row entity.TaxRow
+		var currency string
+		err := rows.Scan(&row.Nama, &row.Rate, &row.Inclusive, &currency, &row.Transactions,
+			&row.TaxableAmount.Amount, &row.Amount.Amount)
+		if err != nil {
+			return nil, err
+		}
+		row.TaxableAmount.Currency = currency
+		row.Amount.Currency = currency
+		report = append(report, row)
+	}
+	return report, rows.Err()
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is satisfied by both *sql.DB and *sql.Tx, so a repository can run
+// on a plain connection or inside a transaction
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Repositories groups repository instances that share one connection or transaction
+type Repositories struct {
+	Category    CategoryRepositoryInterface
+	Product     ProductRepositoryInterface
+	Transaction TransactionRepositoryInterface
+	Price       PriceRepositoryInterface
+	Variant     VariantRepositoryInterface
+	Modifier    ModifierRepositoryInterface
+	Bundle      BundleRepositoryInterface
+	Unit        UnitRepositoryInterface
+	Promotion   PromotionRepositoryInterface
+	Voucher     VoucherRepositoryInterface
+	TaxRate     TaxRateRepositoryInterface
+}
+
+// NewRepositories - constructor untuk semua repository SQL di atas db atau tx
+func NewRepositories(db DBTX) Repositories {
+	return Repositories{
+		Category:    NewCategoryRepository(db),
+		Product:     NewProductRepository(db),
+		Transaction: NewTransactionRepository(db),
+		Price:       NewPriceRepository(db),
+		Variant:     NewVariantRepository(db),
+		Modifier:    NewModifierRepository(db),
+		Bundle:      NewBundleRepository(db),
+		Unit:        NewUnitRepository(db),
+		Promotion:   NewPromotionRepository(db),
+		Voucher:     NewVoucherRepository(db),
+		TaxRate:     NewTaxRateRepository(db),
+	}
+}
+
+// TxManagerInterface - interface untuk unit of work lintas repository
+type TxManagerInterface interface {
+	// WithinTx runs fn with repositories bound to one transaction.
+	// The transaction commits when fn returns nil and rolls back when
+	// fn returns an error or panics.
+	WithinTx(ctx context.Context, fn func(repos Repositories) error) error
+	// WithinReadTx runs fn with repositories bound to one read-only
+	// transaction. fn must not write; it never blocks other readers.
+	WithinReadTx(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+// TxManager - unit of work berbasis *sql.Tx
+type TxManager struct {
+	db *sql.DB
+}
+
+// NewTxManager - constructor untuk TxManager
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx - jalankan fn di dalam satu transaksi database
+func (m *TxManager) WithinTx(ctx context.Context, fn func(repos Repositories) error) (err error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(NewRepositories(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// WithinReadTx - jalankan fn di dalam satu transaksi database read-only
+func (m *TxManager) WithinReadTx(ctx context.Context, fn func(repos Repositories) error) error {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Tidak ada yang ditulis, rollback cukup untuk menutup transaksi
+	defer tx.Rollback()
+
+	return fn(NewRepositories(tx))
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/entity"
+	"strings"
+)
+
+// UnitRepositoryInterface - interface untuk satuan dan konversi satuan produk
+type UnitRepositoryInterface interface {
+	GetAll() ([]entity.Unit, error)
+	GetByCode(code string) (entity.Unit, error)
+	Create(unit entity.Unit) (entity.Unit, error)
+	Update(code string, unit entity.Unit) (entity.Unit, error)
+	Delete(code string) error
+	GetConversions(productID int) ([]entity.UnitConversion, error)
+	GetConversionsByProducts(productIDs []int) (map[int][]entity.UnitConversion, error)
+	SetConversions(productID int, conversions []entity.UnitConversion) error
+}
+
+// UnitRepository - struct untuk unit repository
+type UnitRepository struct {
+	db DBTX
+}
+
+// NewUnitRepository - constructor untuk UnitRepository
+func NewUnitRepository(db DBTX) *UnitRepository {
+	return &UnitRepository{db: db}
+}
+
+// GetAll - semua satuan, urut berdasarkan kode
+func (r *UnitRepository) GetAll() ([]entity.Unit, error) {
+	rows, err := r.db.Query("SELECT code, nama, allow_decimal FROM units ORDER BY code")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var units []entity.Unit
+	for rows.Next() {
+		var u entity.Unit
+		if err := rows.Scan(&u.Code, &u.Nama, &u.AllowDecimal); err != nil {
+			return nil, err
+		}
+		units = append(units, u)
+	}
+	return units, rows.Err()
+}
+
+// GetByCode - ambil satuan berdasarkan kode
+func (r *UnitRepository) GetByCode(code string) (entity.Unit, error) {
+	var u entity.Unit
+	err := r.db.QueryRow("SELECT code, nama, allow_decimal FROM units WHERE code = $1", code).
+		Scan(&u.Code, &u.Nama, &u.AllowDecimal)
+	if err == sql.ErrNoRows {
+		return entity.Unit{}, ErrUnitNotFound
+	}
+	return u, err
+}
+
+// Create - tambah satuan, kode unik
+func (r *UnitRepository) Create(unit entity.Unit) (entity.Unit, error) {
+	if existing, err := r.GetByCode(unit.Code); err == nil {
+		return entity.Unit{}, &ConflictError{Resource: "unit", Name: unit.Code, Scope: "store", Existing: existing}
+	} else if err != ErrUnitNotFound {
+		return entity.Unit{}, err
+	}
+
+	_, err := r.db.Exec("INSERT INTO units (code, nama, allow_decimal) VALUES ($1, $2, $3)", unit.Code, unit.Nama, unit.AllowDecimal)
+	if isUniqueViolation(err) {
+		return entity.Unit{}, &ConflictError{Resource: "unit", Name: unit.Code, Scope: "store"}
+	}
+	if err != nil {
+		return entity.Unit{}, err
+	}
+	return unit, nil
+}
+
+// Update - ubah nama dan izin desimal satuan, kode tidak bisa diubah
+func (r *UnitRepository) Update(code string, unit entity.Unit) (entity.Unit, error) {
+	result, err := r.db.Exec("UPDATE units SET nama = $1, allow_decimal = $2 WHERE code = $3", unit.Nama, unit.AllowDecimal, code)
+	if err != nil {
+		return entity.Unit{}, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return entity.Unit{}, err
+	}
+	if rowsAffected == 0 {
+		return entity.Unit{}, ErrUnitNotFound
+	}
+
+	unit.Code = code
+	return unit, nil
+}
+
+// Delete - hapus satuan yang tidak dipakai produk mana pun (ErrUnitInUse).
+// Baris penjualan menyimpan kode satuannya sendiri sehingga riwayat tetap utuh.
+func (r *UnitRepository) Delete(code string) error {
+	var one int
+	err := r.db.QueryRow(`
+		SELECT 1 FROM products WHERE unit = $1 OR purchase_unit = $1
+		UNION ALL
+		SELECT 1 FROM product_units WHERE unit = $1
+		LIMIT 1`, code,
+	).Scan(&one)
+	if err == nil {
+		return ErrUnitInUse
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	result, err := r.db.Exec("DELETE FROM units WHERE code = $1", code)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUnitNotFound
+	}
+	return nil
+}
+
+// GetConversions - satuan lain dari satu produk, urut berdasarkan faktor
+func (r *UnitRepository) GetConversions(productID int) ([]entity.UnitConversion, error) {
+	grouped, err := r.GetConversionsByProducts([]int{productID})
+	if err != nil {
+		return nil, err
+	}
+	return grouped[productID], nil
+}
+
+// GetConversionsByProducts - satuan lain dari banyak produk sekaligus (satu query), dikelompokkan per product ID
+func (r *UnitRepository) GetConversionsByProducts(productIDs []int) (map[int][]entity.UnitConversion, error) {
+	grouped := make(map[int][]entity.UnitConversion)
+	if len(productIDs) == 0 {
+		return grouped, nil
+	}
+
+	placeholders := make([]string, len(productIDs))
+	args := make([]interface{}, len(productIDs))
+	for i, id := range productIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	rows, err := r.db.Query(
+		"SELECT product_id, unit, factor FROM product_units WHERE product_id IN ("+strings.Join(placeholders, ", ")+") ORDER BY product_id, factor, unit",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var c entity.UnitConversion
+		if err := rows.Scan(&productID, &c.Unit, &c.Factor); err != nil {
+			return nil, err
+		}
+		grouped[productID] = append(grouped[productID], c)
+	}
+	return grouped, rows.Err()
+}
+
+// SetConversions - ganti semua satuan lain dari produk
+func (r *UnitRepository) SetConversions(productID int, conversions []entity.UnitConversion) error {
+	if _, err := r.db.Exec("DELETE FROM product_units WHERE product_id = $1", productID); err != nil {
+		return err
+	}
+	for _, c := range conversions {
+		_, err := r.db.Exec(
+			"INSERT INTO product_units (product_id, unit, factor) VALUES ($1, $2, $3)",
+			productID, c.Unit, int64(c.Factor),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"kasir-api/entity"
+	"strings"
+)
+
+// VariantRepositoryInterface - interface untuk variant repository
+type VariantRepositoryInterface interface {
+	GetByProduct(productID int) ([]entity.ProductVariant, error)
+	GetByProducts(productIDs []int) (map[int][]entity.ProductVariant, error)
+	GetByID(id int) (entity.ProductVariant, error)
+	Create(variant entity.ProductVariant) (entity.ProductVariant, error)
+	Update(id int, variant entity.ProductVariant) (entity.ProductVariant, error)
+	Delete(id int) error
+	AdjustStock(id int, delta entity.Quantity) (entity.ProductVariant, error)
+}
+
+// VariantRepository - struct untuk variant repository
+type VariantRepository struct {
+	db DBTX
+}
+
+// NewVariantRepository - constructor untuk VariantRepository
+func NewVariantRepository(db DBTX) *VariantRepository {
+	return &VariantRepository{db: db}
+}
+
+const variantColumns = "id, product_id, sku, nama, options, harga, currency, harga_beli, stock"
+
+// scanVariant - scan satu baris variantColumns, options disimpan sebagai JSON
+func scanVariant(row interface{ Scan(...interface{}) error }) (entity.ProductVariant, error) {
+	var v entity.ProductVariant
+	var options []byte
+	var hargaBeli sql.NullInt64
+	var stock entity.Quantity
+	err := row.Scan(&v.ID, &v.ProductID, &v.SKU, &v.Nama, &options, &v.Harga.Amount, &v.Harga.Currency, &hargaBeli, &stock)
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+	v.Stock = &stock
+	v.HargaBeli = nullableMoney(hargaBeli, v.Harga.Currency)
+	if err := json.Unmarshal(options, &v.Options); err != nil {
+		return entity.ProductVariant{}, fmt.Errorf("invalid options of variant %d: %w", v.ID, err)
+	}
+	if v.Options == nil {
+		v.Options = map[string]string{}
+	}
+	return v, nil
+}
+
+// queryVariants - jalankan query yang mengembalikan variantColumns
+func (r *VariantRepository) queryVariants(query string, args ...interface{}) ([]entity.ProductVariant, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []entity.ProductVariant
+	for rows.Next() {
+		v, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	return variants, rows.Err()
+}
+
+// GetByProduct - semua varian produk, urut berdasarkan ID
+func (r *VariantRepository) GetByProduct(productID int) ([]entity.ProductVariant, error) {
+	return r.queryVariants("SELECT "+variantColumns+" FROM product_variants WHERE product_id = $1 ORDER BY id", productID)
+}
+
+// GetByProducts - varian dari banyak produk sekaligus (satu query), dikelompokkan per product ID
+func (r *VariantRepository) GetByProducts(productIDs []int) (map[int][]entity.ProductVariant, error) {
+	grouped := make(map[int][]entity.ProductVariant)
+	if len(productIDs) == 0 {
+		return grouped, nil
+	}
+
+	placeholders := make([]string, len(productIDs))
+	args := make([]interface{}, len(productIDs))
+	for i, id := range productIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	variants, err := r.queryVariants(
+		"SELECT "+variantColumns+" FROM product_variants WHERE product_id IN ("+strings.Join(placeholders, ", ")+") ORDER BY id",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range variants {
+		grouped[v.ProductID] = append(grouped[v.ProductID], v)
+	}
+	return grouped, nil
+}
+
+// GetByID - ambil varian berdasarkan ID
+func (r *VariantRepository) GetByID(id int) (entity.ProductVariant, error) {
+	v, err := scanVariant(r.db.QueryRow("SELECT "+variantColumns+" FROM product_variants WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return entity.ProductVariant{}, ErrVariantNotFound
+	}
+	return v, err
+}
+
+// Create - tambah varian, SKU unik di seluruh toko dan nama unik per produk
+func (r *VariantRepository) Create(variant entity.ProductVariant) (entity.ProductVariant, error) {
+	if err := r.checkConflict(0, variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+	options, err := marshalOptions(variant.Options)
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	err = r.db.QueryRow(`
+		INSERT INTO product_variants (product_id, sku, nama, options, harga, currency, harga_beli, stock)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		variant.ProductID, variant.SKU, variant.Nama, options, variant.Harga.Amount, variant.Harga.Cur(), moneyAmount(variant.HargaBeli), variantStock(variant),
+	).Scan(&variant.ID)
+	if isUniqueViolation(err) {
+		return entity.ProductVariant{}, variantConflict(variant, nil)
+	}
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	normalizeVariant(&variant)
+	return variant, nil
+}
+
+// Update - update varian, product_id tidak bisa dipindah
+func (r *VariantRepository) Update(id int, variant entity.ProductVariant) (entity.ProductVariant, error) {
+	current, err := r.GetByID(id)
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+	variant.ID = id
+	variant.ProductID = current.ProductID
+	if err := r.checkConflict(id, variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+	options, err := marshalOptions(variant.Options)
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE product_variants SET sku = $1, nama = $2, options = $3, harga = $4, currency = $5, harga_beli = $6,
+			stock = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $8`,
+		variant.SKU, variant.Nama, options, variant.Harga.Amount, variant.Harga.Cur(), moneyAmount(variant.HargaBeli), variantStock(variant), id,
+	)
+	if isUniqueViolation(err) {
+		return entity.ProductVariant{}, variantConflict(variant, nil)
+	}
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	normalizeVariant(&variant)
+	return variant, nil
+}
+
+// Delete - hapus varian, detail transaksi lama tetap ada dengan variant_id NULL
+func (r *VariantRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM product_variants WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrVariantNotFound
+	}
+	return nil
+}
+
+// AdjustStock - tambah (delta positif) atau kurangi stok (dalam satuan dasar) secara atomik.
+// Stok tidak boleh minus: ErrInsufficientStock dan stok tidak berubah.
+func (r *VariantRepository) AdjustStock(id int, delta entity.Quantity) (entity.ProductVariant, error) {
+	v, err := scanVariant(r.db.QueryRow(`
+		UPDATE product_variants SET stock = stock + $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND stock + $1 >= 0
+		RETURNING `+variantColumns, delta, id))
+	if err != sql.ErrNoRows {
+		return v, err
+	}
+	if _, err := r.GetByID(id); err != nil {
+		return entity.ProductVariant{}, err
+	}
+	return entity.ProductVariant{}, ErrInsufficientStock
+}
+
+// checkConflict - cari varian lain dengan SKU yang sama, atau nama yang sama dalam produk yang sama
+func (r *VariantRepository) checkConflict(id int, variant entity.ProductVariant) error {
+	existing, err := scanVariant(r.db.QueryRow(`
+		SELECT `+variantColumns+` FROM product_variants
+		WHERE id <> $1 AND (LOWER(sku) = LOWER($2) OR (product_id = $3 AND LOWER(nama) = LOWER($4)))
+		ORDER BY id LIMIT 1`,
+		id, variant.SKU, variant.ProductID, variant.Nama,
+	))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return variantConflict(variant, &existing)
+}
+
+// variantConflict - ConflictError untuk SKU atau nama varian, existing nil jika tidak diketahui
+func variantConflict(variant entity.ProductVariant, existing *entity.ProductVariant) *ConflictError {
+	err := &ConflictError{Resource: "variant SKU", Name: variant.SKU, Scope: "store"}
+	if existing == nil {
+		return err
+	}
+	if !strings.EqualFold(existing.SKU, variant.SKU) {
+		err = &ConflictError{Resource: "variant", Name: variant.Nama, Scope: "product"}
+	}
+	err.Existing = *existing
+	return err
+}
+
+// marshalOptions - options sebagai JSON, nil menjadi {}
+func marshalOptions(options map[string]string) (string, error) {
+	if options == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(options)
+	return string(data), err
+}
+
+// normalizeVariant - isi nilai default seperti database
+func normalizeVariant(v *entity.ProductVariant) {
+	v.Harga.Currency = v.Harga.Cur()
+	if v.HargaBeli != nil {
+		cost := entity.NewMoney(v.HargaBeli.Amount, v.Harga.Currency)
+		v.HargaBeli = &cost
+	}
+	if v.Options == nil {
+		v.Options = map[string]string{}
+	}
+	stock := entity.Quantity(variantStock(*v))
+	v.Stock = &stock
+	v.ClearHargaBeli = false
+}
+
+// variantStock - stok varian dalam seperseribu unit, nil menjadi 0 seperti default kolom
+func variantStock(variant entity.ProductVariant) int64 {
+	if variant.Stock == nil {
+		return 0
+	}
+	return int64(*variant.Stock)
+}
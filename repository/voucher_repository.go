@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/entity"
+	"time"
+)
+
+// VoucherRepositoryInterface - interface untuk voucher repository
+type VoucherRepositoryInterface interface {
+	GetAll() ([]entity.Voucher, error)
+	GetByID(id int) (entity.Voucher, error)
+	GetByCode(code string) (entity.Voucher, error)
+	Create(voucher entity.Voucher) (entity.Voucher, error)
+	Update(id int, voucher entity.Voucher) (entity.Voucher, error)
+	Delete(id int) error
+	CountRedemptions(voucherID int, customer string) (int, error)
+	Redeem(redemption entity.VoucherRedemption) (entity.VoucherRedemption, error)
+}
+
+// VoucherRepository - struct untuk voucher repository
+type VoucherRepository struct {
+	db DBTX
+}
+
+// NewVoucherRepository - constructor untuk VoucherRepository
+func NewVoucherRepository(db DBTX) *VoucherRepository {
+	return &VoucherRepository{db: db}
+}
+
+const voucherColumns = `id, code, nama, type, percent, amount, max_discount, min_subtotal, currency,
+	usage_limit, per_customer_limit, used_count, starts_at, expires_at`
+
+// scanVoucher - scan satu baris voucherColumns
+func scanVoucher(row interface{ Scan(...interface{}) error }) (entity.Voucher, error) {
+	var v entity.Voucher
+	var percent, amount, maxDiscount, minSubtotal, usageLimit, perCustomer sql.NullInt64
+	var startsAt, expiresAt sql.NullTime
+	var currency string
+	err := row.Scan(&v.ID, &v.Code, &v.Nama, &v.Type, &percent, &amount, &maxDiscount, &minSubtotal, &currency,
+		&usageLimit, &perCustomer, &v.UsedCount, &startsAt, &expiresAt)
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	v.Percent = entity.Percent(percent.Int64)
+	v.Amount = nullableMoney(amount, currency)
+	v.MaxDiscount = nullableMoney(maxDiscount, currency)
+	v.MinSubtotal = nullableMoney(minSubtotal, currency)
+	v.UsageLimit = nullableInt(usageLimit)
+	v.PerCustomerLimit = nullableInt(perCustomer)
+	if startsAt.Valid {
+		v.StartsAt = utcTime(&startsAt.Time)
+	}
+	if expiresAt.Valid {
+		v.ExpiresAt = utcTime(&expiresAt.Time)
+	}
+	return v, nil
+}
+
+// voucherValues - nilai kolom yang bisa diubah, urut seperti voucherColumns mulai dari code
+func voucherValues(v entity.Voucher) []interface{} {
+	return []interface{}{
+		v.Code, v.Nama, v.Type, nullablePositive(int64(v.Percent)), moneyAmount(v.Amount), moneyAmount(v.MaxDiscount),
+		moneyAmount(v.MinSubtotal), voucherCurrency(v), v.UsageLimit, v.PerCustomerLimit,
+		nullableTime(utcTime(v.StartsAt)), nullableTime(utcTime(v.ExpiresAt)),
+	}
+}
+
+// voucherCurrency - mata uang semua nilai nominal voucher, default IDR
+func voucherCurrency(v entity.Voucher) string {
+	for _, m := range []*entity.Money{v.Amount, v.MaxDiscount, v.MinSubtotal} {
+		if m != nil {
+			return m.Cur()
+		}
+	}
+	return entity.DefaultCurrency
+}
+
+// GetAll - semua voucher, urut berdasarkan ID
+func (r *VoucherRepository) GetAll() ([]entity.Voucher, error) {
+	rows, err := r.db.Query("SELECT " + voucherColumns + " FROM vouchers ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vouchers []entity.Voucher
+	for rows.Next() {
+		v, err := scanVoucher(rows)
+		if err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, v)
+	}
+	return vouchers, rows.Err()
+}
+
+// GetByID - ambil voucher berdasarkan ID
+func (r *VoucherRepository) GetByID(id int) (entity.Voucher, error) {
+	v, err := scanVoucher(r.db.QueryRow("SELECT "+voucherColumns+" FROM vouchers WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return entity.Voucher{}, ErrVoucherNotFound
+	}
+	return v, err
+}
+
+// GetByCode - ambil voucher berdasarkan kode (huruf besar)
+func (r *VoucherRepository) GetByCode(code string) (entity.Voucher, error) {
+	v, err := scanVoucher(r.db.QueryRow("SELECT "+voucherColumns+" FROM vouchers WHERE code = $1", code))
+	if err == sql.ErrNoRows {
+		return entity.Voucher{}, ErrVoucherNotFound
+	}
+	return v, err
+}
+
+// Create - tambah voucher, kode unik
+func (r *VoucherRepository) Create(voucher entity.Voucher) (entity.Voucher, error) {
+	if existing, err := r.GetByCode(voucher.Code); err == nil {
+		return entity.Voucher{}, &ConflictError{Resource: "voucher", Name: voucher.Code, Scope: "store", Existing: existing}
+	} else if err != ErrVoucherNotFound {
+		return entity.Voucher{}, err
+	}
+
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO vouchers (code, nama, type, percent, amount, max_discount, min_subtotal, currency,
+			usage_limit, per_customer_limit, starts_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`,
+		voucherValues(voucher)...,
+	).Scan(&id)
+	if isUniqueViolation(err) {
+		return entity.Voucher{}, &ConflictError{Resource: "voucher", Name: voucher.Code, Scope: "store"}
+	}
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	return r.GetByID(id)
+}
+
+// Update - ubah voucher, used_count tidak berubah
+func (r *VoucherRepository) Update(id int, voucher entity.Voucher) (entity.Voucher, error) {
+	if existing, err := r.GetByCode(voucher.Code); err == nil && existing.ID != id {
+		return entity.Voucher{}, &ConflictError{Resource: "voucher", Name: voucher.Code, Scope: "store", Existing: existing}
+	} else if err != nil && err != ErrVoucherNotFound {
+		return entity.Voucher{}, err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE vouchers SET code = $1, nama = $2, type = $3, percent = $4, amount = $5, max_discount = $6,
+			min_subtotal = $7, currency = $8, usage_limit = $9, per_customer_limit = $10,
+			starts_at = $11, expires_at = $12, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $13`,
+		append(voucherValues(voucher), id)...,
+	)
+	if isUniqueViolation(err) {
+		return entity.Voucher{}, &ConflictError{Resource: "voucher", Name: voucher.Code, Scope: "store"}
+	}
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return entity.Voucher{}, err
+	}
+	if rowsAffected == 0 {
+		return entity.Voucher{}, ErrVoucherNotFound
+	}
+	return r.GetByID(id)
+}
+
+// Delete - hapus voucher beserta riwayat pemakaiannya, diskon di transaksi lama tetap ada
+func (r *VoucherRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM vouchers WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrVoucherNotFound
+	}
+	return nil
+}
+
+// CountRedemptions - jumlah pemakaian voucher oleh customer
+func (r *VoucherRepository) CountRedemptions(voucherID int, customer string) (int, error) {
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM voucher_redemptions WHERE voucher_id = $1 AND customer = $2", voucherID, customer,
+	).Scan(&count)
+	return count, err
+}
+
+// Redeem - catat pemakaian voucher. used_count dinaikkan dengan UPDATE bersyarat lebih dulu
+// sehingga baris voucher terkunci sampai transaksi selesai: checkout bersamaan untuk voucher
+// yang sama menunggu, lalu melihat used_count dan riwayat pemakaian yang sudah di-commit.
+// ErrVoucherUsageLimit jika kuota habis, ErrVoucherCustomerLimit jika kuota customer habis.
+// Jalankan di dalam TxManager bersama penyimpanan transaksinya.
+func (r *VoucherRepository) Redeem(redemption entity.VoucherRedemption) (entity.VoucherRedemption, error) {
+	var perCustomer sql.NullInt64
+	var currency string
+	err := r.db.QueryRow(`
+		UPDATE vouchers SET used_count = used_count + 1
+		WHERE id = $1 AND (usage_limit IS NULL OR used_count < usage_limit)
+		RETURNING per_customer_limit, currency`, redemption.VoucherID,
+	).Scan(&perCustomer, &currency)
+	if err == sql.ErrNoRows {
+		if _, err := r.GetByID(redemption.VoucherID); err != nil {
+			return entity.VoucherRedemption{}, err
+		}
+		return entity.VoucherRedemption{}, ErrVoucherUsageLimit
+	}
+	if err != nil {
+		return entity.VoucherRedemption{}, err
+	}
+
+	if perCustomer.Valid {
+		if redemption.Customer == "" {
+			return entity.VoucherRedemption{}, ErrVoucherCustomerLimit
+		}
+		count, err := r.CountRedemptions(redemption.VoucherID, redemption.Customer)
+		if err != nil {
+			return entity.VoucherRedemption{}, err
+		}
+		if int64(count) >= perCustomer.Int64 {
+			return entity.VoucherRedemption{}, ErrVoucherCustomerLimit
+		}
+	}
+
+	if redemption.CreatedAt.IsZero() {
+		redemption.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	}
+	redemption.Amount = entity.NewMoney(redemption.Amount.Amount, currency)
+	err = r.db.QueryRow(`
+		INSERT INTO voucher_redemptions (voucher_id, transaction_id, customer, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		redemption.VoucherID, redemption.TransactionID, nullableString(redemption.Customer),
+		redemption.Amount.Amount, redemption.CreatedAt,
+	).Scan(&redemption.ID)
+	if err != nil {
+		return entity.VoucherRedemption{}, err
+	}
+	return redemption, nil
+}
@@ -1,50 +1,347 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"kasir-api/entity"
 	"kasir-api/repository"
 )
 
+// DeleteMode - kebijakan terhadap produk saat kategori dihapus
+type DeleteMode string
+
+const (
+	// DeleteDetach - produk tetap ada tanpa kategori (default, perilaku lama ON DELETE SET NULL)
+	DeleteDetach DeleteMode = "detach"
+	// DeleteRestrict - tolak jika kategori masih punya produk
+	DeleteRestrict DeleteMode = "restrict"
+	// DeleteReassign - pindahkan produk ke kategori lain dalam satu transaksi
+	DeleteReassign DeleteMode = "reassign"
+	// DeleteCascade - hapus sub-kategori dan soft delete semua produk di seluruh sub-pohon,
+	// ditolak jika ada yang masih menjadi komponen bundle
+	DeleteCascade DeleteMode = "cascade"
+)
+
+// DeleteCategoryOptions - opsi penghapusan kategori
+type DeleteCategoryOptions struct {
+	Mode DeleteMode
+	To   int // kategori tujuan untuk DeleteReassign
+}
+
+// DeleteCategoryResult - ringkasan penghapusan kategori
+type DeleteCategoryResult struct {
+	Mode                 DeleteMode `json:"mode"`
+	ProductsAffected     int        `json:"products_affected"`
+	SubcategoriesMoved   int        `json:"subcategories_moved"`   // sub-kategori naik ke parent kategori yang dihapus
+	SubcategoriesDeleted int        `json:"subcategories_deleted"` // hanya mode cascade
+}
+
+// Errors for category deletion
+var (
+	ErrInvalidDeleteMode      = errors.New("invalid delete mode, use detach, restrict, reassign or cascade")
+	ErrReassignTargetRequired = errors.New("reassign mode requires a target category (?to=<id>)")
+	ErrReassignToSelf         = errors.New("cannot reassign products to the category being deleted")
+	ErrReassignTargetNotFound = errors.New("target category not found")
+)
+
+// Errors for category hierarchy
+var (
+	ErrParentNotFound = errors.New("parent category not found")
+	ErrCategoryCycle  = errors.New("parent category cannot be the category itself or one of its sub-categories")
+)
+
+// CategoryInUseError - kategori masih dipakai produk (mode restrict)
+type CategoryInUseError struct {
+	CategoryID int
+	Products   []entity.Product
+}
+
+func (e *CategoryInUseError) Error() string {
+	return fmt.Sprintf("category %d still has %d product(s), use mode=reassign or mode=cascade", e.CategoryID, len(e.Products))
+}
+
 // CategoryServiceInterface - interface untuk category service
 type CategoryServiceInterface interface {
 	GetAllCategories() ([]entity.Category, error)
 	GetCategoryByID(id int) (entity.Category, error)
+	GetCategoryTree() ([]entity.Category, error)
 	CreateCategory(category entity.Category) (entity.Category, error)
 	UpdateCategory(id int, category entity.Category) (entity.Category, error)
-	DeleteCategory(id int) error
+	DeleteCategory(id int, opts DeleteCategoryOptions) (DeleteCategoryResult, error)
 }
 
 // CategoryService - struct untuk category service
 type CategoryService struct {
-	repo repository.CategoryRepositoryInterface
+	repo      repository.CategoryRepositoryInterface
+	txManager repository.TxManagerInterface
 }
 
 // NewCategoryService - constructor untuk CategoryService
-func NewCategoryService(repo repository.CategoryRepositoryInterface) *CategoryService {
-	return &CategoryService{repo: repo}
+func NewCategoryService(repo repository.CategoryRepositoryInterface, txManager repository.TxManagerInterface) *CategoryService {
+	return &CategoryService{repo: repo, txManager: txManager}
 }
 
-// GetAllCategories - ambil semua kategori
+// GetAllCategories - ambil semua kategori beserta breadcrumb path
 func (s *CategoryService) GetAllCategories() ([]entity.Category, error) {
-	return s.repo.GetAll()
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]entity.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	for i := range categories {
+		categories[i].Path = breadcrumb(categories[i], byID)
+	}
+	return categories, nil
 }
 
-// GetCategoryByID - ambil kategori berdasarkan ID
+// GetCategoryByID - ambil kategori berdasarkan ID beserta breadcrumb path
 func (s *CategoryService) GetCategoryByID(id int) (entity.Category, error) {
-	return s.repo.GetByID(id)
+	category, err := s.repo.GetByID(id)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	category.Path, err = s.repo.GetPath(id)
+	if err != nil {
+		return entity.Category{}, err
+	}
+	return category, nil
+}
+
+// GetCategoryTree - semua kategori sebagai pohon bersarang, root diurutkan berdasarkan ID
+func (s *CategoryService) GetCategoryTree() ([]entity.Category, error) {
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[int][]entity.Category)
+	var roots []entity.Category
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var attach func(nodes []entity.Category) []entity.Category
+	attach = func(nodes []entity.Category) []entity.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	tree := attach(roots)
+	if tree == nil {
+		tree = []entity.Category{}
+	}
+	return tree, nil
 }
 
-// CreateCategory - tambah kategori baru
+// CreateCategory - tambah kategori baru, parent dicek dalam transaksi yang sama
 func (s *CategoryService) CreateCategory(category entity.Category) (entity.Category, error) {
-	return s.repo.Create(category)
+	var created entity.Category
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if err := checkParent(repos, 0, category.ParentID); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = repos.Category.Create(category)
+		return err
+	})
+	return created, err
 }
 
-// UpdateCategory - update kategori
+// UpdateCategory - update kategori, parent baru tidak boleh membentuk siklus
 func (s *CategoryService) UpdateCategory(id int, category entity.Category) (entity.Category, error) {
-	return s.repo.Update(id, category)
+	var updated entity.Category
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := repos.Category.GetByID(id); err != nil {
+			return err
+		}
+		if err := checkParent(repos, id, category.ParentID); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = repos.Category.Update(id, category)
+		return err
+	})
+	return updated, err
+}
+
+// checkParent - parent harus ada dan bukan kategori id atau turunannya (id 0 = kategori baru)
+func checkParent(repos repository.Repositories, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return ErrCategoryCycle
+	}
+
+	// Breadcrumb parent memuat id berarti parent adalah turunan kategori ini
+	path, err := repos.Category.GetPath(*parentID)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return ErrParentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	for _, crumb := range path {
+		if crumb.ID == id {
+			return ErrCategoryCycle
+		}
+	}
+	return nil
+}
+
+// breadcrumb - path dari root sampai c, dihitung dari daftar kategori yang sudah dimuat
+func breadcrumb(c entity.Category, byID map[int]entity.Category) []entity.CategoryCrumb {
+	path := []entity.CategoryCrumb{{ID: c.ID, Name: c.Name}}
+	for c.ParentID != nil && len(path) <= len(byID) {
+		parent, ok := byID[*c.ParentID]
+		if !ok {
+			break
+		}
+		c = parent
+		path = append([]entity.CategoryCrumb{{ID: c.ID, Name: c.Name}}, path...)
+	}
+	return path
+}
+
+// DeleteCategory - hapus kategori sesuai kebijakan produk (detach, restrict, reassign, cascade).
+// Tanpa mode produk dilepas dari kategori seperti sebelum ada kebijakan.
+func (s *CategoryService) DeleteCategory(id int, opts DeleteCategoryOptions) (DeleteCategoryResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = DeleteDetach
+	}
+	result := DeleteCategoryResult{Mode: opts.Mode}
+
+	switch opts.Mode {
+	case DeleteDetach, DeleteRestrict, DeleteCascade:
+	case DeleteReassign:
+		if opts.To == 0 {
+			return result, ErrReassignTargetRequired
+		}
+		if opts.To == id {
+			return result, ErrReassignToSelf
+		}
+	default:
+		return result, ErrInvalidDeleteMode
+	}
+
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		category, err := repos.Category.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		switch opts.Mode {
+		case DeleteDetach:
+			result.ProductsAffected, err = repos.Product.ReassignCategory(id, nil)
+
+		case DeleteRestrict:
+			products, err := repos.Product.GetAll(entity.ProductFilter{CategoryID: id})
+			if err != nil {
+				return err
+			}
+			if len(products) > 0 {
+				return &CategoryInUseError{CategoryID: id, Products: products}
+			}
+
+		case DeleteReassign:
+			if _, err := repos.Category.GetByID(opts.To); errors.Is(err, repository.ErrCategoryNotFound) {
+				return ErrReassignTargetNotFound
+			} else if err != nil {
+				return err
+			}
+			result.ProductsAffected, err = repos.Product.ReassignCategory(id, &opts.To)
+
+		case DeleteCascade:
+			// Produk yang masih menjadi komponen bundle tidak ikut dihapus, seperti DeleteProduct
+			if err := checkNotInBundle(repos, id); err != nil {
+				return err
+			}
+			result.ProductsAffected, result.SubcategoriesDeleted, err = deleteSubtree(repos, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Sub-kategori tetap di pohon: naik satu tingkat ke parent kategori yang dihapus
+		result.SubcategoriesMoved, err = repos.Category.MoveChildren(id, category.ParentID)
+		if err != nil {
+			return err
+		}
+
+		return repos.Category.Delete(id)
+	})
+	if err != nil {
+		return DeleteCategoryResult{Mode: opts.Mode}, err
+	}
+
+	return result, nil
+}
+
+// checkNotInBundle - ErrProductInBundle jika ada produk di kategori atau sub-kategorinya
+// yang masih menjadi komponen bundle
+func checkNotInBundle(repos repository.Repositories, categoryID int) error {
+	products, err := repos.Product.GetAll(entity.ProductFilter{CategoryID: categoryID, IncludeSubcategories: true})
+	if err != nil {
+		return err
+	}
+	for _, p := range products {
+		inBundle, err := repos.Bundle.IsComponent(p.ID, nil)
+		if err != nil {
+			return err
+		}
+		if inBundle {
+			return fmt.Errorf("product %d: %w", p.ID, ErrProductInBundle)
+		}
+	}
+	return nil
 }
 
-// DeleteCategory - hapus kategori
-func (s *CategoryService) DeleteCategory(id int) error {
-	return s.repo.Delete(id)
+// deleteSubtree - soft delete produk di kategori dan semua turunannya, lalu hapus turunannya
+// mulai dari yang terdalam. Kategori categoryID sendiri dihapus oleh pemanggil.
+func deleteSubtree(repos repository.Repositories, categoryID int) (products, subcategories int, err error) {
+	categories, err := repos.Category.GetAll()
+	if err != nil {
+		return 0, 0, err
+	}
+	children := make(map[int][]int)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	// Urutan BFS: parent selalu sebelum anaknya
+	subtree := []int{categoryID}
+	for i := 0; i < len(subtree) && len(subtree) <= len(categories); i++ {
+		subtree = append(subtree, children[subtree[i]]...)
+	}
+
+	for _, id := range subtree {
+		n, err := repos.Product.SoftDeleteByCategory(id)
+		if err != nil {
+			return 0, 0, err
+		}
+		products += n
+	}
+	for i := len(subtree) - 1; i > 0; i-- {
+		if err := repos.Category.Delete(subtree[i]); err != nil {
+			return 0, 0, err
+		}
+		subcategories++
+	}
+	return products, subcategories, nil
 }
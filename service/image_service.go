@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoder GIF untuk image.Decode
+	"image/jpeg"
+	_ "image/png" // decoder PNG untuk image.Decode
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/storage"
+	"net/http"
+)
+
+// Batas gambar produk
+const (
+	MaxImageSize      = 5 << 20 // ukuran file maksimal, 5 MB
+	MaxImageDimension = 4096    // lebar dan tinggi maksimal dalam pixel
+	ThumbnailSize     = 320     // sisi terpanjang thumbnail untuk tile kasir
+)
+
+// Errors for product images
+var (
+	ErrImageTooLarge    = errors.New("image must be at most 5 MB")
+	ErrUnsupportedImage = errors.New("image must be a JPEG, PNG or GIF file")
+	ErrInvalidImage     = errors.New("image file is corrupt or not an image")
+	ErrImageDimensions  = errors.New("image must be at most 4096×4096 pixels")
+)
+
+// imageExtensions - tipe gambar yang diterima beserta ekstensi filenya
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ImageServiceInterface - interface untuk image service
+type ImageServiceInterface interface {
+	UploadProductImage(productID int, data []byte) (entity.ProductImage, error)
+	DeleteProductImage(productID int) error
+}
+
+// ImageService - struct untuk image service
+type ImageService struct {
+	txManager repository.TxManagerInterface
+	files     storage.FileStorageInterface
+}
+
+// NewImageService - constructor untuk ImageService
+func NewImageService(txManager repository.TxManagerInterface, files storage.FileStorageInterface) *ImageService {
+	return &ImageService{txManager: txManager, files: files}
+}
+
+// UploadProductImage - simpan gambar produk beserta thumbnail-nya dan ganti gambar lama.
+// Tipe file dikenali dari isinya, bukan dari nama file atau Content-Type upload.
+// File baru disimpan sebelum database diubah dan dihapus lagi jika gagal; file lama
+// dihapus setelah gambar baru tersimpan.
+func (s *ImageService) UploadProductImage(productID int, data []byte) (entity.ProductImage, error) {
+	if len(data) > MaxImageSize {
+		return entity.ProductImage{}, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return entity.ProductImage{}, ErrUnsupportedImage
+	}
+	thumbnail, err := makeThumbnail(data)
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	// Produk dicek dulu agar tidak ada file yatim untuk produk yang tidak ada
+	if err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		_, err := repos.Product.GetByID(productID)
+		return err
+	}); err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+	uploaded := entity.ProductImage{
+		Key:          fmt.Sprintf("products/%d/%s%s", productID, name, ext),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s-thumb.jpg", productID, name),
+	}
+	if err := s.files.Save(uploaded.Key, contentType, data); err != nil {
+		return entity.ProductImage{}, err
+	}
+	if err := s.files.Save(uploaded.ThumbnailKey, "image/jpeg", thumbnail); err != nil {
+		deleteImageFiles(s.files, &entity.ProductImage{Key: uploaded.Key})
+		return entity.ProductImage{}, err
+	}
+
+	var previous *entity.ProductImage
+	err = s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(productID)
+		if err != nil {
+			return err
+		}
+		previous = product.Image
+		return repos.Product.SetImage(productID, &uploaded)
+	})
+	if err != nil {
+		deleteImageFiles(s.files, &uploaded)
+		return entity.ProductImage{}, err
+	}
+
+	deleteImageFiles(s.files, previous)
+	setImageURLs(s.files, &uploaded)
+	return uploaded, nil
+}
+
+// DeleteProductImage - hapus gambar produk, produk tanpa gambar tidak berubah
+func (s *ImageService) DeleteProductImage(productID int) error {
+	var previous *entity.ProductImage
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(productID)
+		if err != nil || product.Image == nil {
+			return err
+		}
+		previous = product.Image
+		return repos.Product.SetImage(productID, nil)
+	})
+	if err != nil {
+		return err
+	}
+	deleteImageFiles(s.files, previous)
+	return nil
+}
+
+// deleteImageFiles - hapus file gambar dan thumbnail dari storage. Gagal menghapus hanya
+// menyisakan file yang tidak dipakai, jadi error diabaikan.
+func deleteImageFiles(files storage.FileStorageInterface, img *entity.ProductImage) {
+	if img == nil {
+		return
+	}
+	for _, key := range []string{img.Key, img.ThumbnailKey} {
+		if key != "" {
+			files.Delete(key)
+		}
+	}
+}
+
+// setImageURLs - isi URL gambar dan thumbnail dari storage yang dipakai
+func setImageURLs(files storage.FileStorageInterface, img *entity.ProductImage) {
+	if img == nil {
+		return
+	}
+	img.URL = files.URL(img.Key)
+	img.ThumbnailURL = img.URL
+	if img.ThumbnailKey != "" {
+		img.ThumbnailURL = files.URL(img.ThumbnailKey)
+	}
+}
+
+// randomName - nama file acak agar URL gambar baru tidak tertahan cache gambar lama
+func randomName() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// makeThumbnail - JPEG dengan sisi terpanjang ThumbnailSize. Ukuran gambar dicek dari
+// header sebelum di-decode agar gambar raksasa tidak menghabiskan memori.
+func makeThumbnail(data []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width > MaxImageDimension || config.Height > MaxImageDimension {
+		return nil, ErrImageDimensions
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleDown(src, ThumbnailSize), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleDown - perkecil src agar sisi terpanjangnya paling banyak size pixel dengan rata-rata
+// area (box filter). Bagian transparan diberi latar putih karena JPEG tidak punya alpha.
+func scaleDown(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := bounds.Min.Y+y*h/th, bounds.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := bounds.Min.X+x*w/tw, bounds.Min.X+(x+1)*w/tw
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			// Warna sudah premultiplied, di atas putih: warna + (1 - alpha)
+			white := 0xffff - a/n
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r/n + white),
+				G: uint16(g/n + white),
+				B: uint16(b/n + white),
+				A: 0xffff,
+			})
+		}
+	}
+	return dst
+}
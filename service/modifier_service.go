@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"strings"
+)
+
+// Errors for modifier groups and modifier selection
+var (
+	ErrModifierGroupNameRequired = errors.New("modifier group nama is required")
+	ErrModifierNameRequired      = errors.New("modifier nama is required")
+	ErrModifierTarget            = errors.New("modifier group must belong to exactly one of product_id or category_id")
+	ErrModifierRange             = errors.New("min_select must not be negative and max_select must be 0 (no limit) or at least min_select")
+	ErrModifierNotAvailable      = errors.New("modifier is not available for this product")
+	ErrDuplicateModifier         = errors.New("modifier selected more than once")
+	ErrModifierSelection         = errors.New("invalid modifier selection")
+)
+
+// ModifierServiceInterface - interface untuk modifier service
+type ModifierServiceInterface interface {
+	GetGroups() ([]entity.ModifierGroup, error)
+	GetGroup(id int) (entity.ModifierGroup, error)
+	GetProductModifiers(productID int) ([]entity.ModifierGroup, error)
+	CreateGroup(group entity.ModifierGroup) (entity.ModifierGroup, error)
+	UpdateGroup(id int, group entity.ModifierGroup) (entity.ModifierGroup, error)
+	DeleteGroup(id int) error
+	CreateModifier(groupID int, modifier entity.Modifier) (entity.Modifier, error)
+	UpdateModifier(groupID, modifierID int, modifier entity.Modifier) (entity.Modifier, error)
+	DeleteModifier(groupID, modifierID int) error
+}
+
+// ModifierService - struct untuk modifier service
+type ModifierService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewModifierService - constructor untuk ModifierService
+func NewModifierService(txManager repository.TxManagerInterface) *ModifierService {
+	return &ModifierService{txManager: txManager}
+}
+
+// GetGroups - semua grup modifier beserta pilihannya
+func (s *ModifierService) GetGroups() ([]entity.ModifierGroup, error) {
+	var groups []entity.ModifierGroup
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		groups, err = repos.Modifier.GetAllGroups()
+		return err
+	})
+	if groups == nil && err == nil {
+		groups = []entity.ModifierGroup{}
+	}
+	return groups, err
+}
+
+// GetGroup - satu grup modifier beserta pilihannya
+func (s *ModifierService) GetGroup(id int) (entity.ModifierGroup, error) {
+	var group entity.ModifierGroup
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		group, err = repos.Modifier.GetGroupByID(id)
+		return err
+	})
+	return group, err
+}
+
+// GetProductModifiers - grup modifier yang berlaku untuk produk: milik produk itu sendiri
+// dan milik kategorinya beserta semua kategori induknya
+func (s *ModifierService) GetProductModifiers(productID int) ([]entity.ModifierGroup, error) {
+	var groups []entity.ModifierGroup
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(productID)
+		if err != nil {
+			return err
+		}
+		groups, err = modifierGroupsFor(repos, product)
+		return err
+	})
+	if groups == nil && err == nil {
+		groups = []entity.ModifierGroup{}
+	}
+	return groups, err
+}
+
+// CreateGroup - tambah grup modifier beserta pilihan awalnya dalam satu transaksi
+func (s *ModifierService) CreateGroup(group entity.ModifierGroup) (entity.ModifierGroup, error) {
+	if err := validateModifierGroup(&group); err != nil {
+		return entity.ModifierGroup{}, err
+	}
+	for i := range group.Modifiers {
+		if err := validateModifier(&group.Modifiers[i]); err != nil {
+			return entity.ModifierGroup{}, err
+		}
+	}
+
+	var created entity.ModifierGroup
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if err := checkModifierTarget(repos, group); err != nil {
+			return err
+		}
+		g, err := repos.Modifier.CreateGroup(group)
+		if err != nil {
+			return err
+		}
+		for _, m := range group.Modifiers {
+			m.GroupID = g.ID
+			if _, err := repos.Modifier.CreateModifier(m); err != nil {
+				return err
+			}
+		}
+		created, err = repos.Modifier.GetGroupByID(g.ID)
+		return err
+	})
+	return created, err
+}
+
+// UpdateGroup - update nama, tempat menempel dan batas pilihan grup. Pilihan diubah
+// lewat endpoint modifiers.
+func (s *ModifierService) UpdateGroup(id int, group entity.ModifierGroup) (entity.ModifierGroup, error) {
+	if err := validateModifierGroup(&group); err != nil {
+		return entity.ModifierGroup{}, err
+	}
+
+	var updated entity.ModifierGroup
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := repos.Modifier.GetGroupByID(id); err != nil {
+			return err
+		}
+		if err := checkModifierTarget(repos, group); err != nil {
+			return err
+		}
+		var err error
+		updated, err = repos.Modifier.UpdateGroup(id, group)
+		return err
+	})
+	return updated, err
+}
+
+// DeleteGroup - hapus grup beserta pilihannya, riwayat penjualan tetap ada
+func (s *ModifierService) DeleteGroup(id int) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		return repos.Modifier.DeleteGroup(id)
+	})
+}
+
+// CreateModifier - tambah pilihan ke grup
+func (s *ModifierService) CreateModifier(groupID int, modifier entity.Modifier) (entity.Modifier, error) {
+	if err := validateModifier(&modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+
+	var created entity.Modifier
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := repos.Modifier.GetGroupByID(groupID); err != nil {
+			return err
+		}
+		modifier.GroupID = groupID
+		var err error
+		created, err = repos.Modifier.CreateModifier(modifier)
+		return err
+	})
+	return created, err
+}
+
+// UpdateModifier - update nama dan harga pilihan
+func (s *ModifierService) UpdateModifier(groupID, modifierID int, modifier entity.Modifier) (entity.Modifier, error) {
+	if err := validateModifier(&modifier); err != nil {
+		return entity.Modifier{}, err
+	}
+
+	var updated entity.Modifier
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := modifierOf(repos, groupID, modifierID); err != nil {
+			return err
+		}
+		var err error
+		updated, err = repos.Modifier.UpdateModifier(modifierID, modifier)
+		return err
+	})
+	return updated, err
+}
+
+// DeleteModifier - hapus pilihan, riwayat penjualan tetap ada
+func (s *ModifierService) DeleteModifier(groupID, modifierID int) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := modifierOf(repos, groupID, modifierID); err != nil {
+			return err
+		}
+		return repos.Modifier.DeleteModifier(modifierID)
+	})
+}
+
+// modifierOf - pilihan milik grup, ErrModifierNotFound jika milik grup lain
+func modifierOf(repos repository.Repositories, groupID, modifierID int) (entity.Modifier, error) {
+	if _, err := repos.Modifier.GetGroupByID(groupID); err != nil {
+		return entity.Modifier{}, err
+	}
+	modifier, err := repos.Modifier.GetModifierByID(modifierID)
+	if err != nil {
+		return entity.Modifier{}, err
+	}
+	if modifier.GroupID != groupID {
+		return entity.Modifier{}, repository.ErrModifierNotFound
+	}
+	return modifier, nil
+}
+
+// checkModifierTarget - produk atau kategori tempat grup menempel harus ada
+func checkModifierTarget(repos repository.Repositories, group entity.ModifierGroup) error {
+	if group.ProductID != nil {
+		_, err := repos.Product.GetByID(*group.ProductID)
+		return err
+	}
+	_, err := repos.Category.GetByID(*group.CategoryID)
+	return err
+}
+
+// modifierGroupsFor - grup milik produk dan milik kategori produk beserta induknya
+func modifierGroupsFor(repos repository.Repositories, product entity.Product) ([]entity.ModifierGroup, error) {
+	var categoryIDs []int
+	if product.CategoryID != nil {
+		path, err := repos.Category.GetPath(*product.CategoryID)
+		if err != nil && !errors.Is(err, repository.ErrCategoryNotFound) {
+			return nil, err
+		}
+		for _, crumb := range path {
+			categoryIDs = append(categoryIDs, crumb.ID)
+		}
+	}
+	return repos.Modifier.GetGroupsFor(product.ID, categoryIDs)
+}
+
+// selectModifiers - salin modifier yang dipilih untuk baris penjualan, urut sesuai pilihan.
+// Setiap modifier harus berlaku untuk produk, tidak boleh dipilih dua kali, dan jumlah
+// pilihan per grup harus di antara min_select dan max_select.
+func selectModifiers(repos repository.Repositories, product entity.Product, modifierIDs []int) ([]entity.TransactionDetailModifier, error) {
+	groups, err := modifierGroupsFor(repos, product)
+	if err != nil {
+		return nil, err
+	}
+
+	type choice struct {
+		group    *entity.ModifierGroup
+		modifier entity.Modifier
+	}
+	available := make(map[int]choice)
+	for i := range groups {
+		for _, m := range groups[i].Modifiers {
+			available[m.ID] = choice{group: &groups[i], modifier: m}
+		}
+	}
+
+	selected := make([]entity.TransactionDetailModifier, 0, len(modifierIDs))
+	seen := make(map[int]bool, len(modifierIDs))
+	counts := make(map[int]int, len(groups))
+	for _, id := range modifierIDs {
+		c, ok := available[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: modifier %d", ErrModifierNotAvailable, id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateModifier, c.modifier.Nama)
+		}
+		seen[id] = true
+		counts[c.group.ID]++
+
+		modifierID := c.modifier.ID
+		selected = append(selected, entity.TransactionDetailModifier{
+			ModifierID: &modifierID,
+			GroupNama:  c.group.Nama,
+			Nama:       c.modifier.Nama,
+			Harga:      c.modifier.Harga,
+		})
+	}
+
+	for _, g := range groups {
+		n := counts[g.ID]
+		if n < g.MinSelect {
+			return nil, fmt.Errorf("%w: %s requires at least %d choice(s)", ErrModifierSelection, g.Nama, g.MinSelect)
+		}
+		if g.MaxSelect > 0 && n > g.MaxSelect {
+			return nil, fmt.Errorf("%w: %s allows at most %d choice(s)", ErrModifierSelection, g.Nama, g.MaxSelect)
+		}
+	}
+	return selected, nil
+}
+
+// validateModifierGroup - nama wajib, menempel ke tepat satu produk atau kategori, batas pilihan
+// masuk akal. Required tanpa min_select berarti pilih minimal satu.
+func validateModifierGroup(group *entity.ModifierGroup) error {
+	group.Nama = strings.TrimSpace(group.Nama)
+	if group.Required && group.MinSelect == 0 {
+		group.MinSelect = 1
+	}
+	group.Required = group.MinSelect > 0
+
+	switch {
+	case group.Nama == "":
+		return ErrModifierGroupNameRequired
+	case (group.ProductID == nil) == (group.CategoryID == nil):
+		return ErrModifierTarget
+	case group.MinSelect < 0 || group.MaxSelect < 0 || (group.MaxSelect > 0 && group.MaxSelect < group.MinSelect):
+		return ErrModifierRange
+	}
+	return nil
+}
+
+// validateModifier - nama wajib, harga tidak negatif
+func validateModifier(modifier *entity.Modifier) error {
+	modifier.Nama = strings.TrimSpace(modifier.Nama)
+	switch {
+	case modifier.Nama == "":
+		return ErrModifierNameRequired
+	case modifier.Harga.IsNegative():
+		return ErrNegativeHarga
+	}
+	return nil
+}
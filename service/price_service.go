@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"time"
+)
+
+// Errors for price scheduling
+var (
+	ErrPriceInPast           = errors.New("effective_from must not be in the past")
+	ErrPriceCurrencyMismatch = errors.New("price must use the same currency as the product")
+	ErrPriceAlreadyEffective = errors.New("price is already effective and can no longer be cancelled")
+)
+
+// PriceServiceInterface - interface untuk price service
+type PriceServiceInterface interface {
+	GetPrices(productID int) ([]entity.ProductPrice, error)
+	SchedulePrice(productID int, req entity.SchedulePriceRequest) (entity.ProductPrice, error)
+	CancelPrice(productID, priceID int) error
+}
+
+// PriceService - struct untuk price service
+type PriceService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewPriceService - constructor untuk PriceService
+func NewPriceService(txManager repository.TxManagerInterface) *PriceService {
+	return &PriceService{txManager: txManager}
+}
+
+// GetPrices - riwayat dan jadwal harga produk, urut dari yang paling lama
+func (s *PriceService) GetPrices(productID int) ([]entity.ProductPrice, error) {
+	var prices []entity.ProductPrice
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := repos.Product.GetByID(productID); err != nil {
+			return err
+		}
+		var err error
+		prices, err = repos.Price.GetByProduct(productID)
+		return err
+	})
+	if prices == nil && err == nil {
+		prices = []entity.ProductPrice{}
+	}
+	return prices, err
+}
+
+// SchedulePrice - jadwalkan harga baru mulai EffectiveFrom (kosong = sekarang).
+// Harga yang berlaku sekarang juga disalin ke products.harga.
+func (s *PriceService) SchedulePrice(productID int, req entity.SchedulePriceRequest) (entity.ProductPrice, error) {
+	if req.Harga.IsNegative() {
+		return entity.ProductPrice{}, ErrNegativeHarga
+	}
+	now := currentTime()
+	from := req.EffectiveFrom.UTC().Truncate(time.Microsecond)
+	if req.EffectiveFrom.IsZero() {
+		from = now
+	}
+	if from.Before(now) {
+		return entity.ProductPrice{}, ErrPriceInPast
+	}
+
+	var scheduled entity.ProductPrice
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(productID)
+		if err != nil {
+			return err
+		}
+		if req.Harga.Cur() != product.Harga.Cur() {
+			return ErrPriceCurrencyMismatch
+		}
+
+		if scheduled, err = schedulePrice(repos, productID, req.Harga, from); err != nil {
+			return err
+		}
+		if scheduled.CoversTime(now) {
+			product.Harga = req.Harga
+			_, err = repos.Product.Update(productID, product)
+		}
+		return err
+	})
+	return scheduled, err
+}
+
+// CancelPrice - batalkan harga yang belum berlaku, rentang sebelumnya diperpanjang
+func (s *PriceService) CancelPrice(productID, priceID int) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		price, err := repos.Price.GetByID(priceID)
+		if err != nil {
+			return err
+		}
+		if price.ProductID != productID {
+			return repository.ErrPriceNotFound
+		}
+		if !price.EffectiveFrom.After(currentTime()) {
+			return ErrPriceAlreadyEffective
+		}
+
+		prices, err := repos.Price.GetByProduct(productID)
+		if err != nil {
+			return err
+		}
+		if err := repos.Price.Delete(priceID); err != nil {
+			return err
+		}
+		if prev := previousPrice(prices, price.EffectiveFrom); prev != nil {
+			prev.EffectiveTo = price.EffectiveTo
+			return repos.Price.Update(*prev)
+		}
+		return nil
+	})
+}
+
+// schedulePrice - sisipkan harga mulai from dan jaga rentang tetap bersambung:
+// harga sebelumnya berakhir di from, harga baru berakhir di jadwal berikutnya (jika ada).
+// Harga yang mulai tepat di from diganti nilainya.
+func schedulePrice(repos repository.Repositories, productID int, harga entity.Money, from time.Time) (entity.ProductPrice, error) {
+	from = from.UTC().Truncate(time.Microsecond)
+	prices, err := repos.Price.GetByProduct(productID)
+	if err != nil {
+		return entity.ProductPrice{}, err
+	}
+
+	price := entity.ProductPrice{ProductID: productID, Harga: harga, EffectiveFrom: from}
+	for _, p := range prices {
+		if p.EffectiveFrom.Equal(from) {
+			p.Harga = harga
+			return p, repos.Price.Update(p)
+		}
+		if p.EffectiveFrom.After(from) {
+			next := p.EffectiveFrom
+			price.EffectiveTo = &next
+			break
+		}
+	}
+
+	if prev := previousPrice(prices, from); prev != nil {
+		prev.EffectiveTo = &from
+		if err := repos.Price.Update(*prev); err != nil {
+			return entity.ProductPrice{}, err
+		}
+	}
+	return repos.Price.Create(price)
+}
+
+// previousPrice - harga terakhir yang mulai sebelum t, prices urut effective_from
+func previousPrice(prices []entity.ProductPrice, t time.Time) *entity.ProductPrice {
+	var prev *entity.ProductPrice
+	for i := range prices {
+		if !prices[i].EffectiveFrom.Before(t) {
+			break
+		}
+		prev = &prices[i]
+	}
+	return prev
+}
+
+// effectivePrice - harga produk pada waktu at, products.harga jika produk belum punya riwayat harga
+func effectivePrice(repos repository.Repositories, product entity.Product, at time.Time) (entity.Money, error) {
+	price, err := repos.Price.GetEffective(product.ID, at)
+	if errors.Is(err, repository.ErrPriceNotFound) {
+		return product.Harga, nil
+	}
+	if err != nil {
+		return entity.Money{}, err
+	}
+	return price.Harga, nil
+}
+
+// currentTime - sekarang dalam UTC dengan presisi kolom TIMESTAMP
+func currentTime() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
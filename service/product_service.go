@@ -1,67 +1,504 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"kasir-api/entity"
 	"kasir-api/repository"
+	"kasir-api/storage"
 )
 
+// Errors for product pricing
+var (
+	ErrNegativeHarga        = errors.New("harga and harga_beli must not be negative")
+	ErrCostCurrencyMismatch = errors.New("harga_beli must use the same currency as harga")
+)
+
+// Errors for product types, stock and bundles
+var (
+	ErrInvalidProductType       = errors.New("type must be single or bundle")
+	ErrBundleComponentsRequired = errors.New("bundle must contain at least one component")
+	ErrComponentsOnSingle       = errors.New("only bundle products can have components")
+	ErrNestedBundle             = errors.New("a bundle cannot contain itself or another bundle")
+	ErrDuplicateComponent       = errors.New("component appears more than once in the bundle")
+	ErrComponentNotFound        = errors.New("component product not found")
+	ErrBundleStock              = errors.New("bundle stock follows its components and cannot be set")
+	ErrBundleVariants           = errors.New("bundle products cannot have variants")
+	ErrProductInBundle          = errors.New("product is still a component of a bundle")
+)
+
+// ProductInclude - data tambahan yang disertakan pada daftar produk
+type ProductInclude struct {
+	Category bool // JOIN kategori
+	Variants bool // varian setiap produk
+}
+
 // ProductServiceInterface - interface untuk product service
 type ProductServiceInterface interface {
-	GetAllProducts() ([]entity.Product, error)
+	GetAllProducts(filter entity.ProductFilter, include ProductInclude) ([]entity.Product, error)
+	GetProductsByCategory(categoryID int, recursive bool, include ProductInclude) ([]entity.Product, error)
 	GetProductByID(id int) (entity.Product, error)
 	CreateProduct(product entity.Product) (entity.Product, error)
 	UpdateProduct(id int, product entity.Product) (entity.Product, error)
 	DeleteProduct(id int) error
+	AdjustStock(id int, delta entity.Quantity, unit string) (entity.Product, error)
 }
 
 // ProductService - struct untuk product service
 type ProductService struct {
-	productRepo  repository.ProductRepositoryInterface
-	categoryRepo repository.CategoryRepositoryInterface
+	txManager repository.TxManagerInterface
+	files     storage.FileStorageInterface
 }
 
-// NewProductService - constructor untuk ProductService
-func NewProductService(productRepo repository.ProductRepositoryInterface, categoryRepo repository.CategoryRepositoryInterface) *ProductService {
+// NewProductService - constructor untuk ProductService.
+// files dipakai untuk URL gambar produk dan menghapus gambar produk yang dihapus.
+func NewProductService(txManager repository.TxManagerInterface, files storage.FileStorageInterface) *ProductService {
 	return &ProductService{
-		productRepo:  productRepo,
-		categoryRepo: categoryRepo,
+		txManager: txManager,
+		files:     files,
 	}
 }
 
-// GetAllProducts - ambil semua produk
-func (s *ProductService) GetAllProducts() ([]entity.Product, error) {
-	return s.productRepo.GetAll()
+// GetAllProducts - ambil produk sesuai filter beserta data tambahan dari include.
+// Harga yang ditampilkan adalah harga yang berlaku sekarang.
+func (s *ProductService) GetAllProducts(filter entity.ProductFilter, include ProductInclude) ([]entity.Product, error) {
+	var products []entity.Product
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		products, err = listProducts(repos, filter, include)
+		return err
+	})
+	s.attachImageURLs(products)
+	return products, err
 }
 
-// GetProductByID - ambil produk berdasarkan ID dengan join category
-func (s *ProductService) GetProductByID(id int) (entity.Product, error) {
-	// Ambil produk dari repository
-	product, err := s.productRepo.GetByID(id)
+// GetProductsByCategory - ambil produk dalam kategori, recursive menyertakan semua sub-kategori
+func (s *ProductService) GetProductsByCategory(categoryID int, recursive bool, include ProductInclude) ([]entity.Product, error) {
+	var products []entity.Product
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := repos.Category.GetByID(categoryID); err != nil {
+			return err
+		}
+
+		var err error
+		products, err = listProducts(repos, entity.ProductFilter{CategoryID: categoryID, IncludeSubcategories: recursive}, include)
+		return err
+	})
+	s.attachImageURLs(products)
+	return products, err
+}
+
+// listProducts - produk sesuai filter dengan harga yang berlaku sekarang
+func listProducts(repos repository.Repositories, filter entity.ProductFilter, include ProductInclude) ([]entity.Product, error) {
+	var products []entity.Product
+	var err error
+	if include.Category {
+		products, err = repos.Product.GetAllWithCategory(filter)
+	} else {
+		products, err = repos.Product.GetAll(filter)
+	}
+	if err != nil || len(products) == 0 {
+		return products, err
+	}
+
+	prices, err := repos.Price.CurrentPrices(currentTime())
 	if err != nil {
-		return entity.Product{}, err
+		return nil, err
+	}
+	ids := make([]int, len(products))
+	var bundleIDs []int
+	for i := range products {
+		ids[i] = products[i].ID
+		if harga, ok := prices[products[i].ID]; ok {
+			products[i].Harga = harga
+		}
+		if products[i].IsBundle() {
+			bundleIDs = append(bundleIDs, products[i].ID)
+		}
 	}
 
-	// Join: Ambil category berdasarkan CategoryID
-	category, err := s.categoryRepo.GetByID(product.CategoryID)
-	if err == nil {
-		// Jika category ditemukan, tambahkan ke product
-		product.Category = &category
+	// Isi paket selalu disertakan, tanpa komponen bundle tidak punya arti
+	components, err := repos.Bundle.GetComponentsByBundles(bundleIDs)
+	if err != nil {
+		return nil, err
 	}
+	// Satuan lain juga selalu disertakan agar kasir bisa memilih dus atau pcs
+	units, err := repos.Unit.GetConversionsByProducts(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		products[i].Components = components[products[i].ID]
+		products[i].Units = units[products[i].ID]
+	}
+
+	if include.Variants {
+		variants, err := repos.Variant.GetByProducts(ids)
+		if err != nil {
+			return nil, err
+		}
+		for i := range products {
+			products[i].Variants = variants[products[i].ID]
+		}
+	}
+	return products, nil
+}
 
-	return product, nil
+// GetProductByID - ambil produk berdasarkan ID dengan join category (satu query)
+// beserta harga yang berlaku sekarang, semua variannya, satuan lainnya dan isi paket untuk bundle
+func (s *ProductService) GetProductByID(id int) (entity.Product, error) {
+	var product entity.Product
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		if product, err = repos.Product.GetByIDWithCategory(id); err != nil {
+			return err
+		}
+		if product.Harga, err = effectivePrice(repos, product, currentTime()); err != nil {
+			return err
+		}
+		if product.Variants, err = repos.Variant.GetByProduct(id); err != nil {
+			return err
+		}
+		if product.Units, err = repos.Unit.GetConversions(id); err != nil {
+			return err
+		}
+		product.Components, err = repos.Bundle.GetComponents(id)
+		return err
+	})
+	setImageURLs(s.files, product.Image)
+	return product, err
 }
 
-// CreateProduct - tambah produk baru
+// CreateProduct - tambah produk baru, kategori, satuan dan komponen bundle dicek dalam transaksi yang sama
 func (s *ProductService) CreateProduct(product entity.Product) (entity.Product, error) {
-	return s.productRepo.Create(product)
+	if product.Type == "" {
+		product.Type = entity.ProductTypeSingle
+	}
+	product.Image = nil // gambar lewat POST /api/produk/{id}/image
+	normalizeProductUnits(&product)
+	if product.Unit == "" {
+		product.Unit = entity.DefaultUnit
+	}
+	if err := validatePrices(product); err != nil {
+		return entity.Product{}, err
+	}
+	if err := validateProductType(product); err != nil {
+		return entity.Product{}, err
+	}
+
+	var created entity.Product
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if err := checkCategoryExists(repos, product.CategoryID); err != nil {
+			return err
+		}
+		if err := checkProductUnits(repos, product); err != nil {
+			return err
+		}
+		if product.IsBundle() {
+			if err := checkComponents(repos, 0, product.Components); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if created, err = repos.Product.Create(product); err != nil {
+			return err
+		}
+		if _, err = schedulePrice(repos, created.ID, created.Harga, currentTime()); err != nil {
+			return err
+		}
+		if err := setProductUnits(repos, &created, product.Units); err != nil {
+			return err
+		}
+		return setComponents(repos, &created, product.Components)
+	})
+	return created, err
 }
 
-// UpdateProduct - update produk
+// UpdateProduct - update produk, kategori dicek dalam transaksi yang sama.
+// harga_beli, stock, type, unit, purchase_unit, units dan components yang tidak dikirim
+// mempertahankan nilai yang tersimpan; "harga_beli": null menghapus harga beli.
+// Perubahan harga dicatat di riwayat harga mulai sekarang.
 func (s *ProductService) UpdateProduct(id int, product entity.Product) (entity.Product, error) {
-	return s.productRepo.Update(id, product)
+	if err := validatePrices(product); err != nil {
+		return entity.Product{}, err
+	}
+
+	normalizeProductUnits(&product)
+
+	var updated entity.Product
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if err := checkCategoryExists(repos, product.CategoryID); err != nil {
+			return err
+		}
+		current, err := repos.Product.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if product.HargaBeli == nil && !product.ClearHargaBeli && current.Harga.Cur() == product.Harga.Cur() {
+			product.HargaBeli = current.HargaBeli
+		}
+		if product.Stock == nil && !product.IsBundle() {
+			product.Stock = current.Stock
+		}
+		if product.Type == "" {
+			product.Type = current.Type
+		}
+		product.Image = current.Image
+		if product.Unit == "" {
+			product.Unit = current.Unit
+		}
+		if product.PurchaseUnit == "" {
+			product.PurchaseUnit = current.PurchaseUnit
+		}
+		if product.Units == nil {
+			if product.Units, err = repos.Unit.GetConversions(id); err != nil {
+				return err
+			}
+		}
+		if err := checkProductUnits(repos, product); err != nil {
+			return err
+		}
+		if product.IsBundle() && product.Components == nil && current.IsBundle() {
+			if product.Components, err = repos.Bundle.GetComponents(id); err != nil {
+				return err
+			}
+		}
+		if err := validateProductType(product); err != nil {
+			return err
+		}
+		if product.IsBundle() {
+			if err := checkBecomesBundle(repos, current); err != nil {
+				return err
+			}
+			if err := checkComponents(repos, id, product.Components); err != nil {
+				return err
+			}
+		}
+
+		if updated, err = repos.Product.Update(id, product); err != nil {
+			return err
+		}
+		if err := setProductUnits(repos, &updated, product.Units); err != nil {
+			return err
+		}
+		if err := setComponents(repos, &updated, product.Components); err != nil {
+			return err
+		}
+		return recordPriceChange(repos, updated)
+	})
+	setImageURLs(s.files, updated.Image)
+	return updated, err
 }
 
-// DeleteProduct - hapus produk
+// AdjustStock - tambah (restock) atau kurangi (koreksi) stok produk secara atomik.
+// delta dalam satuan unit, kosong berarti satuan beli produk (atau satuan dasar jika tidak ada),
+// dan dikonversi ke satuan dasar: restock 2 dus menambah 48 pcs. Stok bundle mengikuti komponennya.
+func (s *ProductService) AdjustStock(id int, delta entity.Quantity, unit string) (entity.Product, error) {
+	var adjusted entity.Product
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if product.IsBundle() {
+			return ErrBundleStock
+		}
+		if unit == "" {
+			unit = product.PurchaseUnit
+		}
+		base, _, err := toBaseQuantity(repos, product, delta, unit)
+		if err != nil {
+			return err
+		}
+		if adjusted, err = repos.Product.AdjustStock(id, base); err != nil {
+			return err
+		}
+		adjusted.Units, err = repos.Unit.GetConversions(id)
+		return err
+	})
+	setImageURLs(s.files, adjusted.Image)
+	return adjusted, err
+}
+
+// recordPriceChange - catat harga baru mulai sekarang jika berbeda dari harga yang berlaku
+func recordPriceChange(repos repository.Repositories, product entity.Product) error {
+	now := currentTime()
+	current, err := repos.Price.GetEffective(product.ID, now)
+	if err != nil && !errors.Is(err, repository.ErrPriceNotFound) {
+		return err
+	}
+	if err == nil && current.Harga == product.Harga {
+		return nil
+	}
+	_, err = schedulePrice(repos, product.ID, product.Harga, now)
+	return err
+}
+
+// DeleteProduct - hapus produk beserta file gambarnya, produk yang masih menjadi komponen
+// bundle tidak bisa dihapus
 func (s *ProductService) DeleteProduct(id int) error {
-	return s.productRepo.Delete(id)
+	var image *entity.ProductImage
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		inBundle, err := repos.Bundle.IsComponent(id, nil)
+		if err != nil {
+			return err
+		}
+		if inBundle {
+			return ErrProductInBundle
+		}
+		product, err := repos.Product.GetByID(id)
+		if err != nil {
+			return err
+		}
+		image = product.Image
+		return repos.Product.Delete(id)
+	})
+	if err != nil {
+		return err
+	}
+	deleteImageFiles(s.files, image)
+	return nil
+}
+
+// attachImageURLs - isi URL gambar setiap produk dari storage yang dipakai
+func (s *ProductService) attachImageURLs(products []entity.Product) {
+	for i := range products {
+		setImageURLs(s.files, products[i].Image)
+	}
+}
+
+// validateProductType - jenis produk dikenal, bundle wajib punya komponen dan tidak punya stok sendiri,
+// produk biasa tidak punya komponen. Stok tidak boleh negatif.
+func validateProductType(product entity.Product) error {
+	switch {
+	case product.Type != entity.ProductTypeSingle && product.Type != entity.ProductTypeBundle:
+		return ErrInvalidProductType
+	case product.Stock != nil && *product.Stock < 0:
+		return ErrNegativeStock
+	case !product.IsBundle() && len(product.Components) > 0:
+		return ErrComponentsOnSingle
+	case product.IsBundle() && len(product.Components) == 0:
+		return ErrBundleComponentsRequired
+	case product.IsBundle() && product.Stock != nil:
+		return ErrBundleStock
+	}
+	return nil
+}
+
+// checkBecomesBundle - produk yang dijadikan bundle tidak boleh punya varian atau menjadi komponen bundle lain
+func checkBecomesBundle(repos repository.Repositories, current entity.Product) error {
+	if current.IsBundle() {
+		return nil
+	}
+	variants, err := repos.Variant.GetByProduct(current.ID)
+	if err != nil {
+		return err
+	}
+	if len(variants) > 0 {
+		return ErrBundleVariants
+	}
+	inBundle, err := repos.Bundle.IsComponent(current.ID, nil)
+	if err != nil {
+		return err
+	}
+	if inBundle {
+		return ErrNestedBundle
+	}
+	return nil
+}
+
+// checkComponents - setiap komponen adalah produk biasa lain yang ada, dengan varian jika
+// produknya punya varian, quantity positif dan tidak dobel
+func checkComponents(repos repository.Repositories, bundleID int, components []entity.BundleComponent) error {
+	type key struct{ product, variant int }
+	seen := make(map[key]bool, len(components))
+	for i, c := range components {
+		if err := checkComponent(repos, bundleID, c); err != nil {
+			return fmt.Errorf("components[%d]: %w", i, err)
+		}
+		k := key{product: c.ProductID}
+		if c.VariantID != nil {
+			k.variant = *c.VariantID
+		}
+		if seen[k] {
+			return fmt.Errorf("components[%d]: %w", i, ErrDuplicateComponent)
+		}
+		seen[k] = true
+	}
+	return nil
+}
+
+// checkComponent - validasi satu komponen bundle
+func checkComponent(repos repository.Repositories, bundleID int, c entity.BundleComponent) error {
+	if c.Quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	if c.ProductID == bundleID {
+		return ErrNestedBundle
+	}
+	product, err := repos.Product.GetByID(c.ProductID)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		return ErrComponentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if product.IsBundle() {
+		return ErrNestedBundle
+	}
+
+	if c.VariantID != nil {
+		_, err := variantOf(repos, product.ID, *c.VariantID)
+		return err
+	}
+	variants, err := repos.Variant.GetByProduct(product.ID)
+	if err != nil {
+		return err
+	}
+	if len(variants) > 0 {
+		return ErrVariantRequired
+	}
+	return nil
+}
+
+// setComponents - simpan isi paket produk (kosong untuk produk biasa) dan isi Components
+// dengan nama komponen seperti saat dibaca
+func setComponents(repos repository.Repositories, product *entity.Product, components []entity.BundleComponent) error {
+	if !product.IsBundle() {
+		components = nil
+	}
+	if err := repos.Bundle.SetComponents(product.ID, components); err != nil {
+		return err
+	}
+	var err error
+	product.Components, err = repos.Bundle.GetComponents(product.ID)
+	return err
+}
+
+// validatePrices - harga dan harga beli tidak negatif dan memakai mata uang yang sama
+func validatePrices(product entity.Product) error {
+	if product.Harga.IsNegative() {
+		return ErrNegativeHarga
+	}
+	if product.HargaBeli == nil {
+		return nil
+	}
+	if product.HargaBeli.IsNegative() {
+		return ErrNegativeHarga
+	}
+	if product.HargaBeli.Currency != "" && product.HargaBeli.Cur() != product.Harga.Cur() {
+		return ErrCostCurrencyMismatch
+	}
+	return nil
+}
+
+// checkCategoryExists - validasi kategori produk, nil berarti produk tanpa kategori
+func checkCategoryExists(repos repository.Repositories, categoryID *int) error {
+	if categoryID == nil {
+		return nil
+	}
+	_, err := repos.Category.GetByID(*categoryID)
+	return err
 }
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Errors for promotions
+var (
+	ErrPromotionNameRequired = errors.New("promotion nama is required")
+	ErrPromotionType         = errors.New("type must be percentage, fixed, bogo or threshold")
+	ErrPromotionScope        = errors.New("scope must be product with product_id, category with category_id, or cart")
+	ErrPromotionPercent      = errors.New("percent must be greater than 0 and at most 100")
+	ErrPromotionAmount       = errors.New("amount must be greater than zero")
+	ErrPromotionBOGO         = errors.New("buy_quantity and get_quantity must be greater than zero")
+	ErrPromotionThreshold    = errors.New("threshold promotion needs min_subtotal and exactly one of amount or percent")
+	ErrPromotionRange        = errors.New("ends_at must be after starts_at")
+	ErrPromotionDays         = errors.New("days must be between 0 (Sunday) and 6 (Saturday)")
+)
+
+// PromotionServiceInterface - interface untuk promotion service
+type PromotionServiceInterface interface {
+	GetAll() ([]entity.Promotion, error)
+	GetByID(id int) (entity.Promotion, error)
+	Create(promotion entity.Promotion) (entity.Promotion, error)
+	Update(id int, promotion entity.Promotion) (entity.Promotion, error)
+	Delete(id int) error
+}
+
+// PromotionService - struct untuk promotion service
+type PromotionService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewPromotionService - constructor untuk PromotionService
+func NewPromotionService(txManager repository.TxManagerInterface) *PromotionService {
+	return &PromotionService{txManager: txManager}
+}
+
+// GetAll - semua promosi, termasuk yang belum mulai atau sudah berakhir
+func (s *PromotionService) GetAll() ([]entity.Promotion, error) {
+	var promotions []entity.Promotion
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		promotions, err = repos.Promotion.GetAll()
+		return err
+	})
+	if promotions == nil && err == nil {
+		promotions = []entity.Promotion{}
+	}
+	return promotions, err
+}
+
+// GetByID - ambil promosi berdasarkan ID
+func (s *PromotionService) GetByID(id int) (entity.Promotion, error) {
+	var promotion entity.Promotion
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		promotion, err = repos.Promotion.GetByID(id)
+		return err
+	})
+	return promotion, err
+}
+
+// Create - tambah promosi baru
+func (s *PromotionService) Create(promotion entity.Promotion) (entity.Promotion, error) {
+	if err := validatePromotion(&promotion); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	var created entity.Promotion
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if err := checkPromotionTarget(repos, promotion); err != nil {
+			return err
+		}
+		var err error
+		created, err = repos.Promotion.Create(promotion)
+		return err
+	})
+	return created, err
+}
+
+// Update - ubah promosi, transaksi lama tidak berubah
+func (s *PromotionService) Update(id int, promotion entity.Promotion) (entity.Promotion, error) {
+	if err := validatePromotion(&promotion); err != nil {
+		return entity.Promotion{}, err
+	}
+
+	var updated entity.Promotion
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := repos.Promotion.GetByID(id); err != nil {
+			return err
+		}
+		if err := checkPromotionTarget(repos, promotion); err != nil {
+			return err
+		}
+		var err error
+		updated, err = repos.Promotion.Update(id, promotion)
+		return err
+	})
+	return updated, err
+}
+
+// Delete - hapus promosi, potongan di transaksi lama tetap ada
+func (s *PromotionService) Delete(id int) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		return repos.Promotion.Delete(id)
+	})
+}
+
+// validatePromotion - cek aturan per jenis promosi. Field yang tidak dipakai jenis atau
+// scope-nya dikosongkan agar promosi yang tersimpan tidak ambigu.
+func validatePromotion(p *entity.Promotion) error {
+	p.Nama = strings.TrimSpace(p.Nama)
+	if p.Nama == "" {
+		return ErrPromotionNameRequired
+	}
+
+	switch p.Scope {
+	case entity.PromotionScopeProduct:
+		if p.ProductID == nil {
+			return ErrPromotionScope
+		}
+		p.CategoryID = nil
+	case entity.PromotionScopeCategory:
+		if p.CategoryID == nil {
+			return ErrPromotionScope
+		}
+		p.ProductID = nil
+	case entity.PromotionScopeCart:
+		p.ProductID, p.CategoryID = nil, nil
+	default:
+		return ErrPromotionScope
+	}
+
+	switch p.Type {
+	case entity.PromotionPercentage:
+		if p.Percent <= 0 || p.Percent > 10000 {
+			return ErrPromotionPercent
+		}
+		p.Amount, p.MinSubtotal, p.BuyQuantity, p.GetQuantity = nil, nil, 0, 0
+	case entity.PromotionFixed:
+		if p.Amount == nil || p.Amount.Amount <= 0 {
+			return ErrPromotionAmount
+		}
+		p.Percent, p.MinSubtotal, p.BuyQuantity, p.GetQuantity = 0, nil, 0, 0
+	case entity.PromotionBOGO:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return ErrPromotionBOGO
+		}
+		p.Percent, p.Amount, p.MinSubtotal = 0, nil, nil
+	case entity.PromotionThreshold:
+		if p.MinSubtotal == nil || p.MinSubtotal.IsNegative() || (p.Amount == nil) == (p.Percent == 0) {
+			return ErrPromotionThreshold
+		}
+		if p.Amount != nil && p.Amount.Amount <= 0 {
+			return ErrPromotionAmount
+		}
+		if p.Percent < 0 || p.Percent > 10000 {
+			return ErrPromotionPercent
+		}
+		if p.Amount != nil && p.Amount.Cur() != p.MinSubtotal.Cur() {
+			return ErrMixedCurrency
+		}
+		p.BuyQuantity, p.GetQuantity = 0, 0
+	default:
+		return ErrPromotionType
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return ErrPromotionRange
+	}
+	for _, day := range p.Days {
+		if day < 0 || day > 6 {
+			return ErrPromotionDays
+		}
+	}
+	return nil
+}
+
+// checkPromotionTarget - produk atau kategori promosi harus ada
+func checkPromotionTarget(repos repository.Repositories, p entity.Promotion) error {
+	switch {
+	case p.ProductID != nil:
+		_, err := repos.Product.GetByID(*p.ProductID)
+		return err
+	case p.CategoryID != nil:
+		_, err := repos.Category.GetByID(*p.CategoryID)
+		return err
+	}
+	return nil
+}
+
+// applyPromotions - hitung diskon promosi yang berlaku pada t.CreatedAt untuk baris t.Details
+// yang subtotalnya sudah terisi, lalu isi Discount per baris, Discounts, Subtotal,
+// DiscountAmount dan TotalAmount.
+//
+// Promosi dihitung berurutan dari priority tertinggi (lalu ID terkecil) terhadap sisa harga
+// setiap baris setelah diskon sebelumnya. Promosi yang tidak stackable hanya untuk baris yang
+// belum didiskon dan mengunci baris yang didiskonnya; promosi stackable boleh menambah diskon
+// pada baris yang belum terkunci. Hari berlaku mengikuti zona waktu server.
+func applyPromotions(repos repository.Repositories, t *entity.Transaction) error {
+	currency := entity.DefaultCurrency
+	if len(t.Details) > 0 {
+		currency = t.Details[0].Subtotal.Cur()
+	}
+	subtotal := entity.NewMoney(0, currency)
+	for i := range t.Details {
+		t.Details[i].Discount = entity.NewMoney(0, currency)
+		var err error
+		if subtotal, err = subtotal.Add(t.Details[i].Subtotal); err != nil {
+			if errors.Is(err, entity.ErrCurrencyMismatch) {
+				return &CartItemError{Index: i, Err: ErrMixedCurrency}
+			}
+			return err
+		}
+	}
+
+	promotions, err := repos.Promotion.GetActive(t.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	scope := newCategoryScope(repos)
+	locked := make([]bool, len(t.Details))
+	discount := entity.NewMoney(0, currency)
+	t.Discounts = nil
+	for _, promo := range promotions {
+		if !promo.ActiveOn(t.CreatedAt, time.Local) || !promotionCurrency(promo, currency) {
+			continue
+		}
+
+		var eligible []int
+		for i, d := range t.Details {
+			if locked[i] || (!promo.Stackable && !d.Discount.IsZero()) {
+				continue
+			}
+			in, err := promotionContains(scope, promo, d)
+			if err != nil {
+				return err
+			}
+			if in {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+
+		amounts, err := promotionDiscounts(promo, t.Details, eligible)
+		if err != nil {
+			return err
+		}
+		total := entity.NewMoney(0, currency)
+		for k, i := range eligible {
+			if amounts[k].IsZero() {
+				continue
+			}
+			d := &t.Details[i]
+			if d.Discount, err = d.Discount.Add(amounts[k]); err != nil {
+				return err
+			}
+			if total, err = total.Add(amounts[k]); err != nil {
+				return err
+			}
+			if !promo.Stackable {
+				locked[i] = true
+			}
+		}
+		if total.IsZero() {
+			continue
+		}
+		id := promo.ID
+		t.Discounts = append(t.Discounts, entity.TransactionDiscount{PromotionID: &id, Nama: promo.Nama, Amount: total})
+		if discount, err = discount.Add(total); err != nil {
+			return err
+		}
+	}
+
+	t.Subtotal = subtotal
+	t.DiscountAmount = discount
+	t.TotalAmount, err = subtotal.Sub(discount)
+	return err
+}
+
+// promotionCurrency - potongan nominal promosi harus dalam mata uang transaksi
+func promotionCurrency(p entity.Promotion, currency string) bool {
+	if p.Amount != nil && p.Amount.Cur() != currency {
+		return false
+	}
+	return p.MinSubtotal == nil || p.MinSubtotal.Cur() == currency
+}
+
+// categoryScope - kategori produk setiap baris penjualan beserta semua induknya, di-cache
+// per produk. Dipakai untuk scope promosi dan pembebasan pajak per kategori.
+type categoryScope struct {
+	repos      repository.Repositories
+	categories map[int]map[int]bool // product ID -> kategori produk beserta semua induknya
+}
+
+// newCategoryScope - constructor untuk categoryScope dengan cache kosong
+func newCategoryScope(repos repository.Repositories) categoryScope {
+	return categoryScope{repos: repos, categories: make(map[int]map[int]bool)}
+}
+
+// inCategory - produk baris d ada di kategori categoryID atau salah satu sub-kategorinya
+func (s categoryScope) inCategory(d entity.TransactionDetail, categoryID int) (bool, error) {
+	if d.ProductID == nil {
+		return false, nil
+	}
+	categories, ok := s.categories[*d.ProductID]
+	if !ok {
+		categories = make(map[int]bool)
+		product, err := s.repos.Product.GetByID(*d.ProductID)
+		if err != nil {
+			return false, err
+		}
+		if product.CategoryID != nil {
+			path, err := s.repos.Category.GetPath(*product.CategoryID)
+			if err != nil && !errors.Is(err, repository.ErrCategoryNotFound) {
+				return false, err
+			}
+			for _, crumb := range path {
+				categories[crumb.ID] = true
+			}
+		}
+		s.categories[*d.ProductID] = categories
+	}
+	return categories[categoryID], nil
+}
+
+// promotionContains - baris d masuk scope promosi p. Scope category juga mencakup sub-kategorinya.
+func promotionContains(s categoryScope, p entity.Promotion, d entity.TransactionDetail) (bool, error) {
+	switch p.Scope {
+	case entity.PromotionScopeCart:
+		return true, nil
+	case entity.PromotionScopeProduct:
+		return d.ProductID != nil && *d.ProductID == *p.ProductID, nil
+	}
+	return s.inCategory(d, *p.CategoryID)
+}
+
+// promotionDiscounts - potongan promosi untuk setiap baris eligible (indeks ke details),
+// tidak pernah melebihi sisa harga baris
+func promotionDiscounts(p entity.Promotion, details []entity.TransactionDetail, eligible []int) ([]entity.Money, error) {
+	remaining := make([]entity.Money, len(eligible))
+	sum := entity.NewMoney(0, details[eligible[0]].Subtotal.Cur())
+	for k, i := range eligible {
+		var err error
+		if remaining[k], err = details[i].Subtotal.Sub(details[i].Discount); err != nil {
+			return nil, err
+		}
+		if sum, err = sum.Add(remaining[k]); err != nil {
+			return nil, err
+		}
+	}
+
+	amounts := make([]entity.Money, len(eligible))
+	switch {
+	case p.Type == entity.PromotionPercentage:
+		for k := range eligible {
+			var err error
+			if amounts[k], err = p.Percent.Of(remaining[k]); err != nil {
+				return nil, err
+			}
+		}
+
+	case p.Type == entity.PromotionFixed && p.Scope != entity.PromotionScopeCart:
+		// Potongan per satuan jual, 1,5 kg mendapat 1,5 × potongan
+		for k, i := range eligible {
+			off, err := details[i].Quantity.Price(*p.Amount)
+			if err != nil {
+				return nil, err
+			}
+			amounts[k] = minMoney(off, remaining[k])
+		}
+
+	case p.Type == entity.PromotionFixed:
+		return allocateDiscount(minMoney(*p.Amount, sum), remaining)
+
+	case p.Type == entity.PromotionThreshold:
+		if sum.Amount < p.MinSubtotal.Amount {
+			return amounts, nil
+		}
+		off := sum
+		if p.Amount != nil {
+			off = minMoney(*p.Amount, sum)
+		} else {
+			var err error
+			if off, err = p.Percent.Of(sum); err != nil {
+				return nil, err
+			}
+		}
+		return allocateDiscount(off, remaining)
+
+	case p.Type == entity.PromotionBOGO:
+		return bogoDiscounts(p, details, eligible, remaining)
+	}
+	return amounts, nil
+}
+
+// bogoDiscounts - beli BuyQuantity gratis GetQuantity. Dari total unit eligible, setiap
+// kelompok buy+get unit memberi get unit gratis; unit gratis diambil dari baris termurah
+// per unit. Hanya baris dengan quantity utuh yang ikut.
+func bogoDiscounts(p entity.Promotion, details []entity.TransactionDetail, eligible []int, remaining []entity.Money) ([]entity.Money, error) {
+	amounts := make([]entity.Money, len(eligible))
+	for k := range amounts {
+		amounts[k] = entity.NewMoney(0, remaining[k].Cur())
+	}
+
+	// Satu tingkat harga per baris: units unit seharga remaining/units
+	var lines []int
+	total := int64(0)
+	for k, i := range eligible {
+		q := details[i].Quantity
+		if !q.IsWhole() || q.Units() <= 0 {
+			continue
+		}
+		lines = append(lines, k)
+		total += q.Units()
+	}
+	units := func(k int) int64 { return details[eligible[k]].Quantity.Units() }
+	sort.SliceStable(lines, func(a, b int) bool {
+		// remaining[a]/units(a) < remaining[b]/units(b) tanpa pembulatan
+		left := new(big.Int).Mul(big.NewInt(remaining[lines[a]].Amount), big.NewInt(units(lines[b])))
+		right := new(big.Int).Mul(big.NewInt(remaining[lines[b]].Amount), big.NewInt(units(lines[a])))
+		return left.Cmp(right) < 0
+	})
+
+	group := int64(p.BuyQuantity + p.GetQuantity)
+	free := total / group * int64(p.GetQuantity)
+	for _, k := range lines {
+		if free == 0 {
+			break
+		}
+		n := units(k)
+		take := n
+		if free < take {
+			take = free
+		}
+		free -= take
+		amount, err := remaining[k].MulRatio(take, n)
+		if err != nil {
+			return nil, err
+		}
+		amounts[k] = minMoney(amount, remaining[k])
+	}
+	return amounts, nil
+}
+
+// allocateDiscount - bagi potongan total ke baris sebanding sisa harganya. Pembulatan
+// memakai sisa terbesar agar jumlahnya tepat sama dengan total.
+func allocateDiscount(total entity.Money, weights []entity.Money) ([]entity.Money, error) {
+	amounts := make([]entity.Money, len(weights))
+	sum := new(big.Int)
+	for k, w := range weights {
+		amounts[k] = entity.NewMoney(0, total.Cur())
+		sum.Add(sum, big.NewInt(w.Amount))
+	}
+	if sum.Sign() <= 0 || total.Amount <= 0 {
+		return amounts, nil
+	}
+
+	remainders := make([]*big.Int, len(weights))
+	allocated := int64(0)
+	for k, w := range weights {
+		share := new(big.Int).Mul(big.NewInt(total.Amount), big.NewInt(w.Amount))
+		quotient, remainder := new(big.Int).QuoRem(share, sum, new(big.Int))
+		amounts[k].Amount = quotient.Int64()
+		remainders[k] = remainder
+		allocated += amounts[k].Amount
+	}
+
+	order := make([]int, len(weights))
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+	for _, k := range order[:total.Amount-allocated] {
+		amounts[k].Amount++
+	}
+	return amounts, nil
+}
+
+// minMoney - nilai terkecil dari a dan b yang bermata uang sama
+func minMoney(a, b entity.Money) entity.Money {
+	if b.Amount < a.Amount {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"kasir-api/repository/memory"
+)
+
+// newTestRepos - repository in-memory kosong untuk test service
+func newTestRepos() repository.Repositories {
+	repos, _ := newTestStore()
+	return repos
+}
+
+// newTestStore - store in-memory kosong beserta TxManager untuk service
+func newTestStore() (repository.Repositories, repository.TxManagerInterface) {
+	store := memory.NewStore()
+	return memory.NewRepositories(store), memory.NewTxManager(store)
+}
+
+// createProducts - produk 1..n tanpa kategori untuk baris keranjang
+func createProducts(t *testing.T, repos repository.Repositories, n int) {
+	t.Helper()
+	for i := 1; i <= n; i++ {
+		if _, err := repos.Product.Create(entity.Product{Nama: fmt.Sprintf("Produk %d", i), Harga: entity.IDR(1000)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// cartLine - baris keranjang produk productID dengan subtotal quantity × harga
+func cartLine(t *testing.T, productID int, quantity entity.Quantity, harga int64) entity.TransactionDetail {
+	t.Helper()
+	subtotal, err := quantity.Price(entity.IDR(harga))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entity.TransactionDetail{ProductID: &productID, Quantity: quantity, BaseQuantity: quantity, Harga: entity.IDR(harga), Subtotal: subtotal}
+}
+
+// lineDiscounts - diskon setiap baris dalam rupiah utuh
+func lineDiscounts(details []entity.TransactionDetail) []int64 {
+	discounts := make([]int64, len(details))
+	for i, d := range details {
+		discounts[i] = d.Discount.Amount / 100
+	}
+	return discounts
+}
+
+func equalInts(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestApplyPromotions(t *testing.T) {
+	at := time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC)
+	amount := func(rupiah int64) *entity.Money {
+		m := entity.IDR(rupiah)
+		return &m
+	}
+
+	tests := []struct {
+		name       string
+		promotions []entity.Promotion
+		lines      func(t *testing.T) []entity.TransactionDetail
+		want       []int64 // diskon per baris, rupiah
+		applied    int     // jumlah promosi di Discounts
+	}{
+		{
+			// 6 unit utuh: 2 gratis, diambil dari tingkat harga termurah (5.000 lalu 8.000).
+			// Baris 1,5 kg tidak ikut dihitung.
+			name: "bogo across mixed price tiers",
+			promotions: []entity.Promotion{
+				{Nama: "Beli 2 Gratis 1", Type: entity.PromotionBOGO, Scope: entity.PromotionScopeCart, BuyQuantity: 2, GetQuantity: 1},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{
+					cartLine(t, 1, entity.Qty(2), 10000),
+					cartLine(t, 2, entity.Qty(1), 5000),
+					cartLine(t, 3, entity.Qty(3), 8000),
+					cartLine(t, 4, 1500, 2000),
+				}
+			},
+			want:    []int64{0, 5000, 8000, 0},
+			applied: 1,
+		},
+		{
+			// 5 unit seharga 4.000 (setelah diskon) dan 2 unit seharga 3.000: 2 gratis dari baris 3.000
+			name: "bogo uses remaining price after earlier discount",
+			promotions: []entity.Promotion{
+				{Nama: "Diskon 20%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeProduct, ProductID: intPtr(1), Percent: 2000, Priority: 1, Stackable: true},
+				{Nama: "Beli 2 Gratis 1", Type: entity.PromotionBOGO, Scope: entity.PromotionScopeCart, BuyQuantity: 2, GetQuantity: 1, Stackable: true},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{
+					cartLine(t, 1, entity.Qty(5), 5000),
+					cartLine(t, 2, entity.Qty(2), 3000),
+				}
+			},
+			want:    []int64{5000, 6000},
+			applied: 2,
+		},
+		{
+			name: "non-stackable first at equal priority locks the line",
+			promotions: []entity.Promotion{
+				{Nama: "Diskon 10%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCart, Percent: 1000},
+				{Nama: "Diskon 50%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCart, Percent: 5000, Stackable: true},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{cartLine(t, 1, entity.Qty(1), 10000)}
+			},
+			want:    []int64{1000},
+			applied: 1,
+		},
+		{
+			name: "non-stackable second at equal priority skips discounted lines",
+			promotions: []entity.Promotion{
+				{Nama: "Diskon 10%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeProduct, ProductID: intPtr(1), Percent: 1000, Stackable: true},
+				{Nama: "Diskon 50%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCart, Percent: 5000},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{
+					cartLine(t, 1, entity.Qty(1), 10000),
+					cartLine(t, 2, entity.Qty(1), 10000),
+				}
+			},
+			want:    []int64{1000, 5000},
+			applied: 2,
+		},
+		{
+			// 10% dari 10.000 lalu 50% dari sisa 9.000
+			name: "stackable promotions apply to the remaining price",
+			promotions: []entity.Promotion{
+				{Nama: "Diskon 10%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCart, Percent: 1000, Stackable: true},
+				{Nama: "Diskon 50%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCart, Percent: 5000, Stackable: true},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{cartLine(t, 1, entity.Qty(1), 10000)}
+			},
+			want:    []int64{5500},
+			applied: 2,
+		},
+		{
+			name: "threshold exactly at min_subtotal applies",
+			promotions: []entity.Promotion{
+				{Nama: "Potongan 5.000", Type: entity.PromotionThreshold, Scope: entity.PromotionScopeCart, MinSubtotal: amount(50000), Amount: amount(5000)},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{
+					cartLine(t, 1, entity.Qty(2), 15000),
+					cartLine(t, 2, entity.Qty(1), 20000),
+				}
+			},
+			want:    []int64{3000, 2000},
+			applied: 1,
+		},
+		{
+			name: "threshold one rupiah below min_subtotal does not apply",
+			promotions: []entity.Promotion{
+				{Nama: "Potongan 5.000", Type: entity.PromotionThreshold, Scope: entity.PromotionScopeCart, MinSubtotal: amount(50000), Amount: amount(5000)},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{
+					cartLine(t, 1, entity.Qty(2), 15000),
+					cartLine(t, 2, entity.Qty(1), 19999),
+				}
+			},
+			want:    []int64{0, 0},
+			applied: 0,
+		},
+		{
+			// Potongan lebih besar dari belanja: diskon = subtotal, total 0 (migrasi 015: discount <= subtotal)
+			name: "cart fixed discount larger than subtotal",
+			promotions: []entity.Promotion{
+				{Nama: "Potongan 100.000", Type: entity.PromotionFixed, Scope: entity.PromotionScopeCart, Amount: amount(100000)},
+			},
+			lines: func(t *testing.T) []entity.TransactionDetail {
+				return []entity.TransactionDetail{
+					cartLine(t, 1, entity.Qty(1), 10000),
+					cartLine(t, 2, entity.Qty(2), 10000),
+				}
+			},
+			want:    []int64{10000, 20000},
+			applied: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := newTestRepos()
+			createProducts(t, repos, 4)
+			for _, p := range tt.promotions {
+				if _, err := repos.Promotion.Create(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			transaction := entity.Transaction{CreatedAt: at, Details: tt.lines(t)}
+			if err := applyPromotions(repos, &transaction); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := lineDiscounts(transaction.Details); !equalInts(got, tt.want) {
+				t.Errorf("line discounts = %v, want %v", got, tt.want)
+			}
+			if len(transaction.Discounts) != tt.applied {
+				t.Errorf("applied %d promotions, want %d: %+v", len(transaction.Discounts), tt.applied, transaction.Discounts)
+			}
+			// Total diskon sama dengan jumlah diskon baris dan tidak pernah melebihi subtotal
+			sum := int64(0)
+			for i, d := range transaction.Details {
+				if d.Discount.Amount > d.Subtotal.Amount {
+					t.Errorf("line %d discount %v exceeds subtotal %v", i, d.Discount, d.Subtotal)
+				}
+				sum += d.Discount.Amount
+			}
+			if transaction.DiscountAmount.Amount != sum || transaction.DiscountAmount.Amount > transaction.Subtotal.Amount {
+				t.Errorf("discount %v, line sum %d, subtotal %v", transaction.DiscountAmount, sum, transaction.Subtotal)
+			}
+			if transaction.TotalAmount.Amount != transaction.Subtotal.Amount-sum {
+				t.Errorf("total %v, want subtotal %v - discount %d", transaction.TotalAmount, transaction.Subtotal, sum)
+			}
+		})
+	}
+}
+
+func TestApplyPromotionsWeekday(t *testing.T) {
+	// Hari berlaku mengikuti zona waktu server, di sini WIB (UTC+7)
+	local := time.Local
+	time.Local = time.FixedZone("WIB", 7*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		applies bool
+	}{
+		{"friday evening UTC is saturday in WIB", time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC), true},
+		{"friday afternoon UTC is still friday in WIB", time.Date(2026, 10, 16, 16, 59, 59, 0, time.UTC), false},
+		{"saturday evening UTC is sunday in WIB", time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := newTestRepos()
+			weekend := entity.Promotion{Nama: "Sabtu 10%", Type: entity.PromotionPercentage, Scope: entity.PromotionScopeCart, Percent: 1000, Days: []int{6}}
+			if _, err := repos.Promotion.Create(weekend); err != nil {
+				t.Fatal(err)
+			}
+			transaction := entity.Transaction{CreatedAt: tt.at, Details: []entity.TransactionDetail{cartLine(t, 1, entity.Qty(1), 10000)}}
+			if err := applyPromotions(repos, &transaction); err != nil {
+				t.Fatal(err)
+			}
+			if applied := !transaction.DiscountAmount.IsZero(); applied != tt.applies {
+				t.Errorf("promotion applied = %v, want %v (discount %v)", applied, tt.applies, transaction.DiscountAmount)
+			}
+		})
+	}
+}
+
+func TestBogoDiscounts(t *testing.T) {
+	promo := entity.Promotion{Type: entity.PromotionBOGO, BuyQuantity: 1, GetQuantity: 1}
+	tests := []struct {
+		name  string
+		lines []struct {
+			quantity entity.Quantity
+			harga    int64
+		}
+		want []int64
+	}{
+		{
+			// 4 unit, 2 gratis: keduanya dari baris termurah
+			name: "cheapest units are free",
+			lines: []struct {
+				quantity entity.Quantity
+				harga    int64
+			}{{entity.Qty(1), 9000}, {entity.Qty(3), 4000}},
+			want: []int64{0, 8000},
+		},
+		{
+			// 3 unit, 1 gratis; unit gratis sebagian dari baris 3 unit
+			name: "odd number of units",
+			lines: []struct {
+				quantity entity.Quantity
+				harga    int64
+			}{{entity.Qty(3), 7000}},
+			want: []int64{7000},
+		},
+		{
+			name: "fractional quantities are ignored",
+			lines: []struct {
+				quantity entity.Quantity
+				harga    int64
+			}{{2500, 4000}, {entity.Qty(1), 6000}},
+			want: []int64{0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var details []entity.TransactionDetail
+			var eligible []int
+			var remaining []entity.Money
+			for i, l := range tt.lines {
+				d := cartLine(t, i+1, l.quantity, l.harga)
+				details = append(details, d)
+				eligible = append(eligible, i)
+				remaining = append(remaining, d.Subtotal)
+			}
+			amounts, err := bogoDiscounts(promo, details, eligible, remaining)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]int64, len(amounts))
+			for k, a := range amounts {
+				got[k] = a.Amount / 100
+			}
+			if !equalInts(got, tt.want) {
+				t.Errorf("bogo discounts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	money := func(amounts ...int64) []entity.Money {
+		m := make([]entity.Money, len(amounts))
+		for i, a := range amounts {
+			m[i] = entity.NewMoney(a, "IDR")
+		}
+		return m
+	}
+	tests := []struct {
+		name    string
+		total   int64
+		weights []entity.Money
+		want    []int64
+	}{
+		{"proportional", 300, money(100, 200), []int64{100, 200}},
+		{"largest remainder gets the extra unit", 100, money(1, 1, 1), []int64{34, 33, 33}},
+		{"ties go to the earlier line", 2, money(1, 1, 1), []int64{1, 1, 0}},
+		{"remainder follows the fraction, not the weight", 10, money(3333, 3333, 3334), []int64{3, 3, 4}},
+		{"zero weights get nothing", 50, money(0, 100, 0), []int64{0, 50, 0}},
+		{"nothing to allocate", 0, money(100, 100), []int64{0, 0}},
+		{"all weights zero", 100, money(0, 0), []int64{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts, err := allocateDiscount(entity.NewMoney(tt.total, "IDR"), tt.weights)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]int64, len(amounts))
+			for k, a := range amounts {
+				got[k] = a.Amount
+			}
+			if !equalInts(got, tt.want) {
+				t.Errorf("allocateDiscount(%d) = %v, want %v", tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"math"
+	"sort"
+)
+
+// ErrInvalidGroupBy - pengelompokan laporan tidak dikenal
+var ErrInvalidGroupBy = errors.New("margin report can only be grouped by product or category")
+
+// ReportServiceInterface - interface untuk report service
+type ReportServiceInterface interface {
+	MarginReport(filter entity.MarginFilter) ([]entity.MarginRow, error)
+	TaxReport(filter entity.TaxFilter) ([]entity.TaxRow, error)
+}
+
+// ReportService - struct untuk report service
+type ReportService struct {
+	transactionRepo repository.TransactionRepositoryInterface
+}
+
+// NewReportService - constructor untuk ReportService
+func NewReportService(transactionRepo repository.TransactionRepositoryInterface) *ReportService {
+	return &ReportService{transactionRepo: transactionRepo}
+}
+
+// MarginReport - margin kotor per produk atau kategori, diurutkan dari margin terbesar.
+// Baris tanpa harga beli tidak ikut dihitung dalam margin, lihat UncostedRevenue.
+func (s *ReportService) MarginReport(filter entity.MarginFilter) ([]entity.MarginRow, error) {
+	if filter.GroupBy == "" {
+		filter.GroupBy = entity.MarginByProduct
+	}
+	if filter.GroupBy != entity.MarginByProduct && filter.GroupBy != entity.MarginByCategory {
+		return nil, ErrInvalidGroupBy
+	}
+
+	rows, err := s.transactionRepo.MarginReport(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if row.Name == "" && filter.GroupBy == entity.MarginByCategory {
+			row.Name = "Tanpa Kategori"
+		}
+
+		costedRevenue, err := row.Revenue.Sub(row.UncostedRevenue)
+		if err != nil {
+			return nil, err
+		}
+		if row.GrossMargin, err = costedRevenue.Sub(row.Cost); err != nil {
+			return nil, err
+		}
+		if !costedRevenue.IsZero() {
+			percent := float64(row.GrossMargin.Amount) / float64(costedRevenue.Amount) * 100
+			row.MarginPercent = math.Round(percent*100) / 100
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].GrossMargin.Amount != rows[j].GrossMargin.Amount {
+			return rows[i].GrossMargin.Amount > rows[j].GrossMargin.Amount
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	if rows == nil {
+		rows = []entity.MarginRow{}
+	}
+	return rows, nil
+}
+
+// TaxReport - pajak yang dipungut per tarif dalam rentang waktu, dari salinan pajak di
+// setiap transaksi sehingga tarif yang sudah diubah atau dihapus tetap terlapor
+func (s *ReportService) TaxReport(filter entity.TaxFilter) ([]entity.TaxRow, error) {
+	rows, err := s.transactionRepo.TaxReport(filter)
+	if rows == nil && err == nil {
+		rows = []entity.TaxRow{}
+	}
+	return rows, err
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Errors for tax rates
+var (
+	ErrTaxNameRequired     = errors.New("tax rate nama is required")
+	ErrTaxRate             = errors.New("rate must be greater than 0 and at most 100")
+	ErrTaxCompoundIncluded = errors.New("compound only applies to exclusive taxes")
+)
+
+// TaxServiceInterface - interface untuk tax service
+type TaxServiceInterface interface {
+	GetAll() ([]entity.TaxRate, error)
+	GetByID(id int) (entity.TaxRate, error)
+	Create(rate entity.TaxRate) (entity.TaxRate, error)
+	Update(id int, rate entity.TaxRate) (entity.TaxRate, error)
+	Delete(id int) error
+}
+
+// TaxService - struct untuk tax service
+type TaxService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewTaxService - constructor untuk TaxService
+func NewTaxService(txManager repository.TxManagerInterface) *TaxService {
+	return &TaxService{txManager: txManager}
+}
+
+// GetAll - semua tarif pajak dalam urutan hitung
+func (s *TaxService) GetAll() ([]entity.TaxRate, error) {
+	var rates []entity.TaxRate
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		rates, err = repos.TaxRate.GetAll()
+		return err
+	})
+	if rates == nil && err == nil {
+		rates = []entity.TaxRate{}
+	}
+	return rates, err
+}
+
+// GetByID - ambil tarif pajak berdasarkan ID
+func (s *TaxService) GetByID(id int) (entity.TaxRate, error) {
+	var rate entity.TaxRate
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		rate, err = repos.TaxRate.GetByID(id)
+		return err
+	})
+	return rate, err
+}
+
+// Create - tambah tarif pajak, kategori yang dibebaskan harus ada
+func (s *TaxService) Create(rate entity.TaxRate) (entity.TaxRate, error) {
+	if err := validateTaxRate(&rate); err != nil {
+		return entity.TaxRate{}, err
+	}
+
+	var created entity.TaxRate
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if err := checkExemptCategories(repos, rate.ExemptCategoryIDs); err != nil {
+			return err
+		}
+		var err error
+		created, err = repos.TaxRate.Create(rate)
+		return err
+	})
+	return created, err
+}
+
+// Update - ubah tarif pajak, hanya berlaku untuk transaksi berikutnya
+func (s *TaxService) Update(id int, rate entity.TaxRate) (entity.TaxRate, error) {
+	if err := validateTaxRate(&rate); err != nil {
+		return entity.TaxRate{}, err
+	}
+
+	var updated entity.TaxRate
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if err := checkExemptCategories(repos, rate.ExemptCategoryIDs); err != nil {
+			return err
+		}
+		var err error
+		updated, err = repos.TaxRate.Update(id, rate)
+		return err
+	})
+	return updated, err
+}
+
+// Delete - hapus tarif pajak, pajak di transaksi lama tetap ada
+func (s *TaxService) Delete(id int) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		return repos.TaxRate.Delete(id)
+	})
+}
+
+// validateTaxRate - cek tarif dan rapikan daftar kategori yang dibebaskan (urut, tanpa duplikat)
+func validateTaxRate(t *entity.TaxRate) error {
+	t.Nama = strings.TrimSpace(t.Nama)
+	if t.Nama == "" {
+		return ErrTaxNameRequired
+	}
+	if t.Rate <= 0 || t.Rate > 10000 {
+		return ErrTaxRate
+	}
+	if t.Inclusive && t.Compound {
+		return ErrTaxCompoundIncluded
+	}
+
+	exempt := make([]int, 0, len(t.ExemptCategoryIDs))
+	seen := make(map[int]bool, len(t.ExemptCategoryIDs))
+	for _, id := range t.ExemptCategoryIDs {
+		if !seen[id] {
+			seen[id] = true
+			exempt = append(exempt, id)
+		}
+	}
+	sort.Ints(exempt)
+	t.ExemptCategoryIDs = exempt
+	return nil
+}
+
+// checkExemptCategories - semua kategori yang dibebaskan harus ada
+func checkExemptCategories(repos repository.Repositories, ids []int) error {
+	for _, id := range ids {
+		if _, err := repos.Category.GetByID(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTaxes - hitung semua tarif pajak untuk baris t.Details setelah diskon, lalu isi
+// TaxIncluded per baris, Taxes, TaxAmount dan TotalAmount.
+//
+// Tarif dihitung berurutan sesuai position. Setiap tarif dibulatkan sekali dari jumlah
+// semua barisnya ke minor unit terdekat (setengah menjauhi nol), bukan per baris, lalu
+// dibagi ke baris dengan sisa terbesar. Pajak inclusive diambil dari dalam harga:
+// harga × tarif / (100% + jumlah tarif inclusive yang berlaku untuk baris itu). Pajak
+// compound dihitung dari harga ditambah bagian baris dari pajak exclusive sebelumnya.
+func applyTaxes(repos repository.Repositories, t *entity.Transaction) error {
+	rates, err := repos.TaxRate.GetAll()
+	if err != nil {
+		return err
+	}
+
+	currency := t.Subtotal.Cur()
+	net := make([]int64, len(t.Details))
+	for i, d := range t.Details {
+		remaining, err := d.Subtotal.Sub(d.Discount)
+		if err != nil {
+			return err
+		}
+		net[i] = remaining.Amount
+		t.Details[i].TaxIncluded = entity.NewMoney(0, currency)
+	}
+
+	// Baris yang dikenai setiap tarif, dan jumlah tarif inclusive per baris
+	scope := newCategoryScope(repos)
+	applies := make([][]bool, len(rates))
+	included := make([]int64, len(t.Details))
+	for k, rate := range rates {
+		applies[k] = make([]bool, len(t.Details))
+		for i, d := range t.Details {
+			exempt := false
+			for _, categoryID := range rate.ExemptCategoryIDs {
+				if exempt, err = scope.inCategory(d, categoryID); err != nil {
+					return err
+				}
+				if exempt {
+					break
+				}
+			}
+			applies[k][i] = !exempt && net[i] > 0
+			if applies[k][i] && rate.Inclusive {
+				included[i] += int64(rate.Rate)
+			}
+		}
+	}
+
+	added := make([]int64, len(t.Details)) // bagian baris dari pajak exclusive sebelumnya
+	var taxAmount int64
+	t.Taxes = nil
+	for k, rate := range rates {
+		shares := make([]*big.Rat, len(t.Details))
+		taxable := new(big.Rat)
+		var gross, inclusiveTotal big.Rat // hanya untuk pajak inclusive
+		applied := false
+		for i := range t.Details {
+			shares[i] = new(big.Rat)
+			if !applies[k][i] {
+				continue
+			}
+			applied = true
+			if rate.Inclusive {
+				// harga × tarif / (10000 + jumlah tarif inclusive), semua dalam basis poin
+				shares[i].SetFrac(big.NewInt(net[i]), big.NewInt(10000+included[i]))
+				inclusiveTotal.Add(&inclusiveTotal, new(big.Rat).Mul(shares[i], big.NewRat(included[i], 1)))
+				shares[i].Mul(shares[i], big.NewRat(int64(rate.Rate), 1))
+				gross.Add(&gross, big.NewRat(net[i], 1))
+				continue
+			}
+			base := big.NewRat(net[i], 1)
+			if rate.Compound {
+				base.Add(base, big.NewRat(added[i], 1))
+			}
+			taxable.Add(taxable, base)
+			shares[i].Mul(base, big.NewRat(int64(rate.Rate), 10000))
+		}
+		if !applied {
+			continue
+		}
+		if rate.Inclusive {
+			// Dasar pengenaan = harga - semua pajak inclusive di dalamnya
+			taxable.Sub(&gross, new(big.Rat).SetInt64(roundRat(&inclusiveTotal)))
+		}
+
+		amounts, total := roundShares(shares)
+		for i, amount := range amounts {
+			if rate.Inclusive {
+				t.Details[i].TaxIncluded.Amount += amount
+			} else {
+				added[i] += amount
+			}
+		}
+		if !rate.Inclusive {
+			taxAmount += total
+		}
+		id := rate.ID
+		t.Taxes = append(t.Taxes, entity.TransactionTax{
+			TaxRateID:     &id,
+			Nama:          rate.Nama,
+			Rate:          rate.Rate,
+			Inclusive:     rate.Inclusive,
+			TaxableAmount: entity.NewMoney(roundRat(taxable), currency),
+			Amount:        entity.NewMoney(total, currency),
+		})
+	}
+
+	t.TaxAmount = entity.NewMoney(taxAmount, currency)
+	beforeTax, err := t.Subtotal.Sub(t.DiscountAmount)
+	if err != nil {
+		return err
+	}
+	t.TotalAmount, err = beforeTax.Add(t.TaxAmount)
+	return err
+}
+
+// roundRat - r dibulatkan ke bilangan bulat terdekat, setengah menjauhi nol. r tidak negatif.
+func roundRat(r *big.Rat) int64 {
+	num := new(big.Int).Mul(r.Num(), big.NewInt(2))
+	num.Add(num, r.Denom())
+	return num.Quo(num, new(big.Int).Mul(r.Denom(), big.NewInt(2))).Int64()
+}
+
+// roundShares - jumlah shares dibulatkan sekali dengan roundRat, lalu dibagi kembali ke setiap
+// share: bagian bulatnya dulu, sisanya satu per satu ke share dengan pecahan terbesar.
+func roundShares(shares []*big.Rat) ([]int64, int64) {
+	sum := new(big.Rat)
+	for _, share := range shares {
+		sum.Add(sum, share)
+	}
+	total := roundRat(sum)
+
+	amounts := make([]int64, len(shares))
+	fractions := make([]*big.Rat, len(shares))
+	allocated := int64(0)
+	for k, share := range shares {
+		floor := new(big.Int).Quo(share.Num(), share.Denom())
+		amounts[k] = floor.Int64()
+		fractions[k] = new(big.Rat).Sub(share, new(big.Rat).SetInt(floor))
+		allocated += amounts[k]
+	}
+
+	order := make([]int, len(shares))
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return fractions[order[a]].Cmp(fractions[order[b]]) > 0
+	})
+	for _, k := range order[:total-allocated] {
+		amounts[k]++
+	}
+	return amounts, total
+}
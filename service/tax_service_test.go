@@ -0,0 +1,213 @@
+package service
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// taxLine - baris keranjang produk productID dengan subtotal dalam minor unit, tanpa diskon
+func taxLine(productID int, subtotal int64) entity.TransactionDetail {
+	return entity.TransactionDetail{
+		ProductID: &productID,
+		Quantity:  entity.Qty(1),
+		Harga:     entity.NewMoney(subtotal, "IDR"),
+		Subtotal:  entity.NewMoney(subtotal, "IDR"),
+		Discount:  entity.NewMoney(0, "IDR"),
+	}
+}
+
+func TestRoundRat(t *testing.T) {
+	tests := []struct {
+		num, denom int64
+		want       int64
+	}{
+		{0, 1, 0},
+		{1, 2, 1},
+		{3, 2, 2},
+		{5, 4, 1},
+		{7, 4, 2},
+		{1099, 100, 11},
+	}
+	for _, tt := range tests {
+		if got := roundRat(big.NewRat(tt.num, tt.denom)); got != tt.want {
+			t.Errorf("roundRat(%d/%d) = %d, want %d", tt.num, tt.denom, got, tt.want)
+		}
+	}
+}
+
+func TestRoundShares(t *testing.T) {
+	rats := func(values ...string) []*big.Rat {
+		shares := make([]*big.Rat, len(values))
+		for k, v := range values {
+			shares[k], _ = new(big.Rat).SetString(v)
+		}
+		return shares
+	}
+	tests := []struct {
+		name   string
+		shares []*big.Rat
+		want   []int64
+		total  int64
+	}{
+		// Dibulatkan per baris menjadi 1 + 1 + 1 = 3, dari jumlahnya 1,65 -> 2
+		{"total rounded once", rats("0.55", "0.55", "0.55"), []int64{1, 1, 0}, 2},
+		{"remainder follows the largest fraction", rats("0.2", "0.7", "0.6", "0.5"), []int64{0, 1, 1, 0}, 2},
+		{"whole parts kept", rats("3.4", "2.4", "1.4"), []int64{4, 2, 1}, 7},
+		{"ties go to the earlier line", rats("1/3", "1/3", "1/3"), []int64{1, 0, 0}, 1},
+		{"empty shares", rats("0", "0"), []int64{0, 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, total := roundShares(tt.shares)
+			if !equalInts(got, tt.want) || total != tt.total {
+				t.Errorf("roundShares = %v (%d), want %v (%d)", got, total, tt.want, tt.total)
+			}
+		})
+	}
+}
+
+func TestApplyTaxes(t *testing.T) {
+	type wantTax struct {
+		taxable, amount int64
+	}
+	tests := []struct {
+		name  string
+		rates []entity.TaxRate
+		// products - kategori produk 1..n, 0 berarti tanpa kategori. Kategori 1 Makanan
+		// dengan sub-kategori 2 Roti.
+		products []int
+		lines    []entity.TransactionDetail
+		included []int64 // tax_included per baris
+		taxes    []wantTax
+		total    int64
+	}{
+		{
+			// Rp 11.000 sudah termasuk PB1 10% (Rp 1.000), service charge 5% dari harga jual
+			name: "inclusive and exclusive on the same line",
+			rates: []entity.TaxRate{
+				{Nama: "PB1 10%", Rate: 1000, Inclusive: true, Position: 1},
+				{Nama: "Service Charge 5%", Rate: 500, Position: 2},
+			},
+			products: []int{0},
+			lines:    []entity.TransactionDetail{taxLine(1, 1100000)},
+			included: []int64{100000},
+			taxes:    []wantTax{{1000000, 100000}, {1100000, 55000}},
+			total:    1155000,
+		},
+		{
+			// PPN 11% dari Rp 10.000 + service charge Rp 500
+			name: "compound on top of service charge",
+			rates: []entity.TaxRate{
+				{Nama: "Service Charge 5%", Rate: 500, Position: 1},
+				{Nama: "PPN 11%", Rate: 1100, Compound: true, Position: 2},
+			},
+			products: []int{0},
+			lines:    []entity.TransactionDetail{taxLine(1, 1000000)},
+			included: []int64{0},
+			taxes:    []wantTax{{1000000, 50000}, {1050000, 115500}},
+			total:    1165500,
+		},
+		{
+			// Produk 1 di Roti, sub-kategori dari Makanan yang bebas PPN: hanya kena service charge
+			name: "exempt subcategory",
+			rates: []entity.TaxRate{
+				{Nama: "Service Charge 5%", Rate: 500, Position: 1},
+				{Nama: "PPN 11%", Rate: 1100, Compound: true, Position: 2, ExemptCategoryIDs: []int{1}},
+			},
+			products: []int{2, 0},
+			lines:    []entity.TransactionDetail{taxLine(1, 1000000), taxLine(2, 1000000)},
+			included: []int64{0, 0},
+			taxes:    []wantTax{{2000000, 100000}, {1050000, 115500}},
+			total:    2215500,
+		},
+		{
+			// 3 × 5 sen × 10/110 = 1,36 sen -> 1 sen untuk baris pertama, bukan 0 per baris
+			name: "inclusive remainder spread across lines",
+			rates: []entity.TaxRate{
+				{Nama: "PB1 10%", Rate: 1000, Inclusive: true, Position: 1},
+			},
+			products: []int{0, 0, 0},
+			lines:    []entity.TransactionDetail{taxLine(1, 5), taxLine(2, 5), taxLine(3, 5)},
+			included: []int64{1, 0, 0},
+			taxes:    []wantTax{{14, 1}},
+			total:    15,
+		},
+		{
+			// 4 × 5 sen × 11% = 2,2 sen -> 2 sen, bukan 1 sen per baris
+			name: "exclusive remainder spread across lines",
+			rates: []entity.TaxRate{
+				{Nama: "PPN 11%", Rate: 1100, Position: 1},
+			},
+			products: []int{0, 0, 0, 0},
+			lines:    []entity.TransactionDetail{taxLine(1, 5), taxLine(2, 5), taxLine(3, 5), taxLine(4, 5)},
+			included: []int64{0, 0, 0, 0},
+			taxes:    []wantTax{{20, 2}},
+			total:    22,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := newTestRepos()
+			createTaxFixture(t, repos, tt.products, tt.rates)
+
+			txn := entity.Transaction{Details: tt.lines, DiscountAmount: entity.NewMoney(0, "IDR")}
+			txn.Subtotal = entity.NewMoney(0, "IDR")
+			for _, d := range tt.lines {
+				txn.Subtotal.Amount += d.Subtotal.Amount
+			}
+			if err := applyTaxes(repos, &txn); err != nil {
+				t.Fatal(err)
+			}
+
+			included := make([]int64, len(txn.Details))
+			for i, d := range txn.Details {
+				included[i] = d.TaxIncluded.Amount
+			}
+			if !equalInts(included, tt.included) {
+				t.Errorf("tax_included = %v, want %v", included, tt.included)
+			}
+			if len(txn.Taxes) != len(tt.taxes) {
+				t.Fatalf("taxes = %+v, want %d", txn.Taxes, len(tt.taxes))
+			}
+			for k, want := range tt.taxes {
+				got := txn.Taxes[k]
+				if got.TaxableAmount.Amount != want.taxable || got.Amount.Amount != want.amount {
+					t.Errorf("%s = %d of %d, want %d of %d", got.Nama, got.Amount.Amount, got.TaxableAmount.Amount, want.amount, want.taxable)
+				}
+			}
+			if txn.TotalAmount.Amount != tt.total {
+				t.Errorf("total = %d, want %d", txn.TotalAmount.Amount, tt.total)
+			}
+		})
+	}
+}
+
+// createTaxFixture - kategori Makanan > Roti, produk dengan kategori categories[i] dan tarif pajak
+func createTaxFixture(t *testing.T, repos repository.Repositories, categories []int, rates []entity.TaxRate) {
+	t.Helper()
+	makanan, err := repos.Category.Create(entity.Category{Name: "Makanan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Category.Create(entity.Category{Name: "Roti", ParentID: &makanan.ID}); err != nil {
+		t.Fatal(err)
+	}
+	for i, categoryID := range categories {
+		product := entity.Product{Nama: fmt.Sprintf("Produk %d", i+1), Harga: entity.IDR(1000)}
+		if categoryID != 0 {
+			product.CategoryID = intPtr(categoryID)
+		}
+		if _, err := repos.Product.Create(product); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, rate := range rates {
+		if _, err := repos.TaxRate.Create(rate); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
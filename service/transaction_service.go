@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"strings"
+	"time"
+)
+
+// Errors for checkout
+var (
+	ErrEmptyCart        = errors.New("cart must contain at least one item")
+	ErrInvalidQuantity  = errors.New("quantity must be greater than zero")
+	ErrQuantityTooLarge = fmt.Errorf("quantity must not exceed %s", maxItemQuantity)
+	ErrMixedCurrency    = errors.New("all items in one transaction must use the same currency")
+)
+
+// maxItemQuantity - batas quantity per baris keranjang
+var maxItemQuantity = entity.Qty(100000)
+
+// CartItemError - baris keranjang yang tidak valid, Index dimulai dari 0
+type CartItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *CartItemError) Error() string {
+	return fmt.Sprintf("items[%d]: %v", e.Index, e.Err)
+}
+
+func (e *CartItemError) Unwrap() error {
+	return e.Err
+}
+
+// TransactionServiceInterface - interface untuk transaction service
+type TransactionServiceInterface interface {
+	Checkout(req entity.CheckoutRequest) (entity.Transaction, error)
+	Price(req entity.CheckoutRequest) (entity.CartPrice, error)
+}
+
+// TransactionService - struct untuk transaction service
+type TransactionService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewTransactionService - constructor untuk TransactionService
+func NewTransactionService(txManager repository.TxManagerInterface) *TransactionService {
+	return &TransactionService{txManager: txManager}
+}
+
+// Checkout - simpan penjualan dalam satu transaksi database. Nama, harga jual yang berlaku
+// saat penjualan dan harga beli produk disalin ke setiap baris agar laporan tidak berubah
+// saat produk diedit.
+// Promosi yang berlaku dihitung otomatis, diskon per baris dan per promosi ikut disimpan.
+// Voucher dipakai setelah promosi dan pemakaiannya dicatat dalam transaksi database yang sama.
+// Pajak dihitung terakhir dari harga setelah semua diskon.
+func (s *TransactionService) Checkout(req entity.CheckoutRequest) (entity.Transaction, error) {
+	if len(req.Items) == 0 {
+		return entity.Transaction{}, ErrEmptyCart
+	}
+
+	var created entity.Transaction
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		transaction, err := buildCart(repos, req.Items, currentTime(), deductStock)
+		if err != nil {
+			return err
+		}
+
+		var voucher entity.Voucher
+		var voucherDiscount entity.Money
+		if strings.TrimSpace(req.VoucherCode) != "" {
+			voucher, voucherDiscount, err = applyVoucher(repos, &transaction, req.VoucherCode, req.Customer)
+			if err != nil {
+				return err
+			}
+		}
+		if err := applyTaxes(repos, &transaction); err != nil {
+			return err
+		}
+
+		if created, err = repos.Transaction.Create(transaction); err != nil {
+			return err
+		}
+		if voucher.ID == 0 {
+			return nil
+		}
+		_, err = repos.Voucher.Redeem(entity.VoucherRedemption{
+			VoucherID:     voucher.ID,
+			TransactionID: created.ID,
+			Customer:      strings.TrimSpace(req.Customer),
+			Amount:        voucherDiscount,
+			CreatedAt:     created.CreatedAt,
+		})
+		return err
+	})
+	return created, err
+}
+
+// Price - hitung keranjang persis seperti Checkout (harga produk saat ini, promosi, voucher
+// dan pajak) hanya dengan pembacaan: tidak ada transaksi yang disimpan, stok yang dikurangi
+// atau pemakaian voucher yang dicatat. Stok yang kurang dilaporkan di StockShortages.
+func (s *TransactionService) Price(req entity.CheckoutRequest) (entity.CartPrice, error) {
+	if len(req.Items) == 0 {
+		return entity.CartPrice{}, ErrEmptyCart
+	}
+
+	var priced entity.CartPrice
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		stock := newStockCheck()
+		transaction, err := buildCart(repos, req.Items, currentTime(), stock.take)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.VoucherCode) != "" {
+			if _, _, err := applyVoucher(repos, &transaction, req.VoucherCode, req.Customer); err != nil {
+				return err
+			}
+		}
+		if err := applyTaxes(repos, &transaction); err != nil {
+			return err
+		}
+		priced = cartPrice(transaction, stock.shortages)
+		return nil
+	})
+	return priced, err
+}
+
+// cartPrice - transaksi yang belum disimpan sebagai CartPrice
+func cartPrice(t entity.Transaction, shortages []entity.StockShortage) entity.CartPrice {
+	price := entity.CartPrice{
+		Subtotal:       t.Subtotal,
+		DiscountAmount: t.DiscountAmount,
+		TaxAmount:      t.TaxAmount,
+		TotalAmount:    t.TotalAmount,
+		Details:        make([]entity.CartLine, len(t.Details)),
+		Discounts:      t.Discounts,
+		Taxes:          t.Taxes,
+		StockShortages: shortages,
+	}
+	for i, d := range t.Details {
+		price.Details[i] = entity.CartLine{
+			ProductID:    *d.ProductID,
+			VariantID:    d.VariantID,
+			NamaProduk:   d.NamaProduk,
+			Harga:        d.Harga,
+			Quantity:     d.Quantity,
+			Unit:         d.Unit,
+			BaseQuantity: d.BaseQuantity,
+			Subtotal:     d.Subtotal,
+			Discount:     d.Discount,
+			TaxIncluded:  d.TaxIncluded,
+			Modifiers:    d.Modifiers,
+		}
+	}
+	return price
+}
+
+// buildCart - baris penjualan untuk items beserta diskon promosi yang berlaku pada at.
+// Stok setiap baris diteruskan ke stock: deductStock untuk checkout (jalankan di dalam
+// WithinTx), stockCheck.take untuk menghitung harga saja (cukup WithinReadTx).
+func buildCart(repos repository.Repositories, items []entity.CheckoutItem, at time.Time, stock stockFunc) (entity.Transaction, error) {
+	if len(items) == 0 {
+		return entity.Transaction{}, ErrEmptyCart
+	}
+
+	transaction := entity.Transaction{CreatedAt: at}
+	for i, item := range items {
+		if item.Quantity <= 0 {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: ErrInvalidQuantity}
+		}
+		if item.Quantity > maxItemQuantity {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: ErrQuantityTooLarge}
+		}
+		product, err := repos.Product.GetByID(item.ProductID)
+		if err != nil {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: err}
+		}
+
+		line, err := checkoutLine(repos, product, item, transaction.CreatedAt, stock)
+		if err != nil {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: err}
+		}
+
+		unit, err := lineUnitPrice(line)
+		if err != nil {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: err}
+		}
+		subtotal, err := item.Quantity.Price(unit)
+		if err != nil {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: err}
+		}
+		total, err := transaction.TotalAmount.Add(subtotal)
+		if errors.Is(err, entity.ErrCurrencyMismatch) {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: ErrMixedCurrency}
+		}
+		if err != nil {
+			return entity.Transaction{}, &CartItemError{Index: i, Err: err}
+		}
+		transaction.TotalAmount = total
+
+		line.Subtotal = subtotal
+		transaction.Details = append(transaction.Details, line)
+	}
+
+	if err := applyPromotions(repos, &transaction); err != nil {
+		return entity.Transaction{}, err
+	}
+	return transaction, nil
+}
+
+// checkoutLine - baris penjualan tanpa subtotal. Quantity dalam satuan jual item.Unit dikonversi
+// ke satuan dasar untuk stok; harga dan harga beli (per satuan dasar) dikali faktor satuannya.
+// Produk dengan varian wajib memilih varian: harga diambil dari varian dan stoknya yang dipakai.
+// Modifier yang dipilih disalin ke baris penjualan.
+func checkoutLine(repos repository.Repositories, product entity.Product, item entity.CheckoutItem, at time.Time, stock stockFunc) (entity.TransactionDetail, error) {
+	base, factor, err := toBaseQuantity(repos, product, item.Quantity, item.Unit)
+	if err != nil {
+		return entity.TransactionDetail{}, err
+	}
+
+	productID := product.ID
+	line := entity.TransactionDetail{
+		ProductID:    &productID,
+		NamaProduk:   product.Nama,
+		HargaBeli:    product.HargaBeli,
+		Quantity:     item.Quantity,
+		Unit:         product.Unit,
+		BaseQuantity: base,
+	}
+	if item.Unit != "" {
+		line.Unit = strings.ToLower(strings.TrimSpace(item.Unit))
+	}
+
+	modifiers, err := selectModifiers(repos, product, item.Modifiers)
+	if err != nil {
+		return entity.TransactionDetail{}, err
+	}
+	if len(modifiers) > 0 {
+		line.Modifiers = modifiers
+	}
+
+	switch {
+	case product.IsBundle():
+		if item.VariantID != nil {
+			return entity.TransactionDetail{}, repository.ErrVariantNotFound
+		}
+		line, err = bundleLine(repos, product, line, at, stock)
+	case item.VariantID == nil:
+		line, err = singleLine(repos, product, line, at, stock)
+	default:
+		line, err = variantLine(repos, product, *item.VariantID, line, stock)
+	}
+	if err != nil {
+		return entity.TransactionDetail{}, err
+	}
+	return priceForUnit(line, factor)
+}
+
+// singleLine - produk tanpa varian, stok produk dipakai jika dilacak
+func singleLine(repos repository.Repositories, product entity.Product, line entity.TransactionDetail, at time.Time, stock stockFunc) (entity.TransactionDetail, error) {
+	variants, err := repos.Variant.GetByProduct(product.ID)
+	if err != nil {
+		return entity.TransactionDetail{}, err
+	}
+	if len(variants) > 0 {
+		return entity.TransactionDetail{}, ErrVariantRequired
+	}
+	if err := stock(repos, product, nil, line.BaseQuantity); err != nil {
+		return entity.TransactionDetail{}, err
+	}
+	line.Harga, err = effectivePrice(repos, product, at)
+	return line, err
+}
+
+// variantLine - varian produk dengan harga dan harga beli varian, stok varian dalam satuan
+// dasar produk seperti stok produk. Harga beli produk tidak dipakai: varian tanpa harga beli dicatat tanpa harga
+// pokok dan tidak ikut dihitung margin.
+func variantLine(repos repository.Repositories, product entity.Product, variantID int, line entity.TransactionDetail, stock stockFunc) (entity.TransactionDetail, error) {
+	variant, err := variantOf(repos, product.ID, variantID)
+	if err != nil {
+		return entity.TransactionDetail{}, err
+	}
+	if err := stock(repos, product, &variant.ID, line.BaseQuantity); err != nil {
+		return entity.TransactionDetail{}, err
+	}
+	line.VariantID = &variant.ID
+	line.NamaProduk = truncateRunes(fmt.Sprintf("%s (%s)", product.Nama, variant.Nama), 100)
+	line.Harga = variant.Harga
+	line.HargaBeli = variant.HargaBeli
+	return line, nil
+}
+
+// bundleLine - baris penjualan bundle dengan harga paket. Stok setiap komponen dipakai
+// sebanyak quantity komponen × jumlah paket. Tanpa harga pokok sendiri, harga pokok paket
+// adalah jumlah harga pokok komponennya (tidak diketahui jika ada komponen tanpa harga pokok).
+// Komponen varian memakai harga pokok variannya.
+func bundleLine(repos repository.Repositories, bundle entity.Product, line entity.TransactionDetail, at time.Time, stock stockFunc) (entity.TransactionDetail, error) {
+	if !line.BaseQuantity.IsWhole() {
+		return entity.TransactionDetail{}, ErrFractionalQuantity
+	}
+	components, err := repos.Bundle.GetComponents(bundle.ID)
+	if err != nil {
+		return entity.TransactionDetail{}, err
+	}
+
+	cost := entity.NewMoney(0, bundle.Harga.Cur())
+	costKnown := true
+	for _, c := range components {
+		product, err := repos.Product.GetByID(c.ProductID)
+		if err != nil {
+			return entity.TransactionDetail{}, err
+		}
+		quantity := entity.Qty(int64(c.Quantity) * line.BaseQuantity.Units())
+		if err := stock(repos, product, c.VariantID, quantity); err != nil {
+			return entity.TransactionDetail{}, fmt.Errorf("%s: %w", c.Nama, err)
+		}
+		unitCost := product.HargaBeli
+		if c.VariantID != nil {
+			variant, err := repos.Variant.GetByID(*c.VariantID)
+			if err != nil {
+				return entity.TransactionDetail{}, err
+			}
+			unitCost = variant.HargaBeli
+		}
+		if unitCost == nil {
+			costKnown = false
+			continue
+		}
+		componentCost, err := unitCost.Mul(int64(c.Quantity))
+		if err == nil {
+			cost, err = cost.Add(componentCost)
+		}
+		if err != nil {
+			costKnown = false
+		}
+	}
+	if line.HargaBeli == nil && costKnown {
+		line.HargaBeli = &cost
+	}
+
+	line.Harga, err = effectivePrice(repos, bundle, at)
+	return line, err
+}
+
+// priceForUnit - harga dan harga beli per satuan dasar menjadi per satuan jual, 1 dus = 24 × harga pcs
+func priceForUnit(line entity.TransactionDetail, factor entity.Quantity) (entity.TransactionDetail, error) {
+	if factor == entity.Qty(1) {
+		return line, nil
+	}
+	var err error
+	if line.Harga, err = factor.Price(line.Harga); err != nil {
+		return entity.TransactionDetail{}, err
+	}
+	if line.HargaBeli != nil {
+		cost, err := factor.Price(*line.HargaBeli)
+		if err != nil {
+			return entity.TransactionDetail{}, err
+		}
+		line.HargaBeli = &cost
+	}
+	return line, nil
+}
+
+// stockFunc - pemakaian stok satu baris (atau komponen bundle) keranjang
+type stockFunc func(repos repository.Repositories, product entity.Product, variantID *int, quantity entity.Quantity) error
+
+// deductStock - kurangi stok varian, atau stok produk jika dilacak (dalam satuan dasar)
+func deductStock(repos repository.Repositories, product entity.Product, variantID *int, quantity entity.Quantity) error {
+	if variantID != nil {
+		_, err := repos.Variant.AdjustStock(*variantID, -quantity)
+		return err
+	}
+	if product.Stock == nil {
+		return nil
+	}
+	_, err := repos.Product.AdjustStock(product.ID, -quantity)
+	return err
+}
+
+// stockKey - stok produk (variantID 0) atau stok varian
+type stockKey struct {
+	productID int
+	variantID int
+}
+
+// stockCheck - bandingkan kebutuhan keranjang dengan stok saat ini tanpa mengubahnya.
+// Kebutuhan dijumlahkan per produk/varian, termasuk komponen bundle; stok yang kurang
+// dicatat di shortages, bukan dikembalikan sebagai error.
+type stockCheck struct {
+	demand    map[stockKey]entity.Quantity
+	index     map[stockKey]int // posisi di shortages
+	shortages []entity.StockShortage
+}
+
+func newStockCheck() *stockCheck {
+	return &stockCheck{demand: map[stockKey]entity.Quantity{}, index: map[stockKey]int{}}
+}
+
+// take - stockFunc yang hanya membaca stok
+func (c *stockCheck) take(repos repository.Repositories, product entity.Product, variantID *int, quantity entity.Quantity) error {
+	key := stockKey{productID: product.ID}
+	var available entity.Quantity
+	if variantID != nil {
+		variant, err := repos.Variant.GetByID(*variantID)
+		if err != nil {
+			return err
+		}
+		key.variantID = variant.ID
+		available = *variant.Stock
+	} else if product.Stock != nil {
+		available = *product.Stock
+	} else {
+		return nil
+	}
+
+	c.demand[key] += quantity
+	if c.demand[key] <= available {
+		return nil
+	}
+	shortage := entity.StockShortage{ProductID: product.ID, VariantID: variantID, Requested: c.demand[key], Available: available}
+	if k, ok := c.index[key]; ok {
+		c.shortages[k] = shortage
+		return nil
+	}
+	c.index[key] = len(c.shortages)
+	c.shortages = append(c.shortages, shortage)
+	return nil
+}
+
+// lineUnitPrice - harga satu unit termasuk semua modifier yang dipilih
+func lineUnitPrice(line entity.TransactionDetail) (entity.Money, error) {
+	unit := line.Harga
+	for _, m := range line.Modifiers {
+		var err error
+		unit, err = unit.Add(m.Harga)
+		if errors.Is(err, entity.ErrCurrencyMismatch) {
+			return entity.Money{}, ErrMixedCurrency
+		}
+		if err != nil {
+			return entity.Money{}, err
+		}
+	}
+	return unit, nil
+}
+
+// truncateRunes - potong s menjadi paling banyak n karakter (kolom VARCHAR(n))
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
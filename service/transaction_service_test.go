@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+func TestPriceIsReadOnly(t *testing.T) {
+	repos, _, transactions := voucherFixture(t, entity.Voucher{
+		Code: "SEPULUH", Nama: "Diskon 10%", Type: entity.VoucherPercentage, Percent: 1000, UsageLimit: intPtr(1), PerCustomerLimit: intPtr(1),
+	})
+
+	priced, err := transactions.Price(entity.CheckoutRequest{Items: oneKopi(2), VoucherCode: "SEPULUH", Customer: "0811"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priced.DiscountAmount != entity.IDR(2000) || priced.TotalAmount != entity.IDR(18000) {
+		t.Errorf("discount %v total %v", priced.DiscountAmount, priced.TotalAmount)
+	}
+	if len(priced.StockShortages) != 0 {
+		t.Errorf("unexpected shortages %+v", priced.StockShortages)
+	}
+
+	// 3 + 4 unit dari stok 5: keranjang tetap dihitung, kekurangannya dijumlah per produk
+	items := []entity.CheckoutItem{{ProductID: 1, Quantity: entity.Qty(3)}, {ProductID: 1, Quantity: entity.Qty(4)}}
+	priced, err = transactions.Price(entity.CheckoutRequest{Items: items, VoucherCode: "SEPULUH", Customer: "0811"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priced.TotalAmount != entity.IDR(63000) {
+		t.Errorf("total %v", priced.TotalAmount)
+	}
+	want := entity.StockShortage{ProductID: 1, Requested: entity.Qty(7), Available: entity.Qty(5)}
+	if len(priced.StockShortages) != 1 || priced.StockShortages[0] != want {
+		t.Errorf("shortages = %+v, want %+v", priced.StockShortages, want)
+	}
+	if _, err := transactions.Checkout(entity.CheckoutRequest{Items: items}); err == nil {
+		t.Error("checkout of the short cart should fail")
+	}
+
+	product, err := repos.Product.GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *product.Stock != entity.Qty(5) {
+		t.Errorf("stock changed to %v", *product.Stock)
+	}
+	voucher, err := repos.Voucher.GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if voucher.UsedCount != 0 {
+		t.Errorf("used_count changed to %d", voucher.UsedCount)
+	}
+	if used, err := repos.Voucher.CountRedemptions(1, "0811"); err != nil || used != 0 {
+		t.Errorf("redemptions for customer = %d, %v", used, err)
+	}
+	if _, err := repos.Transaction.GetByID(1); !errors.Is(err, repository.ErrTransactionNotFound) {
+		t.Errorf("Price stored a transaction: %v", err)
+	}
+}
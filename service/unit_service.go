@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+// Errors for units of measure
+var (
+	ErrInvalidUnitCode    = errors.New("unit code must be 1-10 lower case letters or digits")
+	ErrUnitNameRequired   = errors.New("unit nama is required")
+	ErrUnitNameTooLong    = errors.New("unit nama must be at most 50 characters")
+	ErrUnknownUnit        = errors.New("unit does not exist")
+	ErrInvalidConversion  = errors.New("unit conversion must use another unit with a factor greater than zero")
+	ErrDuplicateUnit      = errors.New("unit appears more than once in units")
+	ErrInvalidPurchase    = errors.New("purchase_unit must be the product unit or one of its units")
+	ErrUnitNotAvailable   = errors.New("unit is not available for this product")
+	ErrFractionalQuantity = errors.New("quantity must be a whole number for this unit")
+)
+
+// UnitServiceInterface - interface untuk unit service
+type UnitServiceInterface interface {
+	GetAllUnits() ([]entity.Unit, error)
+	CreateUnit(unit entity.Unit) (entity.Unit, error)
+	UpdateUnit(code string, unit entity.Unit) (entity.Unit, error)
+	DeleteUnit(code string) error
+}
+
+// UnitService - struct untuk unit service
+type UnitService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewUnitService - constructor untuk UnitService
+func NewUnitService(txManager repository.TxManagerInterface) *UnitService {
+	return &UnitService{txManager: txManager}
+}
+
+// GetAllUnits - semua satuan
+func (s *UnitService) GetAllUnits() ([]entity.Unit, error) {
+	var units []entity.Unit
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		units, err = repos.Unit.GetAll()
+		return err
+	})
+	if units == nil && err == nil {
+		units = []entity.Unit{}
+	}
+	return units, err
+}
+
+// CreateUnit - tambah satuan baru, kode disimpan dalam huruf kecil
+func (s *UnitService) CreateUnit(unit entity.Unit) (entity.Unit, error) {
+	unit.Code = strings.ToLower(strings.TrimSpace(unit.Code))
+	if !validUnitCode(unit.Code) {
+		return entity.Unit{}, ErrInvalidUnitCode
+	}
+	if err := validateUnit(&unit); err != nil {
+		return entity.Unit{}, err
+	}
+
+	var created entity.Unit
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		created, err = repos.Unit.Create(unit)
+		return err
+	})
+	return created, err
+}
+
+// UpdateUnit - ubah nama dan izin desimal satuan. Jumlah desimal yang sudah tersimpan tidak diubah.
+func (s *UnitService) UpdateUnit(code string, unit entity.Unit) (entity.Unit, error) {
+	if err := validateUnit(&unit); err != nil {
+		return entity.Unit{}, err
+	}
+
+	var updated entity.Unit
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		updated, err = repos.Unit.Update(strings.ToLower(code), unit)
+		return err
+	})
+	return updated, err
+}
+
+// DeleteUnit - hapus satuan yang tidak dipakai produk mana pun
+func (s *UnitService) DeleteUnit(code string) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		return repos.Unit.Delete(strings.ToLower(code))
+	})
+}
+
+// validUnitCode - 1-10 huruf kecil atau angka
+func validUnitCode(code string) bool {
+	if code == "" || len(code) > 10 {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateUnit - nama wajib diisi dan muat di kolom VARCHAR(50)
+func validateUnit(unit *entity.Unit) error {
+	unit.Nama = strings.TrimSpace(unit.Nama)
+	if unit.Nama == "" {
+		return ErrUnitNameRequired
+	}
+	if utf8.RuneCountInString(unit.Nama) > 50 {
+		return ErrUnitNameTooLong
+	}
+	return nil
+}
+
+// checkProductUnits - satuan dasar, satuan beli dan semua konversi produk memakai satuan yang ada,
+// stok desimal hanya untuk satuan dasar yang mengizinkan desimal
+func checkProductUnits(repos repository.Repositories, product entity.Product) error {
+	base, err := unitByCode(repos, product.Unit)
+	if err != nil {
+		return err
+	}
+	if product.Stock != nil && !product.Stock.IsWhole() && !base.AllowDecimal {
+		return fmt.Errorf("stock: %w", ErrFractionalQuantity)
+	}
+
+	seen := map[string]bool{product.Unit: true}
+	for i, c := range product.Units {
+		if c.Unit == product.Unit || c.Factor <= 0 {
+			return fmt.Errorf("units[%d]: %w", i, ErrInvalidConversion)
+		}
+		if seen[c.Unit] {
+			return fmt.Errorf("units[%d]: %w", i, ErrDuplicateUnit)
+		}
+		if _, err := unitByCode(repos, c.Unit); err != nil {
+			return fmt.Errorf("units[%d]: %w", i, err)
+		}
+		seen[c.Unit] = true
+	}
+
+	if product.PurchaseUnit != "" && !seen[product.PurchaseUnit] {
+		return ErrInvalidPurchase
+	}
+	return nil
+}
+
+// unitByCode - satuan dengan kode tersebut, ErrUnknownUnit jika tidak ada
+func unitByCode(repos repository.Repositories, code string) (entity.Unit, error) {
+	unit, err := repos.Unit.GetByCode(code)
+	if errors.Is(err, repository.ErrUnitNotFound) {
+		return entity.Unit{}, fmt.Errorf("%w: %q", ErrUnknownUnit, code)
+	}
+	return unit, err
+}
+
+// normalizeProductUnits - kode satuan dalam huruf kecil seperti yang tersimpan
+func normalizeProductUnits(product *entity.Product) {
+	product.Unit = strings.ToLower(strings.TrimSpace(product.Unit))
+	product.PurchaseUnit = strings.ToLower(strings.TrimSpace(product.PurchaseUnit))
+	for i := range product.Units {
+		product.Units[i].Unit = strings.ToLower(strings.TrimSpace(product.Units[i].Unit))
+	}
+}
+
+// setProductUnits - simpan satuan lain produk dan isi Units seperti saat dibaca
+func setProductUnits(repos repository.Repositories, product *entity.Product, conversions []entity.UnitConversion) error {
+	if err := repos.Unit.SetConversions(product.ID, conversions); err != nil {
+		return err
+	}
+	var err error
+	product.Units, err = repos.Unit.GetConversions(product.ID)
+	return err
+}
+
+// toBaseQuantity - quantity dalam satuan code (kosong = satuan dasar produk) diubah ke satuan
+// dasar, misal 2 dus = 48 pcs. Mengembalikan juga faktor satuan tersebut. Jumlah desimal hanya
+// boleh jika satuan yang dipakai dan satuan dasarnya sama-sama mengizinkan desimal.
+func toBaseQuantity(repos repository.Repositories, product entity.Product, quantity entity.Quantity, code string) (base, factor entity.Quantity, err error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		code = product.Unit
+	}
+
+	factor = entity.Qty(1)
+	if code != product.Unit {
+		conversions, err := repos.Unit.GetConversions(product.ID)
+		if err != nil {
+			return 0, 0, err
+		}
+		factor = 0
+		for _, c := range conversions {
+			if c.Unit == code {
+				factor = c.Factor
+			}
+		}
+		if factor == 0 {
+			return 0, 0, fmt.Errorf("%w: %q", ErrUnitNotAvailable, code)
+		}
+	}
+
+	unit, err := unitByCode(repos, code)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !quantity.IsWhole() && !unit.AllowDecimal {
+		return 0, 0, ErrFractionalQuantity
+	}
+	base, ok := quantity.Convert(factor)
+	if !ok {
+		return 0, 0, ErrInvalidQuantity
+	}
+	if !base.IsWhole() {
+		baseUnit, err := unitByCode(repos, product.Unit)
+		if err != nil {
+			return 0, 0, err
+		}
+		if !baseUnit.AllowDecimal {
+			return 0, 0, ErrFractionalQuantity
+		}
+	}
+	return base, factor, nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"strings"
+)
+
+// Errors for product variants
+var (
+	ErrSKURequired             = errors.New("sku is required")
+	ErrVariantNameRequired     = errors.New("variant nama is required")
+	ErrNegativeStock           = errors.New("stock must not be negative")
+	ErrVariantCurrencyMismatch = errors.New("variant harga must use the same currency as the product")
+	ErrVariantRequired         = errors.New("product has variants, variant_id is required")
+)
+
+// VariantServiceInterface - interface untuk variant service
+type VariantServiceInterface interface {
+	GetVariants(productID int) ([]entity.ProductVariant, error)
+	CreateVariant(productID int, variant entity.ProductVariant) (entity.ProductVariant, error)
+	UpdateVariant(productID, variantID int, variant entity.ProductVariant) (entity.ProductVariant, error)
+	DeleteVariant(productID, variantID int) error
+	AdjustStock(productID, variantID int, delta entity.Quantity, unit string) (entity.ProductVariant, error)
+}
+
+// VariantService - struct untuk variant service
+type VariantService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewVariantService - constructor untuk VariantService
+func NewVariantService(txManager repository.TxManagerInterface) *VariantService {
+	return &VariantService{txManager: txManager}
+}
+
+// GetVariants - semua varian produk
+func (s *VariantService) GetVariants(productID int) ([]entity.ProductVariant, error) {
+	var variants []entity.ProductVariant
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := repos.Product.GetByID(productID); err != nil {
+			return err
+		}
+		var err error
+		variants, err = repos.Variant.GetByProduct(productID)
+		return err
+	})
+	if variants == nil && err == nil {
+		variants = []entity.ProductVariant{}
+	}
+	return variants, err
+}
+
+// CreateVariant - tambah varian ke produk
+func (s *VariantService) CreateVariant(productID int, variant entity.ProductVariant) (entity.ProductVariant, error) {
+	if err := validateVariant(&variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	var created entity.ProductVariant
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(productID)
+		if err != nil {
+			return err
+		}
+		if product.IsBundle() {
+			return ErrBundleVariants
+		}
+		if variant.Harga.Cur() != product.Harga.Cur() {
+			return ErrVariantCurrencyMismatch
+		}
+		if err := checkVariantStock(repos, product, variant); err != nil {
+			return err
+		}
+
+		variant.ProductID = productID
+		created, err = repos.Variant.Create(variant)
+		return err
+	})
+	return created, err
+}
+
+// UpdateVariant - update SKU, nama, options, harga, harga beli dan stok varian.
+// Stok yang tidak dikirim tetap seperti sebelumnya.
+func (s *VariantService) UpdateVariant(productID, variantID int, variant entity.ProductVariant) (entity.ProductVariant, error) {
+	if err := validateVariant(&variant); err != nil {
+		return entity.ProductVariant{}, err
+	}
+
+	var updated entity.ProductVariant
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(productID)
+		if err != nil {
+			return err
+		}
+		current, err := variantOf(repos, productID, variantID)
+		if err != nil {
+			return err
+		}
+		if variant.Harga.Cur() != product.Harga.Cur() {
+			return ErrVariantCurrencyMismatch
+		}
+		// Harga beli yang tidak dikirim (misal oleh kasir) tetap seperti sebelumnya,
+		// "harga_beli": null dari manager menghapusnya
+		if variant.HargaBeli == nil && !variant.ClearHargaBeli && current.Harga.Cur() == variant.Harga.Cur() {
+			variant.HargaBeli = current.HargaBeli
+		}
+		if variant.Stock == nil {
+			variant.Stock = current.Stock
+		}
+		if err := checkVariantStock(repos, product, variant); err != nil {
+			return err
+		}
+
+		updated, err = repos.Variant.Update(variantID, variant)
+		return err
+	})
+	return updated, err
+}
+
+// DeleteVariant - hapus varian, riwayat penjualan tetap ada.
+// Varian yang masih menjadi komponen bundle tidak bisa dihapus.
+func (s *VariantService) DeleteVariant(productID, variantID int) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		if _, err := variantOf(repos, productID, variantID); err != nil {
+			return err
+		}
+		inBundle, err := repos.Bundle.IsComponent(productID, &variantID)
+		if err != nil {
+			return err
+		}
+		if inBundle {
+			return ErrProductInBundle
+		}
+		return repos.Variant.Delete(variantID)
+	})
+}
+
+// AdjustStock - tambah (restock) atau kurangi (koreksi) stok varian secara atomik.
+// Seperti stok produk, delta dalam satuan unit (kosong berarti satuan beli produk) dan
+// dikonversi ke satuan dasar produk: varian produk per kg bisa direstock 0,5 kg.
+func (s *VariantService) AdjustStock(productID, variantID int, delta entity.Quantity, unit string) (entity.ProductVariant, error) {
+	var adjusted entity.ProductVariant
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		product, err := repos.Product.GetByID(productID)
+		if err != nil {
+			return err
+		}
+		if _, err := variantOf(repos, productID, variantID); err != nil {
+			return err
+		}
+		if unit == "" {
+			unit = product.PurchaseUnit
+		}
+		base, _, err := toBaseQuantity(repos, product, delta, unit)
+		if err != nil {
+			return err
+		}
+		adjusted, err = repos.Variant.AdjustStock(variantID, base)
+		return err
+	})
+	return adjusted, err
+}
+
+// variantOf - varian milik produk, ErrVariantNotFound jika milik produk lain
+func variantOf(repos repository.Repositories, productID, variantID int) (entity.ProductVariant, error) {
+	variant, err := repos.Variant.GetByID(variantID)
+	if err != nil {
+		return entity.ProductVariant{}, err
+	}
+	if variant.ProductID != productID {
+		return entity.ProductVariant{}, repository.ErrVariantNotFound
+	}
+	return variant, nil
+}
+
+// checkVariantStock - stok varian dalam satuan dasar produk, desimal hanya jika satuannya mengizinkan
+func checkVariantStock(repos repository.Repositories, product entity.Product, variant entity.ProductVariant) error {
+	if variant.Stock == nil || variant.Stock.IsWhole() {
+		return nil
+	}
+	base, err := unitByCode(repos, product.Unit)
+	if err != nil {
+		return err
+	}
+	if !base.AllowDecimal {
+		return fmt.Errorf("stock: %w", ErrFractionalQuantity)
+	}
+	return nil
+}
+
+// validateVariant - SKU dan nama wajib, harga dan stok tidak negatif
+func validateVariant(variant *entity.ProductVariant) error {
+	variant.SKU = strings.TrimSpace(variant.SKU)
+	variant.Nama = strings.TrimSpace(variant.Nama)
+	switch {
+	case variant.SKU == "":
+		return ErrSKURequired
+	case variant.Nama == "":
+		return ErrVariantNameRequired
+	case variant.Harga.IsNegative(), variant.HargaBeli != nil && variant.HargaBeli.IsNegative():
+		return ErrNegativeHarga
+	case variant.HargaBeli != nil && variant.HargaBeli.Currency != "" && variant.HargaBeli.Cur() != variant.Harga.Cur():
+		return ErrCostCurrencyMismatch
+	case variant.Stock != nil && *variant.Stock < 0:
+		return ErrNegativeStock
+	}
+	return nil
+}
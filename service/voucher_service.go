@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"kasir-api/entity"
+	"kasir-api/repository"
+	"regexp"
+	"strings"
+)
+
+// Errors for vouchers
+var (
+	ErrVoucherCode             = errors.New("voucher code must be 3-32 letters, digits, - or _")
+	ErrVoucherType             = errors.New("type must be percentage or fixed")
+	ErrVoucherPercent          = errors.New("percentage voucher needs percent greater than 0 and at most 100")
+	ErrVoucherAmount           = errors.New("fixed voucher needs amount greater than zero")
+	ErrVoucherMaxDiscount      = errors.New("max_discount must be greater than zero and only applies to percentage vouchers")
+	ErrVoucherLimit            = errors.New("usage_limit and per_customer_limit must be greater than zero")
+	ErrVoucherLimitBelowUsed   = errors.New("usage_limit must not be lower than used_count")
+	ErrVoucherRange            = errors.New("expires_at must be after starts_at")
+	ErrVoucherNotStarted       = errors.New("voucher is not valid yet")
+	ErrVoucherExpired          = errors.New("voucher has expired")
+	ErrVoucherMinSubtotal      = errors.New("cart total is below the voucher minimum")
+	ErrVoucherCustomerRequired = errors.New("customer is required for this voucher")
+)
+
+// voucherCodePattern - kode voucher setelah diubah ke huruf besar
+var voucherCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// VoucherServiceInterface - interface untuk voucher service
+type VoucherServiceInterface interface {
+	GetAll() ([]entity.Voucher, error)
+	GetByID(id int) (entity.Voucher, error)
+	Create(voucher entity.Voucher) (entity.Voucher, error)
+	Update(id int, voucher entity.Voucher) (entity.Voucher, error)
+	Delete(id int) error
+	Validate(req entity.VoucherValidateRequest) (entity.VoucherPreview, error)
+}
+
+// VoucherService - struct untuk voucher service
+type VoucherService struct {
+	txManager repository.TxManagerInterface
+}
+
+// NewVoucherService - constructor untuk VoucherService
+func NewVoucherService(txManager repository.TxManagerInterface) *VoucherService {
+	return &VoucherService{txManager: txManager}
+}
+
+// GetAll - semua voucher beserta jumlah pemakaiannya
+func (s *VoucherService) GetAll() ([]entity.Voucher, error) {
+	var vouchers []entity.Voucher
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		vouchers, err = repos.Voucher.GetAll()
+		return err
+	})
+	if vouchers == nil && err == nil {
+		vouchers = []entity.Voucher{}
+	}
+	return vouchers, err
+}
+
+// GetByID - ambil voucher berdasarkan ID
+func (s *VoucherService) GetByID(id int) (entity.Voucher, error) {
+	var voucher entity.Voucher
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		voucher, err = repos.Voucher.GetByID(id)
+		return err
+	})
+	return voucher, err
+}
+
+// Create - tambah voucher baru
+func (s *VoucherService) Create(voucher entity.Voucher) (entity.Voucher, error) {
+	if err := validateVoucher(&voucher); err != nil {
+		return entity.Voucher{}, err
+	}
+
+	var created entity.Voucher
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		var err error
+		created, err = repos.Voucher.Create(voucher)
+		return err
+	})
+	return created, err
+}
+
+// Update - ubah voucher. Kuota tidak boleh diturunkan di bawah pemakaian yang sudah terjadi.
+func (s *VoucherService) Update(id int, voucher entity.Voucher) (entity.Voucher, error) {
+	if err := validateVoucher(&voucher); err != nil {
+		return entity.Voucher{}, err
+	}
+
+	var updated entity.Voucher
+	err := s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		current, err := repos.Voucher.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if voucher.UsageLimit != nil && *voucher.UsageLimit < current.UsedCount {
+			return ErrVoucherLimitBelowUsed
+		}
+		updated, err = repos.Voucher.Update(id, voucher)
+		return err
+	})
+	return updated, err
+}
+
+// Delete - hapus voucher, potongan di transaksi lama tetap ada
+func (s *VoucherService) Delete(id int) error {
+	return s.txManager.WithinTx(context.Background(), func(repos repository.Repositories) error {
+		return repos.Voucher.Delete(id)
+	})
+}
+
+// Validate - hitung potongan voucher untuk keranjang tanpa menyimpan transaksi atau
+// mencatat pemakaian, hanya dengan pembacaan. Stok tidak dicek di sini. Kuota dicek
+// terhadap pemakaian saat ini; checkout tetap bisa ditolak jika kuota habis oleh kasir
+// lain di antaranya.
+func (s *VoucherService) Validate(req entity.VoucherValidateRequest) (entity.VoucherPreview, error) {
+	if strings.TrimSpace(req.Code) == "" {
+		return entity.VoucherPreview{}, repository.ErrVoucherNotFound
+	}
+
+	var preview entity.VoucherPreview
+	err := s.txManager.WithinReadTx(context.Background(), func(repos repository.Repositories) error {
+		transaction, err := buildCart(repos, req.Items, currentTime(), newStockCheck().take)
+		if err != nil {
+			return err
+		}
+		promotionDiscount := transaction.DiscountAmount
+		voucher, discount, err := applyVoucher(repos, &transaction, req.Code, req.Customer)
+		if err != nil {
+			return err
+		}
+		if err := applyTaxes(repos, &transaction); err != nil {
+			return err
+		}
+		preview = entity.VoucherPreview{
+			Code:              voucher.Code,
+			Nama:              voucher.Nama,
+			Subtotal:          transaction.Subtotal,
+			PromotionDiscount: promotionDiscount,
+			Discount:          discount,
+			TaxAmount:         transaction.TaxAmount,
+			TotalAmount:       transaction.TotalAmount,
+		}
+		return nil
+	})
+	return preview, err
+}
+
+// normalizeVoucherCode - kode voucher tidak membedakan huruf besar dan kecil
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validateVoucher - cek aturan voucher dan lengkapi nama. Field yang tidak dipakai jenisnya
+// dikosongkan. UsedCount diabaikan karena hanya bertambah lewat checkout.
+func validateVoucher(v *entity.Voucher) error {
+	v.Code = normalizeVoucherCode(v.Code)
+	if !voucherCodePattern.MatchString(v.Code) {
+		return ErrVoucherCode
+	}
+	v.Nama = strings.TrimSpace(v.Nama)
+	if v.Nama == "" {
+		v.Nama = "Voucher " + v.Code
+	}
+
+	switch v.Type {
+	case entity.VoucherPercentage:
+		if v.Percent <= 0 || v.Percent > 10000 {
+			return ErrVoucherPercent
+		}
+		if v.MaxDiscount != nil && v.MaxDiscount.Amount <= 0 {
+			return ErrVoucherMaxDiscount
+		}
+		v.Amount = nil
+	case entity.VoucherFixed:
+		if v.Amount == nil || v.Amount.Amount <= 0 {
+			return ErrVoucherAmount
+		}
+		if v.MaxDiscount != nil {
+			return ErrVoucherMaxDiscount
+		}
+		v.Percent = 0
+	default:
+		return ErrVoucherType
+	}
+	if v.MinSubtotal != nil && v.MinSubtotal.IsNegative() {
+		return ErrNegativeHarga
+	}
+
+	currency := ""
+	for _, m := range []*entity.Money{v.Amount, v.MaxDiscount, v.MinSubtotal} {
+		if m == nil {
+			continue
+		}
+		if currency != "" && m.Cur() != currency {
+			return ErrMixedCurrency
+		}
+		currency = m.Cur()
+	}
+
+	if (v.UsageLimit != nil && *v.UsageLimit <= 0) || (v.PerCustomerLimit != nil && *v.PerCustomerLimit <= 0) {
+		return ErrVoucherLimit
+	}
+	if v.StartsAt != nil && v.ExpiresAt != nil && !v.ExpiresAt.After(*v.StartsAt) {
+		return ErrVoucherRange
+	}
+	v.UsedCount = 0
+	return nil
+}
+
+// applyVoucher - potongan voucher dari total transaksi setelah promosi. Potongan dibagi ke
+// baris sebanding sisa harganya dan dicatat sebagai baris diskon; totalnya diperbarui.
+// Kuota dicek terhadap pemakaian saat ini, pencatatan atomik dilakukan oleh Redeem.
+func applyVoucher(repos repository.Repositories, t *entity.Transaction, code, customer string) (entity.Voucher, entity.Money, error) {
+	v, err := repos.Voucher.GetByCode(normalizeVoucherCode(code))
+	if err != nil {
+		return entity.Voucher{}, entity.Money{}, err
+	}
+	if v.StartsAt != nil && t.CreatedAt.Before(*v.StartsAt) {
+		return entity.Voucher{}, entity.Money{}, ErrVoucherNotStarted
+	}
+	if v.ExpiresAt != nil && !t.CreatedAt.Before(*v.ExpiresAt) {
+		return entity.Voucher{}, entity.Money{}, ErrVoucherExpired
+	}
+	if v.UsageLimit != nil && v.UsedCount >= *v.UsageLimit {
+		return entity.Voucher{}, entity.Money{}, repository.ErrVoucherUsageLimit
+	}
+	customer = strings.TrimSpace(customer)
+	if v.PerCustomerLimit != nil {
+		if customer == "" {
+			return entity.Voucher{}, entity.Money{}, ErrVoucherCustomerRequired
+		}
+		used, err := repos.Voucher.CountRedemptions(v.ID, customer)
+		if err != nil {
+			return entity.Voucher{}, entity.Money{}, err
+		}
+		if used >= *v.PerCustomerLimit {
+			return entity.Voucher{}, entity.Money{}, repository.ErrVoucherCustomerLimit
+		}
+	}
+
+	base := t.TotalAmount
+	for _, m := range []*entity.Money{v.Amount, v.MaxDiscount, v.MinSubtotal} {
+		if m != nil && m.Cur() != base.Cur() {
+			return entity.Voucher{}, entity.Money{}, ErrMixedCurrency
+		}
+	}
+	if v.MinSubtotal != nil && base.Amount < v.MinSubtotal.Amount {
+		return entity.Voucher{}, entity.Money{}, ErrVoucherMinSubtotal
+	}
+
+	var off entity.Money
+	if v.Type == entity.VoucherPercentage {
+		if off, err = v.Percent.Of(base); err != nil {
+			return entity.Voucher{}, entity.Money{}, err
+		}
+		if v.MaxDiscount != nil {
+			off = minMoney(off, *v.MaxDiscount)
+		}
+	} else {
+		off = minMoney(*v.Amount, base)
+	}
+
+	remaining := make([]entity.Money, len(t.Details))
+	for i, d := range t.Details {
+		if remaining[i], err = d.Subtotal.Sub(d.Discount); err != nil {
+			return entity.Voucher{}, entity.Money{}, err
+		}
+	}
+	amounts, err := allocateDiscount(off, remaining)
+	if err != nil {
+		return entity.Voucher{}, entity.Money{}, err
+	}
+	for i := range t.Details {
+		if t.Details[i].Discount, err = t.Details[i].Discount.Add(amounts[i]); err != nil {
+			return entity.Voucher{}, entity.Money{}, err
+		}
+	}
+
+	id := v.ID
+	t.Discounts = append(t.Discounts, entity.TransactionDiscount{VoucherID: &id, Nama: v.Nama, Amount: off})
+	if t.DiscountAmount, err = t.DiscountAmount.Add(off); err != nil {
+		return entity.Voucher{}, entity.Money{}, err
+	}
+	if t.TotalAmount, err = t.Subtotal.Sub(t.DiscountAmount); err != nil {
+		return entity.Voucher{}, entity.Money{}, err
+	}
+	return v, off, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"kasir-api/entity"
+	"kasir-api/repository"
+)
+
+// voucherFixture - produk Rp 10.000 dengan stok 5 dan voucher v, dibuat di store baru
+func voucherFixture(t *testing.T, v entity.Voucher) (repository.Repositories, *VoucherService, *TransactionService) {
+	t.Helper()
+	repos, txManager := newTestStore()
+	stock := entity.Qty(5)
+	if _, err := repos.Product.Create(entity.Product{Nama: "Kopi", Harga: entity.IDR(10000), Stock: &stock}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Voucher.Create(v); err != nil {
+		t.Fatal(err)
+	}
+	return repos, NewVoucherService(txManager), NewTransactionService(txManager)
+}
+
+func oneKopi(quantity int64) []entity.CheckoutItem {
+	return []entity.CheckoutItem{{ProductID: 1, Quantity: entity.Qty(quantity)}}
+}
+
+func TestVoucherPerCustomerLimit(t *testing.T) {
+	amount := entity.IDR(2000)
+	_, vouchers, transactions := voucherFixture(t, entity.Voucher{
+		Code: "HEMAT", Nama: "Hemat 2rb", Type: entity.VoucherFixed, Amount: &amount, PerCustomerLimit: intPtr(1),
+	})
+
+	checkout := func(customer string) error {
+		_, err := transactions.Checkout(entity.CheckoutRequest{Items: oneKopi(1), VoucherCode: "hemat", Customer: customer})
+		return err
+	}
+	if err := checkout("0811"); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		customer string
+		want     error
+	}{
+		{"same customer again", "0811", repository.ErrVoucherCustomerLimit},
+		{"same customer with spaces", " 0811 ", repository.ErrVoucherCustomerLimit},
+		{"without customer", "", ErrVoucherCustomerRequired},
+		{"another customer", "0822", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vouchers.Validate(entity.VoucherValidateRequest{Code: "HEMAT", Customer: tt.customer, Items: oneKopi(1)})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Validate error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := checkout("0811"); !errors.Is(err, repository.ErrVoucherCustomerLimit) {
+		t.Fatalf("second checkout by the same customer: %v", err)
+	}
+	if err := checkout("0822"); err != nil {
+		t.Fatalf("another customer: %v", err)
+	}
+	voucher, err := vouchers.GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if voucher.UsedCount != 2 {
+		t.Errorf("used_count = %d, want 2", voucher.UsedCount)
+	}
+}
+
+func TestVoucherValidityWindow(t *testing.T) {
+	now := currentTime()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	amount := entity.IDR(2000)
+
+	tests := []struct {
+		name              string
+		startsAt, expires *time.Time
+		want              error
+	}{
+		{"expired", nil, &past, ErrVoucherExpired},
+		{"not started", &future, nil, ErrVoucherNotStarted},
+		{"within window", &past, &future, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, vouchers, transactions := voucherFixture(t, entity.Voucher{
+				Code: "WAKTU", Nama: "Berwaktu", Type: entity.VoucherFixed, Amount: &amount, StartsAt: tt.startsAt, ExpiresAt: tt.expires,
+			})
+			if _, err := vouchers.Validate(entity.VoucherValidateRequest{Code: "WAKTU", Items: oneKopi(1)}); !errors.Is(err, tt.want) {
+				t.Errorf("Validate error = %v, want %v", err, tt.want)
+			}
+			if _, err := transactions.Checkout(entity.CheckoutRequest{Items: oneKopi(1), VoucherCode: "WAKTU"}); !errors.Is(err, tt.want) {
+				t.Errorf("Checkout error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVoucherValidateIsReadOnly(t *testing.T) {
+	repos, vouchers, _ := voucherFixture(t, entity.Voucher{
+		Code: "SEPULUH", Nama: "Diskon 10%", Type: entity.VoucherPercentage, Percent: 1000, UsageLimit: intPtr(1), PerCustomerLimit: intPtr(1),
+	})
+
+	// Validate tidak mengecek stok: 8 unit dari stok 5 tetap mendapat pratinjau
+	for _, quantity := range []int64{2, 8} {
+		preview, err := vouchers.Validate(entity.VoucherValidateRequest{Code: "SEPULUH", Customer: "0811", Items: oneKopi(quantity)})
+		if err != nil {
+			t.Fatalf("Validate %d: %v", quantity, err)
+		}
+		want := entity.IDR(1000 * quantity)
+		if preview.Discount != want || preview.TotalAmount != entity.IDR(10000*quantity-1000*quantity) {
+			t.Errorf("Validate %d: discount %v total %v", quantity, preview.Discount, preview.TotalAmount)
+		}
+	}
+
+	product, err := repos.Product.GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *product.Stock != entity.Qty(5) {
+		t.Errorf("stock changed to %v", *product.Stock)
+	}
+	voucher, err := repos.Voucher.GetByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if voucher.UsedCount != 0 {
+		t.Errorf("used_count changed to %d", voucher.UsedCount)
+	}
+	if used, err := repos.Voucher.CountRedemptions(1, "0811"); err != nil || used != 0 {
+		t.Errorf("redemptions for customer = %d, %v", used, err)
+	}
+	if _, err := repos.Transaction.GetByID(1); !errors.Is(err, repository.ErrTransactionNotFound) {
+		t.Errorf("Validate stored a transaction: %v", err)
+	}
+}
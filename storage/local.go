@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage - file disimpan di direktori lokal dan dilayani oleh Handler
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage - constructor untuk LocalStorage. baseURL adalah prefix URL publik,
+// misal "/uploads" (dilayani aplikasi ini) atau "https://cdn.example.com/kasir".
+func NewLocalStorage(dir, baseURL string) *LocalStorage {
+	return &LocalStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Save - tulis file ke direktori lokal. File ditulis ke file sementara lalu di-rename
+// agar pembaca tidak pernah melihat file setengah jadi.
+func (s *LocalStorage) Save(key, contentType string, data []byte) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Delete - hapus file, file yang sudah tidak ada diabaikan
+func (s *LocalStorage) Delete(key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL - baseURL + "/" + key
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Handler - layani file di direktori lokal tanpa daftar isi direktori.
+// Pasang dengan http.StripPrefix sesuai path baseURL.
+func (s *LocalStorage) Handler() http.Handler {
+	files := http.FileServer(http.Dir(s.dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		files.ServeHTTP(w, r)
+	})
+}
+
+// path - lokasi file untuk key di dalam dir
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,33 @@
+// Package storage menyimpan file upload (gambar produk) di luar database.
+// Service hanya bergantung pada FileStorageInterface sehingga filesystem lokal
+// bisa diganti object storage tanpa mengubah service.
+package storage
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// ErrInvalidKey - key kosong, absolut atau keluar dari root storage (misal "../x")
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// FileStorageInterface - interface untuk penyimpanan file.
+// Key adalah path relatif dengan pemisah "/", misal "products/7/ab12.jpg".
+type FileStorageInterface interface {
+	Save(key, contentType string, data []byte) error
+	Delete(key string) error // key yang tidak ada bukan error
+	URL(key string) string   // URL publik untuk key
+}
+
+// CleanKey - key yang aman dipakai sebagai path, ErrInvalidKey jika tidak
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}